@@ -58,42 +58,309 @@ type DevicePluginSpec struct {
 	DevicePluginTolerations []v1.Toleration `json:"devicePluginTolerations,omitempty"`
 
 	// device plugin arguments is used to pass supported flags and their values while starting device plugin daemonset
-	// supported flag values: {"resource_naming_strategy": {"single", "mixed"}}
+	// supported flags: resource_naming_strategy, log_level, resource_prefix, health_check_interval, kubelet_socket
+	// deprecated: use devicePluginFlags instead, the typed flags take precedence over the arguments
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DevicePluginArguments",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:devicePluginArguments"}
 	// +optional
 	DevicePluginArguments map[string]string `json:"devicePluginArguments,omitempty"`
 
+	// flags passed to the device plugin container on start
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DevicePluginFlags",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:devicePluginFlags"}
+	// +optional
+	DevicePluginFlags DevicePluginFlagsSpec `json:"devicePluginFlags,omitempty"`
+
+	// discovery of the NIC to GPU topology affinity and allocation of the NICs aligned with the GPUs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="GPUAffinity",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:gpuAffinity"}
+	// +optional
+	GPUAffinity GPUAffinitySpec `json:"gpuAffinity,omitempty"`
+
+	// resource pools advertised by the device plugin, rendered into the device plugin ConfigMap of the NetworkConfig
+	// if not specified, the nic (device 1002) and vnic (device 1003) pools of AMD NICs are advertised
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ResourcePools",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:resourcePools"}
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ResourcePools []ResourcePoolSpec `json:"resourcePools,omitempty"`
+
+	// enable or disable the device plugin, the NICs can be allocated by the DRA driver instead
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="EnableDevicePlugin",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:enableDevicePlugin"}
+	// +kubebuilder:default=true
+	// +optional
+	EnableDevicePlugin *bool `json:"enableDevicePlugin,omitempty"`
+
 	// node labeller image
+	// Deprecated: use nodeLabeller.image, which takes precedence when set
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NodeLabellerImage",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerImage"}
 	// +optional
 	// +kubebuilder:validation:Pattern=`^([a-z0-9]+(?:[._-][a-z0-9]+)*(:[0-9]+)?)(/[a-z0-9]+(?:[._-][a-z0-9]+)*)*(?::[a-z0-9._-]+)?(?:@[a-zA-Z0-9]+:[a-f0-9]+)?$`
 	NodeLabellerImage string `json:"nodeLabellerImage,omitempty"`
 
 	// image pull policy for node labeller
+	// Deprecated: use nodeLabeller.imagePullPolicy, which takes precedence when set
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NodeLabellerImagePullPolicy",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:NodeLabellerImagePullPolicy"}
 	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
 	// +optional
 	NodeLabellerImagePullPolicy string `json:"nodeLabellerImagePullPolicy,omitempty"`
 
 	// tolerations for the node labeller DaemonSet
+	// Deprecated: use nodeLabeller.tolerations, which take precedence when set
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NodeLabellerTolerations",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerTolerations"}
 	// +optional
 	NodeLabellerTolerations []v1.Toleration `json:"nodeLabellerTolerations,omitempty"`
 
-	// node labeller image registry secret used to pull/push images
+	// device plugin and node labeller image registry secret used to pull/push images
+	// the node labeller uses nodeLabeller.imageRegistrySecret instead when set
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImageRegistrySecret",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:imageRegistrySecret"}
 	// +optional
 	ImageRegistrySecret *v1.LocalObjectReference `json:"imageRegistrySecret,omitempty"`
 
 	// enable or disable the node labeller
+	// Deprecated: use nodeLabeller.enable, which takes precedence when set
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="EnableNodeLabeller",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:enableNodeLabeller"}
 	// +kubebuilder:default=true
 	EnableNodeLabeller *bool `json:"enableNodeLabeller,omitempty"`
 
+	// label families published by the node labeller, all of them by default
+	// Deprecated: use nodeLabeller.labels, which take precedence when set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NodeLabellerLabels",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerLabels"}
+	// +kubebuilder:validation:items:Enum=nicModel;firmwareVersion;driverVersion;ports;pcieGeneration;numaNode;rail;vfCount
+	// +optional
+	NodeLabellerLabels []string `json:"nodeLabellerLabels,omitempty"`
+
 	// upgrade policy for device plugin and node labeller daemons
+	// the node labeller uses nodeLabeller.upgradePolicy instead when set
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="UpgradePolicy",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:upgradePolicy"}
 	// +optional
 	UpgradePolicy *DaemonSetUpgradeSpec `json:"upgradePolicy,omitempty"`
+
+	// pod customization of the device plugin, merged over commonConfig.podCustomization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PodCustomization",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:podCustomization"}
+	// +optional
+	PodCustomization *PodCustomizationOverrideSpec `json:"podCustomization,omitempty"`
+}
+
+// NodeLabellerSpec describes the node labeller publishing the properties of the AMD NICs as node labels
+// unset fields fall back to the deprecated node labeller fields of devicePlugin
+type NodeLabellerSpec struct {
+	// enable or disable the node labeller
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerEnable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// node labeller image
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerImage"}
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]+(?:[._-][a-z0-9]+)*(:[0-9]+)?)(/[a-z0-9]+(?:[._-][a-z0-9]+)*)*(?::[a-z0-9._-]+)?(?:@[a-zA-Z0-9]+:[a-f0-9]+)?$`
+	Image string `json:"image,omitempty"`
+
+	// image pull policy for node labeller
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImagePullPolicy",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerImagePullPolicy"}
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// image registry secret used to pull the node labeller image
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImageRegistrySecret",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerImageRegistrySecret"}
+	// +optional
+	ImageRegistrySecret *v1.LocalObjectReference `json:"imageRegistrySecret,omitempty"`
+
+	// Selector describes on which nodes to enable the node labeller, same as spec.selector by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Selector",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerSelector"}
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+
+	// tolerations for the node labeller DaemonSet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tolerations",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerTolerations"}
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// compute resources of the node labeller container
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resources",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerResources"}
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// upgrade policy for the node labeller daemons
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="UpgradePolicy",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerUpgradePolicy"}
+	// +optional
+	UpgradePolicy *DaemonSetUpgradeSpec `json:"upgradePolicy,omitempty"`
+
+	// label families published by the node labeller, all of them by default
+	// the labels of a family removed from the list are cleaned up from the nodes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Labels",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerLabels"}
+	// +kubebuilder:validation:items:Enum=nicModel;firmwareVersion;driverVersion;ports;pcieGeneration;numaNode;rail;vfCount
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// pod customization of the node labeller, merged over commonConfig.podCustomization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PodCustomization",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeLabellerPodCustomization"}
+	// +optional
+	PodCustomization *PodCustomizationOverrideSpec `json:"podCustomization,omitempty"`
+}
+
+// DevicePluginFlagsSpec describes the flags supported by the device plugin, unset flags use the device plugin defaults
+type DevicePluginFlagsSpec struct {
+	// resource naming strategy, single advertises all the NICs as one resource, mixed advertises one resource per NIC type
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ResourceNamingStrategy",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:resourceNamingStrategy"}
+	// +kubebuilder:validation:Enum=single;mixed
+	// +optional
+	ResourceNamingStrategy string `json:"resourceNamingStrategy,omitempty"`
+
+	// log level of the device plugin
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="LogLevel",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:devicePluginLogLevel"}
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// resource prefix of the resource pools which don't specify a prefix, amd.com by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ResourcePrefix",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:devicePluginResourcePrefix"}
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +optional
+	ResourcePrefix string `json:"resourcePrefix,omitempty"`
+
+	// interval in seconds at which the device plugin checks the health of the devices
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="HealthCheckIntervalSeconds",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:healthCheckIntervalSeconds"}
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HealthCheckIntervalSeconds int32 `json:"healthCheckIntervalSeconds,omitempty"`
+
+	// path of the kubelet device plugin socket on the host, /var/lib/kubelet/device-plugins/kubelet.sock by default
+	// the directory of the socket is mounted into the device plugin container at the same path
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="KubeletSocketPath",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:kubeletSocketPath"}
+	// +kubebuilder:validation:Pattern=`^/.+\.sock$`
+	// +optional
+	KubeletSocketPath string `json:"kubeletSocketPath,omitempty"`
+}
+
+// GPUAffinitySpec describes the NIC to GPU topology affinity handling
+type GPUAffinitySpec struct {
+	// discover the PCIe topology of the NICs and GPUs on the selected nodes, publish the NIC to GPU pairs
+	// in the <NetworkConfig name>-nic-gpu-topology ConfigMap and the node affinity in the network.operator.amd.com/nic-gpu-affinity label
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:gpuAffinityEnable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// allocation policy of the device plugin, pcie-switch and numa prefer the NICs aligned at that level with the GPUs allocated to the same pod
+	// none only publishes the topology, the other policies require a device plugin supporting the gpu_affinity flag
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="AllocationPolicy",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:gpuAffinityAllocationPolicy"}
+	// +kubebuilder:validation:Enum=none;numa;pcie-switch
+	// +kubebuilder:default=none
+	// +optional
+	AllocationPolicy string `json:"allocationPolicy,omitempty"`
+}
+
+// ResourcePoolSpec describes a pool of NIC devices advertised as one extended resource
+type ResourcePoolSpec struct {
+	// name of the resource, e.g. nic makes the devices requestable as amd.com/nic
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:resourcePoolName"}
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// prefix of the resource name, defaults to the resourcePrefix device plugin flag or amd.com
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Prefix",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:resourcePoolPrefix"}
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// selectors of the devices in the pool, a device must match all the specified selectors
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Selectors",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:resourcePoolSelectors"}
+	// +optional
+	Selectors ResourcePoolSelectors `json:"selectors,omitempty"`
+
+	// exclude the NUMA topology of the devices from the resource advertisement
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ExcludeTopology",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:excludeTopology"}
+	// +optional
+	ExcludeTopology bool `json:"excludeTopology,omitempty"`
+
+	// withdraw the devices reported unhealthy by the metrics exporter from the allocatable resources
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="EnableExporterHealthCheck",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:enableExporterHealthCheck"}
+	// +kubebuilder:default=true
+	// +optional
+	EnableExporterHealthCheck *bool `json:"enableExporterHealthCheck,omitempty"`
+}
+
+// ResourcePoolSelectors selects the devices of a resource pool
+type ResourcePoolSelectors struct {
+	// PCI vendor IDs in hex, e.g. 1dd8
+	// +optional
+	Vendors []string `json:"vendors,omitempty"`
+
+	// PCI device IDs in hex, e.g. 1002
+	// +optional
+	Devices []string `json:"devices,omitempty"`
+
+	// PCI subsystem device IDs in hex
+	// +optional
+	SubsystemDevices []string `json:"subsystemDevices,omitempty"`
+
+	// kernel drivers bound to the devices, e.g. ionic
+	// +optional
+	Drivers []string `json:"drivers,omitempty"`
+
+	// physical function interface names, e.g. enp1s0f0 or enp1s0f0#0-3 to select a range of VFs
+	// +optional
+	PFNames []string `json:"pfNames,omitempty"`
+
+	// select only the RDMA capable devices
+	// +optional
+	IsRdma bool `json:"isRdma,omitempty"`
+}
+
+// PodCustomizationSpec customizes the pods of all the operands, unset fields keep the operator defaults
+type PodCustomizationSpec struct {
+	// compute resources of the main container of the pods, the init and sidecar containers keep their own resources
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resources",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:resources"}
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// priority class of the pods, system-node-critical by default for the DaemonSets
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PriorityClassName",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:priorityClassName"}
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// pod level security context, the containers keep their own security context
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SecurityContext",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:securityContext"}
+	// +optional
+	SecurityContext *v1.PodSecurityContext `json:"securityContext,omitempty"`
+
+	// extra environment variables of the containers, they override the variables of the same name set by the operator
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Env",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:env"}
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
+
+	// node affinity of the pods, in addition to the node selector. Ignored by the pods bound to a node, e.g. upgrade workers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NodeAffinity",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:nodeAffinity"}
+	// +optional
+	NodeAffinity *v1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// extra annotations of the pods
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Annotations",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:annotations"}
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PodCustomizationOverrideSpec overrides the common pod customization for the pods of an operand
+// the security context and node affinity are only set in commonConfig.podCustomization to keep the CRD small
+type PodCustomizationOverrideSpec struct {
+	// compute resources of the main container of the pods, the init and sidecar containers keep their own resources
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resources",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:resources"}
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// priority class of the pods
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PriorityClassName",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:priorityClassName"}
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// extra environment variables of the containers, merged by name over the common ones
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Env",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:env"}
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
+
+	// extra annotations of the pods, merged over the common ones
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Annotations",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:annotations"}
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 type DaemonSetUpgradeSpec struct {
@@ -131,6 +398,21 @@ type NetworkConfigSpec struct {
 	// +optional
 	DevicePlugin DevicePluginSpec `json:"devicePlugin,omitempty"`
 
+	// host config, kernel module options, blacklist and sysctls applied on the selected nodes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="HostConfig",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfig"}
+	// +optional
+	HostConfig HostConfigSpec `json:"hostConfig,omitempty"`
+
+	// node labeller
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NodeLabeller",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:nodeLabeller"}
+	// +optional
+	NodeLabeller NodeLabellerSpec `json:"nodeLabeller,omitempty"`
+
+	// Dynamic Resource Allocation (DRA) driver of the AMD NICs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DRADriver",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriver"}
+	// +optional
+	DRADriver DRADriverSpec `json:"draDriver,omitempty"`
+
 	// test runner
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TestRunner",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:testRunner"}
 	// +optional
@@ -146,12 +428,186 @@ type NetworkConfigSpec struct {
 	// SecondaryNetworkSpec contains the spec for secondary network: CNI plugins and IPAM
 	SecondaryNetwork SecondaryNetworkSpec `json:"secondaryNetwork,omitempty"`
 
+	// node readiness, published as the AMDNetworkReady node condition
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NodeReadiness",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:nodeReadiness"}
+	// +optional
+	NodeReadiness NodeReadinessSpec `json:"nodeReadiness,omitempty"`
+
+	// remediation of the nodes whose NICs are reported unhealthy
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Remediation",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:remediation"}
+	// +optional
+	Remediation RemediationSpec `json:"remediation,omitempty"`
+
 	// Selector describes on which nodes the Network Operator should enable the Network device.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Selector",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:selector"}
 	// +optional
 	Selector map[string]string `json:"selector,omitempty"`
 }
 
+// HostConfigSpec describes the kernel module options, blacklist and sysctls applied on the selected nodes
+// they are written by the host config agent DaemonSet, or rendered into a MachineConfig on OpenShift
+type HostConfigSpec struct {
+	// enable the host config, disabled by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigEnable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// host config agent image, not used on OpenShift
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigImage"}
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]+(?:[._-][a-z0-9]+)*(:[0-9]+)?)(/[a-z0-9]+(?:[._-][a-z0-9]+)*)*(?::[a-z0-9._-]+)?(?:@[a-zA-Z0-9]+:[a-f0-9]+)?$`
+	Image string `json:"image,omitempty"`
+
+	// image pull policy for host config agent
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImagePullPolicy",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigImagePullPolicy"}
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// image registry secret used to pull the host config agent image
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImageRegistrySecret",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigImageRegistrySecret"}
+	// +optional
+	ImageRegistrySecret *v1.LocalObjectReference `json:"imageRegistrySecret,omitempty"`
+
+	// tolerations for the host config agent DaemonSet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tolerations",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigTolerations"}
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// kernel module options written to /etc/modprobe.d, they take effect the next time the module is loaded
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ModprobeOptions",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigModprobeOptions"}
+	// +optional
+	ModprobeOptions []ModprobeOptionsSpec `json:"modprobeOptions,omitempty"`
+
+	// kernel modules blacklisted in /etc/modprobe.d, the ionic inbox driver is also blacklisted when driver.blacklist is set
+	// node reboot is required to unload a blacklisted module
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Blacklist",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigBlacklist"}
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_-]+$`
+	// +optional
+	Blacklist []string `json:"blacklist,omitempty"`
+
+	// sysctls written to /etc/sysctl.d and applied on the nodes, e.g. net.ipv4.conf.all.arp_ignore for RoCE
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Sysctls",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigSysctls"}
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+
+	// MachineConfigPool the MachineConfig is rendered for on OpenShift, worker by default
+	// applying a MachineConfig reboots the nodes of the pool
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="MachineConfigPool",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigMachineConfigPool"}
+	// +kubebuilder:default=worker
+	// +optional
+	MachineConfigPool string `json:"machineConfigPool,omitempty"`
+
+	// pod customization of the host config agent, merged over commonConfig.podCustomization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PodCustomization",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:hostConfigPodCustomization"}
+	// +optional
+	PodCustomization *PodCustomizationOverrideSpec `json:"podCustomization,omitempty"`
+}
+
+// ModprobeOptionsSpec describes the options of an AMD NIC kernel module
+type ModprobeOptionsSpec struct {
+	// kernel module name
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Module",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:modprobeModule"}
+	// +kubebuilder:validation:Enum=ionic;ionic_rdma
+	Module string `json:"module"`
+
+	// module parameters in the name=value form
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Options",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:modprobeOptions"}
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_]+=[^\s]+$`
+	Options []string `json:"options"`
+}
+
+// DRADriverSpec describes the DRA kubelet plugin publishing the AMD NICs in ResourceSlices
+type DRADriverSpec struct {
+	// enable the DRA driver, disabled by default
+	// it can run alongside the device plugin, or replace it with devicePlugin.enableDevicePlugin set to false
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverEnable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// DRA driver image
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverImage"}
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]+(?:[._-][a-z0-9]+)*(:[0-9]+)?)(/[a-z0-9]+(?:[._-][a-z0-9]+)*)*(?::[a-z0-9._-]+)?(?:@[a-zA-Z0-9]+:[a-f0-9]+)?$`
+	Image string `json:"image,omitempty"`
+
+	// image pull policy for DRA driver
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImagePullPolicy",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverImagePullPolicy"}
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// image registry secret used to pull the DRA driver image
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImageRegistrySecret",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverImageRegistrySecret"}
+	// +optional
+	ImageRegistrySecret *v1.LocalObjectReference `json:"imageRegistrySecret,omitempty"`
+
+	// tolerations for the DRA driver DaemonSet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tolerations",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverTolerations"}
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// upgrade policy for the DRA driver DaemonSet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="UpgradePolicy",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverUpgradePolicy"}
+	// +optional
+	UpgradePolicy *DaemonSetUpgradeSpec `json:"upgradePolicy,omitempty"`
+
+	// device attributes published in the ResourceSlices besides the function type (pf or vf), all of them by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Attributes",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverAttributes"}
+	// +kubebuilder:validation:items:Enum=pciAddress;pfName;numaNode;linkSpeed;firmwareVersion;rail
+	// +listType=set
+	// +optional
+	Attributes []string `json:"attributes,omitempty"`
+
+	// rails published as the rail attribute of the NICs, a NIC not in any rail doesn't have the attribute
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rails",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverRails"}
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Rails []RailSpec `json:"rails,omitempty"`
+
+	// DeviceClasses managed by the operator
+	// if not specified, the nic.amd.com (physical functions) and vnic.amd.com (virtual functions) classes are created
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DeviceClasses",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverDeviceClasses"}
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	DeviceClasses []DeviceClassSpec `json:"deviceClasses,omitempty"`
+
+	// pod customization of the DRA driver, merged over commonConfig.podCustomization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PodCustomization",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:draDriverPodCustomization"}
+	// +optional
+	PodCustomization *PodCustomizationOverrideSpec `json:"podCustomization,omitempty"`
+}
+
+// RailSpec assigns a rail to the NICs of its physical functions
+type RailSpec struct {
+	// name of the rail, published as the rail attribute
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:railName"}
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_.-]+$`
+	// +kubebuilder:validation:MaxLength=64
+	Name string `json:"name"`
+
+	// physical function names of the NICs in the rail on every node
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PFNames",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:railPFNames"}
+	// +kubebuilder:validation:MinItems=1
+	PFNames []string `json:"pfNames"`
+}
+
+// DeviceClassSpec describes a DeviceClass of the AMD NICs
+type DeviceClassSpec struct {
+	// name of the DeviceClass
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:deviceClassName"}
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+
+	// CEL expressions selecting the devices of the class among the AMD NICs, a device must match all of them
+	// e.g. device.attributes["nic.amd.com"].linkSpeed >= 400
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Selectors",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:deviceClassSelectors"}
+	// +optional
+	Selectors []string `json:"selectors,omitempty"`
+}
+
 type CniPluginsSpec struct {
 	// enable CNI plugins, disabled by default
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:enable"}
@@ -184,11 +640,209 @@ type CniPluginsSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="UpgradePolicy",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:upgradePolicy"}
 	// +optional
 	UpgradePolicy *DaemonSetUpgradeSpec `json:"upgradePolicy,omitempty"`
+
+	// CNI plugins installed on the nodes, amd-host-device, host-device, sbr and tuning by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Plugins",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:cniPluginsPlugins"}
+	// +kubebuilder:validation:items:Enum=amd-host-device;host-device;sbr;tuning;rdma
+	// +optional
+	Plugins []string `json:"plugins,omitempty"`
+
+	// overwrite the CNI binaries installed by other operators, disabled by default
+	// a binary is owned by the operator when it was installed by the CNI plugins DaemonSet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OverwriteForeignBinaries",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:cniPluginsOverwriteForeignBinaries"}
+	// +optional
+	OverwriteForeignBinaries *bool `json:"overwriteForeignBinaries,omitempty"`
+
+	// pod customization of the CNI plugins, merged over commonConfig.podCustomization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PodCustomization",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:podCustomization"}
+	// +optional
+	PodCustomization *PodCustomizationOverrideSpec `json:"podCustomization,omitempty"`
 }
 
 type SecondaryNetworkSpec struct {
 	// Image information for CNI plugins
 	CniPlugins *CniPluginsSpec `json:"cniPlugins,omitempty"`
+
+	// Multus thick plugin deployed by the operator, OpenShift already ships Multus
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Multus",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multus"}
+	// +optional
+	Multus *MultusSpec `json:"multus,omitempty"`
+
+	// NetworkAttachmentDefinitions rendered and owned by the operator
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NetworkAttachments",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachments"}
+	// +optional
+	NetworkAttachments []NetworkAttachmentSpec `json:"networkAttachments,omitempty"`
+}
+
+// MultusSpec describes the Multus thick plugin DaemonSet
+type MultusSpec struct {
+	// deploy Multus, disabled by default, ignored on OpenShift where Multus is part of the cluster network
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multusEnable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// Multus thick plugin image
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multusImage"}
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]+(?:[._-][a-z0-9]+)*(:[0-9]+)?)(/[a-z0-9]+(?:[._-][a-z0-9]+)*)*(?::[a-z0-9._-]+)?(?:@[a-zA-Z0-9]+:[a-f0-9]+)?$`
+	Image string `json:"image,omitempty"`
+
+	// image pull policy for Multus
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImagePullPolicy",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multusImagePullPolicy"}
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// image registry secret used to pull the Multus image
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImageRegistrySecret",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multusImageRegistrySecret"}
+	// +optional
+	ImageRegistrySecret *v1.LocalObjectReference `json:"imageRegistrySecret,omitempty"`
+
+	// tolerations for the Multus DaemonSet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tolerations",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multusTolerations"}
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// upgrade policy for the Multus DaemonSet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="UpgradePolicy",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multusUpgradePolicy"}
+	// +optional
+	UpgradePolicy *DaemonSetUpgradeSpec `json:"upgradePolicy,omitempty"`
+
+	// secret with a kubeconfig key used by Multus to reach the API server
+	// Multus uses the credentials of its service account when not set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="KubeconfigSecret",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multusKubeconfigSecret"}
+	// +optional
+	KubeconfigSecret *v1.LocalObjectReference `json:"kubeconfigSecret,omitempty"`
+
+	// seconds the device plugin waits for the Multus CNI config before failing with an explanation, 600 by default, 0 waits forever
+	// it applies whether Multus is deployed by the operator or not
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="WaitTimeoutSeconds",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multusWaitTimeoutSeconds"}
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	WaitTimeoutSeconds *int32 `json:"waitTimeoutSeconds,omitempty"`
+
+	// pod customization of Multus, merged over commonConfig.podCustomization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PodCustomization",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:multusPodCustomization"}
+	// +optional
+	PodCustomization *PodCustomizationOverrideSpec `json:"podCustomization,omitempty"`
+}
+
+// NetworkAttachmentSpec describes a NetworkAttachmentDefinition attaching the AMD NICs with the amd-host-device CNI plugin
+type NetworkAttachmentSpec struct {
+	// name of the NetworkAttachmentDefinition and of the CNI network
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentName"}
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+
+	// namespaces the NetworkAttachmentDefinition is created in
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespaces",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentNamespaces"}
+	// +kubebuilder:validation:MinItems=1
+	Namespaces []string `json:"namespaces"`
+
+	// NIC resource allocated to the pods attached to the network, amd.com/nic by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ResourceName",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentResourceName"}
+	// +kubebuilder:validation:Enum=amd.com/nic;amd.com/vnic
+	// +kubebuilder:default=amd.com/nic
+	// +optional
+	ResourceName string `json:"resourceName,omitempty"`
+
+	// CNI plugins chained after amd-host-device, in the given order
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Plugins",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentPlugins"}
+	// +kubebuilder:validation:items:Enum=sbr;tuning;rdma
+	// +optional
+	Plugins []string `json:"plugins,omitempty"`
+
+	// tuning plugin config, used when tuning is in the plugins
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tuning",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentTuning"}
+	// +optional
+	Tuning *NetworkAttachmentTuningSpec `json:"tuning,omitempty"`
+
+	// IPAM of the attached interfaces
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="IPAM",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentIPAM"}
+	// +optional
+	IPAM NetworkAttachmentIPAMSpec `json:"ipam,omitempty"`
+}
+
+// NetworkAttachmentTuningSpec describes the tuning CNI plugin config
+type NetworkAttachmentTuningSpec struct {
+	// MTU of the attached interface
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="MTU",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentTuningMTU"}
+	// +kubebuilder:validation:Minimum=576
+	// +kubebuilder:validation:Maximum=9216
+	// +optional
+	MTU *int32 `json:"mtu,omitempty"`
+
+	// interface sysctls set in the pod network namespace
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Sysctls",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentTuningSysctls"}
+	// +optional
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+}
+
+// NetworkAttachmentIPAMSpec describes the IPAM of a NetworkAttachmentDefinition
+type NetworkAttachmentIPAMSpec struct {
+	// IPAM mode, none keeps the addresses configured on the NIC
+	// static takes the addresses from the ips of the pod network selection annotation
+	// cluster leases the addresses from the cluster-wide NICIPPools of the operator, one per rail subnet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mode",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentIPAMMode"}
+	// +kubebuilder:validation:Enum=none;host-local;static;dhcp;whereabouts;cluster
+	// +kubebuilder:default=none
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// subnet in CIDR notation the addresses are allocated from, required by host-local and whereabouts
+	// with the cluster mode it is the subnet of the NICs not listed in the rails
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Range",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentIPAMRange"}
+	// +optional
+	Range string `json:"range,omitempty"`
+
+	// first address allocated from the range
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RangeStart",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentIPAMRangeStart"}
+	// +optional
+	RangeStart string `json:"rangeStart,omitempty"`
+
+	// last address allocated from the range
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RangeEnd",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentIPAMRangeEnd"}
+	// +optional
+	RangeEnd string `json:"rangeEnd,omitempty"`
+
+	// gateway of the range
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Gateway",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentIPAMGateway"}
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// subnet of each rail, only used by the cluster mode
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rails",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentIPAMRails"}
+	// +optional
+	Rails []NetworkAttachmentRailSpec `json:"rails,omitempty"`
+}
+
+// NetworkAttachmentRailSpec describes the subnet of the NICs of a rail
+type NetworkAttachmentRailSpec struct {
+	// host interface name of the NIC of the rail on every node
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Interface",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentRailInterface"}
+	// +kubebuilder:validation:Required
+	Interface string `json:"interface"`
+
+	// subnet in CIDR notation the addresses of the rail are allocated from
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Range",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentRailRange"}
+	// +kubebuilder:validation:Required
+	Range string `json:"range"`
+
+	// first address allocated from the range
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RangeStart",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentRailRangeStart"}
+	// +optional
+	RangeStart string `json:"rangeStart,omitempty"`
+
+	// last address allocated from the range
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RangeEnd",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentRailRangeEnd"}
+	// +optional
+	RangeEnd string `json:"rangeEnd,omitempty"`
+
+	// gateway of the range
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Gateway",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:networkAttachmentRailGateway"}
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
 }
 
 type RegistryTLS struct {
@@ -210,9 +864,8 @@ type DriverSpec struct {
 	Enable *bool `json:"enable,omitempty"`
 
 	// blacklist amdnetwork drivers on the host. Node reboot is required to apply the blacklist on the worker nodes.
-	// Require spec.devicePlugin.enableNodeLabeller=true to take effect.
-	// Not working for OpenShift cluster. OpenShift users please use the Machine Config Operator (MCO) resource to configure ionic blacklist.
-	// Example MCO resource is available at https://instinct.docs.amd.com/projects/network-operator/en/latest/installation/openshift-olm.html#create-blacklist-for-installing-out-of-tree-kernel-module
+	// When hostConfig is enabled the blacklist is applied by the host config, including on OpenShift.
+	// Otherwise it requires the node labeller to be enabled to take effect and is not working for OpenShift cluster.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="BlacklistDrivers",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:blacklistDrivers"}
 	Blacklist *bool `json:"blacklist,omitempty"`
 
@@ -275,6 +928,75 @@ type DriverSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Tolerations",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:tolerations"}
 	// +optional
 	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// policy for selected nodes whose OS or kernel is not supported by the operator
+	// Fail: stop the driver rollout for the whole NetworkConfig until the node is fixed or deselected
+	// Skip: leave the unsupported nodes out of the KMM module and report the reason per node in status.nodeModuleStatus
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="UnsupportedNodePolicy",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:unsupportedNodePolicy"}
+	// +kubebuilder:default=Fail
+	// +optional
+	UnsupportedNodePolicy UnsupportedNodePolicy `json:"unsupportedNodePolicy,omitempty"`
+
+	// kernel mappings matched by regular expression
+	// a node whose kernel version matches one of the regular expressions is mapped with that regular expression instead of its literal kernel version
+	// so that new kernels of a known distro are covered without a new literal mapping per kernel
+	// regular expression mappings are not used when image signing is configured, as the files to sign are specific to each kernel
+	// a regular expression must not match the kernels of nodes running different OSes, as all of them would load the same driver image
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="KernelMappings",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:kernelMappings"}
+	// +optional
+	KernelMappings []KernelMappingSpec `json:"kernelMappings,omitempty"`
+
+	// ConfigMap with additional OS profiles to build the driver image for distros not built into the operator, e.g. SLES or Rocky
+	// each data key holds one OS profile in YAML format with the fields:
+	// name, osImageRegex, dockerfile, sourceImageDockerfile, defaultDriverVersion, baseImageRegistry and kmodsToSign
+	// user defined profiles are matched against the node OS image before the built-in Ubuntu 22.04/24.04, CoreOS and RHEL 9 profiles
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OSProfiles",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:osProfiles"}
+	// +optional
+	OSProfiles *v1.LocalObjectReference `json:"osProfiles,omitempty"`
+
+	// NOTE: for internal development and validation only, currently only for Ubuntu
+	// build the driver image from an internal dev build of the amdnetwork installer package instead of the released packages
+	// spec.driver.AMDNetworkInstallerRepoURL is ignored when dev build is specified
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DevBuild",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:devBuild"}
+	// +optional
+	DevBuild *DevBuildSpec `json:"devBuild,omitempty"`
+}
+
+// DevBuildSpec describes an internal dev build of the driver packages
+type DevBuildSpec struct {
+	// URL of the amdnetwork installer debian package, e.g. https://artifactory.example.com/amdnetwork-install_6.4.60400-1_all.deb
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="InstallerPackageURL",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:installerPackageURL"}
+	// +kubebuilder:validation:Pattern=`^https?://\S+\.deb$`
+	InstallerPackageURL string `json:"installerPackageURL"`
+
+	// build number of the ionic driver packages
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="IonicBuild",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:ionicBuild"}
+	// +kubebuilder:validation:Pattern=`^[0-9]+$`
+	IonicBuild string `json:"ionicBuild"`
+
+	// build tag of the ROCm packages, e.g. compute-rocm-rel-6.4/43
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ROCmBuild",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:rocmBuild"}
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9._/-]+$`
+	ROCmBuild string `json:"rocmBuild"`
+}
+
+// UnsupportedNodePolicy describes how to handle selected nodes whose OS or kernel is not supported
+// +kubebuilder:validation:Enum=Fail;Skip
+type UnsupportedNodePolicy string
+
+const (
+	// UnsupportedNodePolicyFail fails the whole driver rollout if any selected node is unsupported
+	UnsupportedNodePolicyFail UnsupportedNodePolicy = "Fail"
+	// UnsupportedNodePolicySkip skips unsupported nodes and reports them in status
+	UnsupportedNodePolicySkip UnsupportedNodePolicy = "Skip"
+)
+
+// KernelMappingSpec describes a kernel mapping matched by regular expression
+type KernelMappingSpec struct {
+	// regular expression to be matched against the node kernel version, e.g. ^6\.8\.0-[0-9]+-generic$
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Regexp",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:regexp"}
+	// +kubebuilder:validation:MinLength=1
+	Regexp string `json:"regexp"`
 }
 
 // UpgradeState captures the state of the upgrade process on a node
@@ -413,6 +1135,57 @@ type ImageBuildSpec struct {
 	// this field will be applied to SourceImageRepo as well
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="BaseImageRegistryTLS",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:baseImageRegistryTLS"}
 	BaseImageRegistryTLS RegistryTLS `json:"baseImageRegistryTLS,omitempty"`
+
+	// user supplied Dockerfiles to build the driver image instead of the operator rendered ones
+	// each ConfigMap must hold the Dockerfile content under the key dockerfile and be in the same namespace as the NetworkConfig
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Dockerfiles",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:dockerfiles"}
+	// +optional
+	Dockerfiles []DockerfileSpec `json:"dockerfiles,omitempty"`
+
+	// extra build args passed to the driver image build, e.g. HTTP_PROXY or extra packages to install
+	// build args managed by the operator (DRIVERS_VERSION, REPO_URL, BASE_IMAGE_REGISTRY, SOURCE_IMAGE_REPO, RHEL_VERSION, CA_BUNDLE_PATHS) cannot be overridden
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="BuildArgs",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:buildArgs"}
+	// +optional
+	BuildArgs []BuildArg `json:"buildArgs,omitempty"`
+
+	// secrets made available to the driver image build, mounted at /run/secrets/<secret name> during the build
+	// e.g. credentials of a private package mirror
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="BuildSecrets",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:buildSecrets"}
+	// +optional
+	BuildSecrets []v1.LocalObjectReference `json:"buildSecrets,omitempty"`
+
+	// secrets holding PEM encoded CA certificates to trust during the driver image build, e.g. for internal mirrors or TLS intercepting proxies
+	// every key of the secrets is added to the trust store of the builder stage in the built-in Dockerfiles
+	// user supplied Dockerfiles can find the secret directories in the CA_BUNDLE_PATHS build arg
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CABundleSecrets",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:caBundleSecrets"}
+	// +optional
+	CABundleSecrets []v1.LocalObjectReference `json:"caBundleSecrets,omitempty"`
+}
+
+// DockerfileSpec describes a user supplied Dockerfile for the driver image build of one OS
+type DockerfileSpec struct {
+	// OS the Dockerfile applies to, either <distro>-<version> e.g. ubuntu-22.04 or <distro> for all versions e.g. ubuntu
+	// an exact <distro>-<version> match takes precedence over a <distro> match
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OS",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:os"}
+	// +kubebuilder:validation:MinLength=1
+	OS string `json:"os"`
+
+	// ConfigMap holding the Dockerfile content under the key dockerfile
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ConfigMap",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:configMap"}
+	ConfigMap v1.LocalObjectReference `json:"configMap"`
+}
+
+// BuildArg is a build arg passed to the driver image build
+type BuildArg struct {
+	// name of the build arg
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:name"}
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// value of the build arg
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Value",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:value"}
+	// +optional
+	Value string `json:"value,omitempty"`
 }
 
 // ServiceType string describes ingress methods for a service
@@ -521,6 +1294,11 @@ type MetricsExporterSpec struct {
 	// +optional
 	Config MetricsConfig `json:"config,omitempty"`
 
+	// Options are the command line options of the metrics exporter
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Options",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:options"}
+	// +optional
+	Options *MetricsExporterOptions `json:"options,omitempty"`
+
 	// optional kube-rbac-proxy config to provide rbac services
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RbacConfig",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:rbacConfig"}
 	// +optional
@@ -546,6 +1324,97 @@ type MetricsExporterSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Prometheus",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:prometheus"}
 	// +optional
 	Prometheus *PrometheusConfig `json:"prometheus,omitempty"`
+
+	// OTLP pushes the metrics to an OpenTelemetry collector through an OpenTelemetry Collector sidecar
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OTLP",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:otlp"}
+	// +optional
+	OTLP *OTLPConfig `json:"otlp,omitempty"`
+
+	// pod customization of the metrics exporter, merged over commonConfig.podCustomization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PodCustomization",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:podCustomization"}
+	// +optional
+	PodCustomization *PodCustomizationOverrideSpec `json:"podCustomization,omitempty"`
+}
+
+// OTLPConfig provides configuration for pushing the metrics over OTLP
+type OTLPConfig struct {
+	// Enable or disable the OTLP push (default false)
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:enable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// Endpoint of the OTLP receiver, host:port for grpc or the URL for http
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Endpoint",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:endpoint"}
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Protocol of the OTLP receiver
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Protocol",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:protocol"}
+	// +kubebuilder:validation:Enum=grpc;http
+	// +kubebuilder:default=grpc
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// HeadersSecret is a secret in the NetworkConfig namespace, each key is sent as a header with its value, e.g. Authorization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="HeadersSecret",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:headersSecret"}
+	// +optional
+	HeadersSecret *v1.LocalObjectReference `json:"headersSecret,omitempty"`
+
+	// TLS configuration of the connection to the OTLP receiver
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TLS",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:tls"}
+	// +optional
+	TLS *OTLPTLSConfig `json:"tls,omitempty"`
+
+	// Interval the metrics are collected from the exporter and pushed at. Accepts values with time unit suffix: "30s", "1m"
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Interval",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:interval"}
+	// +kubebuilder:validation:Pattern=`^([0-9]+)(s|m|h)$`
+	// +kubebuilder:default="60s"
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// ResourceAttributes added to the pushed metrics, k8s.node.name is set to the node of the exporter by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ResourceAttributes",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:resourceAttributes"}
+	// +optional
+	ResourceAttributes map[string]string `json:"resourceAttributes,omitempty"`
+
+	// Image of the OpenTelemetry Collector sidecar
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:image"}
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]+(?:[._-][a-z0-9]+)*(:[0-9]+)?)(/[a-z0-9]+(?:[._-][a-z0-9]+)*)*(?::[a-z0-9._-]+)?(?:@[a-zA-Z0-9]+:[a-f0-9]+)?$`
+	Image string `json:"image,omitempty"`
+
+	// KeepService keeps the metrics Service, and allows the ServiceMonitor, when the metrics are pushed (default false)
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="KeepService",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:keepService"}
+	// +optional
+	KeepService *bool `json:"keepService,omitempty"`
+}
+
+// OTLPTLSConfig provides the TLS configuration of the OTLP connection
+type OTLPTLSConfig struct {
+	// Insecure disables TLS, only for grpc endpoints, http endpoints use the URL scheme
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Insecure",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:insecure"}
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// InsecureSkipVerify skips the verification of the server certificate
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="InsecureSkipVerify",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:insecureSkipVerify"}
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// CASecret is a secret with the ca.crt key used to verify the server certificate, the system roots by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CASecret",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:caSecret"}
+	// +optional
+	CASecret *v1.LocalObjectReference `json:"caSecret,omitempty"`
+
+	// CertSecret is a kubernetes.io/tls secret with the client certificate for mutual TLS
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CertSecret",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:certSecret"}
+	// +optional
+	CertSecret *v1.LocalObjectReference `json:"certSecret,omitempty"`
+
+	// ServerName overrides the server name verified in the server certificate
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ServerName",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:serverName"}
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
 }
 
 type PrometheusConfig struct {
@@ -553,6 +1422,114 @@ type PrometheusConfig struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ServiceMonitor",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:serviceMonitor"}
 	// +optional
 	ServiceMonitor *ServiceMonitorConfig `json:"serviceMonitor,omitempty"`
+
+	// PrometheusRule with the alert rules of the AMD NICs
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rules",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:rules"}
+	// +optional
+	Rules *PrometheusRuleConfig `json:"rules,omitempty"`
+
+	// Grafana dashboard provisioned as a ConfigMap picked up by the Grafana dashboard sidecar
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="GrafanaDashboard",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:grafanaDashboard"}
+	// +optional
+	GrafanaDashboard *GrafanaDashboardConfig `json:"grafanaDashboard,omitempty"`
+}
+
+// PrometheusRuleConfig provides configuration for the PrometheusRule
+type PrometheusRuleConfig struct {
+	// Enable or disable PrometheusRule creation (default false)
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:enable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// Additional labels to add to the PrometheusRule, e.g. to match the ruleSelector of Prometheus
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Labels",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:labels"}
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// LinkDown fires when a NIC port has no link, based on the port frames reported by the metrics exporter
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="LinkDown",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:linkDown"}
+	// +optional
+	LinkDown *LinkDownAlertConfig `json:"linkDown,omitempty"`
+
+	// RDMAErrors fires when the RDMA completion and local errors of a device exceed the threshold over 5 minutes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RDMAErrors",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:rdmaErrors"}
+	// +optional
+	RDMAErrors *AlertRuleConfig `json:"rdmaErrors,omitempty"`
+
+	// ExporterDown fires when a metrics exporter target is down or its pods are unavailable
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ExporterDown",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:exporterDown"}
+	// +optional
+	ExporterDown *AlertRuleConfig `json:"exporterDown,omitempty"`
+
+	// DriverUpgradeStuck fires when a driver upgrade worker or reboot pod doesn't complete
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DriverUpgradeStuck",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:driverUpgradeStuck"}
+	// +optional
+	DriverUpgradeStuck *AlertRuleConfig `json:"driverUpgradeStuck,omitempty"`
+
+	// DevicePluginUnhealthy fires when more NIC resources than the threshold are unhealthy on a node or device plugin pods are unavailable
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DevicePluginUnhealthy",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:devicePluginUnhealthy"}
+	// +optional
+	DevicePluginUnhealthy *AlertRuleConfig `json:"devicePluginUnhealthy,omitempty"`
+}
+
+// AlertRuleConfig customizes an alert rule, unset fields keep the defaults of the alert
+type AlertRuleConfig struct {
+	// Enable or disable the alert (default true)
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:enable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// For is how long the condition must hold before the alert fires. Accepts values with time unit suffix: "30s", "5m", "1h"
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="For",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:for"}
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([0-9]+)(s|m|h)$`
+	For string `json:"for,omitempty"`
+
+	// Severity label of the alert
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Severity",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:severity"}
+	// +kubebuilder:validation:Enum=info;warning;critical
+	// +optional
+	Severity string `json:"severity,omitempty"`
+
+	// Threshold of the alert, only used by the alerts comparing a value
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Threshold",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:threshold"}
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Threshold *int32 `json:"threshold,omitempty"`
+}
+
+// LinkDownAlertConfig customizes the link down alert
+type LinkDownAlertConfig struct {
+	AlertRuleConfig `json:",inline"`
+
+	// Ports is a regular expression matching the port_name of the AMD NIC ports monitored, all ports by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ports",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:ports"}
+	// +optional
+	Ports string `json:"ports,omitempty"`
+}
+
+// GrafanaDashboardConfig provides configuration for the Grafana dashboard ConfigMap
+type GrafanaDashboardConfig struct {
+	// Enable or disable the dashboard ConfigMap creation (default false)
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:enable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// Labels of the ConfigMap, matching the label watched by the Grafana sidecar (default grafana_dashboard: "1")
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Labels",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:labels"}
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// URL of the AINIC dashboard JSON published in the grafana directory of the device-metrics-exporter repository,
+	// downloaded by the Grafana sidecar, required when the dashboard is enabled
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="URL",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:url"}
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Folder of the dashboard in Grafana, set as the grafana_folder annotation
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Folder",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:folder"}
+	// +optional
+	Folder string `json:"folder,omitempty"`
 }
 
 // ServiceMonitorConfig provides configuration for ServiceMonitor
@@ -659,6 +1636,97 @@ type KubeRbacConfig struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="StaticAuthorization",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:staticAuthorization"}
 	// +optional
 	StaticAuthorization *StaticAuthConfig `json:"staticAuthorization,omitempty"`
+
+	// AutoTLS has the operator issue and rotate the serving certificate of kube-rbac-proxy and a client certificate for Prometheus
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="AutoTLS",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:autoTLS"}
+	// +optional
+	AutoTLS *AutoTLSConfig `json:"autoTLS,omitempty"`
+}
+
+// AutoTLSConfig configures the certificates issued by the operator for kube-rbac-proxy
+type AutoTLSConfig struct {
+	// Enable or disable the certificates issued by the operator (default false), secret and clientCAConfigMap must not be set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:enable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// Provider issuing the certificates, a CA internal to the operator or cert-manager Certificates
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Provider",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:provider"}
+	// +kubebuilder:validation:Enum=internal;cert-manager
+	// +kubebuilder:default=internal
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// IssuerRef is the cert-manager issuer of the certificates, a CA issuer owned by the operator by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="IssuerRef",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:issuerRef"}
+	// +optional
+	IssuerRef *CertManagerIssuerRef `json:"issuerRef,omitempty"`
+
+	// DurationHours is the validity of the certificates, they are renewed when a third of it is left
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DurationHours",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:durationHours"}
+	// +kubebuilder:validation:Minimum=24
+	// +kubebuilder:default=2160
+	// +optional
+	DurationHours int32 `json:"durationHours,omitempty"`
+}
+
+// CertManagerIssuerRef references a cert-manager Issuer or ClusterIssuer
+type CertManagerIssuerRef struct {
+	// Name of the issuer
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:name"}
+	Name string `json:"name"`
+
+	// Kind of the issuer
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Kind",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:kind"}
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer, for external issuers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Group",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:group"}
+	// +kubebuilder:default=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// MetricsExporterOptions are rendered into the arguments and environment of the metrics exporter container
+type MetricsExporterOptions struct {
+	// MonitorNIC exports the NIC metrics, enabled by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="MonitorNIC",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:monitorNIC"}
+	// +kubebuilder:default=true
+	// +optional
+	MonitorNIC *bool `json:"monitorNIC,omitempty"`
+
+	// MonitorGPU exports the GPU metrics, disabled by default as the GPU operator deploys its own exporter
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="MonitorGPU",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:monitorGPU"}
+	// +optional
+	MonitorGPU *bool `json:"monitorGPU,omitempty"`
+
+	// ConfigKey is the key of the config map in config.name holding the metrics field configuration (default config.json)
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ConfigKey",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:configKey"}
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	ConfigKey string `json:"configKey,omitempty"`
+
+	// InternalPort is the port the exporter listens on behind kube-rbac-proxy, 5001 by default or 5000 when port is 5001.
+	// Set it when the default collides with another exporter on the host network, e.g. the one of the GPU operator
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="InternalPort",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:internalPort"}
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	InternalPort int32 `json:"internalPort,omitempty"`
+
+	// LogLevel of the metrics exporter, info by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="LogLevel",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:logLevel"}
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// ExtraArgs are appended to the arguments rendered from the options above
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ExtraArgs",xDescriptors={"urn:alm:descriptor:com.amd.networkconfigs:extraArgs"}
+	// +optional
+	ExtraArgs []string `json:"extraArgs,omitempty"`
 }
 
 // MetricsConfig contains list of metrics to collect/report
@@ -757,6 +1825,75 @@ type UtilsContainerSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ImageRegistrySecret",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:imageRegistrySecret"}
 	// +optional
 	ImageRegistrySecret *v1.LocalObjectReference `json:"imageRegistrySecret,omitempty"`
+
+	// pod customization of the utils pods, i.e. upgrade workers and reboot pods, merged over commonConfig.podCustomization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PodCustomization",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:podCustomization"}
+	// +optional
+	PodCustomization *PodCustomizationOverrideSpec `json:"podCustomization,omitempty"`
+}
+
+// NodeReadinessSpec describes how the AMDNetworkReady node condition is evaluated
+type NodeReadinessSpec struct {
+	// expected number of AMD NIC resources advertised by the device plugin on each node
+	// if not specified, all the NIC resources registered by the device plugin must be allocatable
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="ExpectedNICCount",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:expectedNICCount"}
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ExpectedNICCount int32 `json:"expectedNICCount,omitempty"`
+}
+
+// RemediationSpec describes how the nodes with unhealthy NICs are remediated
+// a NIC is unhealthy once the device plugin withdraws it from the allocatable resources, e.g. with enableExporterHealthCheck
+type RemediationSpec struct {
+	// enable the remediation, disabled by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Enable",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:remediationEnable"}
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// number of unhealthy NICs on a node which triggers its remediation
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="UnhealthyNICThreshold",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:remediationUnhealthyNICThreshold"}
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=1
+	// +optional
+	UnhealthyNICThreshold int32 `json:"unhealthyNICThreshold,omitempty"`
+
+	// time in seconds the NICs need to stay unhealthy before the node is remediated
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="UnhealthyDurationSeconds",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:remediationUnhealthyDurationSeconds"}
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default:=300
+	// +optional
+	UnhealthyDurationSeconds int32 `json:"unhealthyDurationSeconds,omitempty"`
+
+	// effect of the taint applied on the remediated nodes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TaintEffect",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:remediationTaintEffect"}
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	// +kubebuilder:default:=NoSchedule
+	// +optional
+	TaintEffect v1.TaintEffect `json:"taintEffect,omitempty"`
+
+	// cordon the remediated nodes, disabled by default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cordon",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:remediationCordon"}
+	// +optional
+	Cordon *bool `json:"cordon,omitempty"`
+
+	// drain the remediated nodes, disabled by default
+	// the node is cordoned and its pods are evicted, except the DaemonSet and static pods
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Drain",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:remediationDrain"}
+	// +optional
+	Drain *bool `json:"drain,omitempty"`
+
+	// drain policy of the remediated nodes, force also evicts the pods not managed by a controller
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="NodeDrainPolicy",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:remediationNodeDrainPolicy"}
+	// +optional
+	NodeDrainPolicy *DrainSpec `json:"nodeDrainPolicy,omitempty"`
+
+	// maximum number of nodes remediated at the same time across the cluster
+	// the other nodes stay pending until a remediated node recovers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="MaxConcurrentRemediations",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:remediationMaxConcurrentRemediations"}
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default:=1
+	// +optional
+	MaxConcurrentRemediations int32 `json:"maxConcurrentRemediations,omitempty"`
 }
 
 // CommonConfigSpec contains the common config across operator and operands
@@ -770,6 +1907,11 @@ type CommonConfigSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="UtilsContainer",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:utilsContainer"}
 	// +optional
 	UtilsContainer UtilsContainerSpec `json:"utilsContainer,omitempty"`
+
+	// default pod customization of all the operand pods, overridden field by field by the operand podCustomization
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PodCustomization",xDescriptors={"urn:alm:descriptor:com.amd.NetworkConfigs:podCustomization"}
+	// +optional
+	PodCustomization *PodCustomizationSpec `json:"podCustomization,omitempty"`
 }
 
 // DeploymentStatus contains the status for a daemonset deployed during
@@ -794,6 +1936,29 @@ type ModuleStatus struct {
 	Status             UpgradeState `json:"status,omitempty"`
 	UpgradeStartTime   string       `json:"upgradeStartTime,omitempty"`
 	BootId             string       `json:"bootId,omitempty"`
+	// Reason describes why the driver module is not configured on the node, e.g. unsupported OS
+	Reason string `json:"reason,omitempty"`
+	// KmodSignature is the result of verifying the loaded kernel modules against the imageSign certificate
+	// one of Pending, Verified or Failed followed by the failure details
+	KmodSignature string `json:"kmodSignature,omitempty"`
+}
+
+// HostConfigStatus contains the status of the host config on the node
+type HostConfigStatus struct {
+	// InSync is true once the node runs with the desired host config
+	InSync bool `json:"inSync"`
+	// ConfigHash is the hash of the desired host config
+	ConfigHash string `json:"configHash,omitempty"`
+	// Message describes why the node is not in sync
+	Message string `json:"message,omitempty"`
+}
+
+// CNIPluginsStatus contains the install status of the CNI plugins on the node
+type CNIPluginsStatus struct {
+	// Ready is true once all the CNI plugins are installed or provided by another operator
+	Ready bool `json:"ready"`
+	// Plugins maps each CNI plugin to its install status: installed, foreign, missing or failed
+	Plugins map[string]string `json:"plugins,omitempty"`
 }
 
 // NetworkConfigStatus defines the observed state of Module.
@@ -806,6 +1971,12 @@ type NetworkConfigStatus struct {
 	MetricsExporter DeploymentStatus `json:"metricsExporter,omitempty"`
 	// ConfigManager contains the status of the ConfigManager deployment
 	ConfigManager DeploymentStatus `json:"configManager,omitempty"`
+	// NodeCNIPluginsStatus contains per node install status of the CNI plugins
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="NodeCNIPluginsStatus",xDescriptors="urn:alm:descriptor:com.amd.NetworkConfigs:nodeCNIPluginsStatus"
+	NodeCNIPluginsStatus map[string]CNIPluginsStatus `json:"nodeCNIPluginsStatus,omitempty"`
+	// NodeHostConfigStatus contains per node status of the host config
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="NodeHostConfigStatus",xDescriptors="urn:alm:descriptor:com.amd.NetworkConfigs:nodeHostConfigStatus"
+	NodeHostConfigStatus map[string]HostConfigStatus `json:"nodeHostConfigStatus,omitempty"`
 	// NodeModuleStatus contains per node status of driver module installation
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="NodeModuleStatus",xDescriptors="urn:alm:descriptor:com.amd.NetworkConfigs:nodeModuleStatus"
 	NodeModuleStatus map[string]ModuleStatus `json:"nodeModuleStatus,omitempty"`
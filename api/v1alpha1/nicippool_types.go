@@ -0,0 +1,97 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NICIPPoolSpec describes the range of a cluster-wide IP pool and its leases
+type NICIPPoolSpec struct {
+	// subnet in CIDR notation the addresses are leased from
+	// +kubebuilder:validation:Required
+	Range string `json:"range"`
+
+	// first address leased from the range
+	// +optional
+	RangeStart string `json:"rangeStart,omitempty"`
+
+	// last address leased from the range
+	// +optional
+	RangeEnd string `json:"rangeEnd,omitempty"`
+
+	// gateway of the range, never leased
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// leased addresses keyed by IP address, written by the amd-host-device CNI plugin
+	// and garbage collected by the operator once the pod is gone
+	// +optional
+	Allocations map[string]NICIPAllocation `json:"allocations,omitempty"`
+}
+
+// NICIPAllocation describes the lease of an address
+type NICIPAllocation struct {
+	// namespace/name of the pod the address is leased to
+	// +optional
+	PodRef string `json:"podRef,omitempty"`
+
+	// UID of the pod the address is leased to, a pod recreated with the same name doesn't inherit the lease
+	// +optional
+	PodUID string `json:"podUID,omitempty"`
+
+	// container ID of the pod sandbox the address is leased to
+	// +optional
+	ContainerID string `json:"containerID,omitempty"`
+
+	// interface name of the address in the pod
+	// +optional
+	IfName string `json:"ifName,omitempty"`
+
+	// node of the pod, or of the host interface
+	// +optional
+	Node string `json:"node,omitempty"`
+
+	// the address is configured on a host interface of the node and is never leased to a pod of another node
+	// +optional
+	HostReserved bool `json:"hostReserved,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Namespaced,shortName=nicpool
+//+kubebuilder:printcolumn:name="Range",type=string,JSONPath=`.spec.range`
+
+// NICIPPool is a cluster-wide IP pool of a NetworkConfig attachment using the cluster IPAM mode, one per rail subnet
+type NICIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NICIPPoolSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NICIPPoolList contains a list of NICIPPools
+type NICIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NICIPPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NICIPPool{}, &NICIPPoolList{})
+}
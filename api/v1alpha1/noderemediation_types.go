@@ -0,0 +1,110 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeRemediationPhase is the phase of the remediation of a node
+type NodeRemediationPhase string
+
+const (
+	// NodeRemediationPending the NICs are unhealthy, the node is remediated once they stayed unhealthy
+	// for the configured duration and the cluster-wide limit of concurrent remediations allows it
+	NodeRemediationPending NodeRemediationPhase = "Pending"
+	// NodeRemediationRemediating the node is tainted, and cordoned and drained if configured
+	NodeRemediationRemediating NodeRemediationPhase = "Remediating"
+	// NodeRemediationResolved the NICs recovered and the remediation steps were reversed
+	NodeRemediationResolved NodeRemediationPhase = "Resolved"
+)
+
+// NodeRemediationSpec describes the node under remediation
+type NodeRemediationSpec struct {
+	// name of the node
+	// +kubebuilder:validation:Required
+	NodeName string `json:"nodeName"`
+}
+
+// NodeRemediationStatus describes the progress of the remediation of a node
+type NodeRemediationStatus struct {
+	// phase of the remediation
+	// +optional
+	Phase NodeRemediationPhase `json:"phase,omitempty"`
+
+	// number of unhealthy NICs on the node when last evaluated
+	// +optional
+	UnhealthyNICs int32 `json:"unhealthyNICs,omitempty"`
+
+	// time the number of unhealthy NICs reached the threshold
+	// +optional
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
+
+	// time the node was remediated
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// time the remediation steps were reversed
+	// +optional
+	ResolvedTime *metav1.Time `json:"resolvedTime,omitempty"`
+
+	// the node was tainted by the operator
+	// +optional
+	Tainted bool `json:"tainted,omitempty"`
+
+	// the node was cordoned by the operator, a node which was already unschedulable is left as is on recovery
+	// +optional
+	Cordoned bool `json:"cordoned,omitempty"`
+
+	// the pods of the node were drained
+	// +optional
+	Drained bool `json:"drained,omitempty"`
+
+	// details of the last remediation step
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Namespaced,shortName=nicremediation
+//+kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.spec.nodeName`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Unhealthy",type=integer,JSONPath=`.status.unhealthyNICs`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NodeRemediation records the remediation of a node whose NICs are unhealthy, opened and closed by the operator
+// following the remediation policy of the NetworkConfig selecting the node
+type NodeRemediation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeRemediationSpec   `json:"spec,omitempty"`
+	Status NodeRemediationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NodeRemediationList contains a list of NodeRemediations
+type NodeRemediationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeRemediation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeRemediation{}, &NodeRemediationList{})
+}
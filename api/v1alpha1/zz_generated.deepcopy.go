@@ -43,6 +43,108 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertRuleConfig) DeepCopyInto(out *AlertRuleConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertRuleConfig.
+func (in *AlertRuleConfig) DeepCopy() *AlertRuleConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertRuleConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoTLSConfig) DeepCopyInto(out *AutoTLSConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertManagerIssuerRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoTLSConfig.
+func (in *AutoTLSConfig) DeepCopy() *AutoTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildArg) DeepCopyInto(out *BuildArg) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildArg.
+func (in *BuildArg) DeepCopy() *BuildArg {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildArg)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CNIPluginsStatus) DeepCopyInto(out *CNIPluginsStatus) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CNIPluginsStatus.
+func (in *CNIPluginsStatus) DeepCopy() *CNIPluginsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CNIPluginsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CniPluginsSpec) DeepCopyInto(out *CniPluginsSpec) {
 	*out = *in
@@ -68,6 +170,21 @@ func (in *CniPluginsSpec) DeepCopyInto(out *CniPluginsSpec) {
 		*out = new(DaemonSetUpgradeSpec)
 		**out = **in
 	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OverwriteForeignBinaries != nil {
+		in, out := &in.OverwriteForeignBinaries, &out.OverwriteForeignBinaries
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PodCustomization != nil {
+		in, out := &in.PodCustomization, &out.PodCustomization
+		*out = new(PodCustomizationOverrideSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CniPluginsSpec.
@@ -84,6 +201,11 @@ func (in *CniPluginsSpec) DeepCopy() *CniPluginsSpec {
 func (in *CommonConfigSpec) DeepCopyInto(out *CommonConfigSpec) {
 	*out = *in
 	in.UtilsContainer.DeepCopyInto(&out.UtilsContainer)
+	if in.PodCustomization != nil {
+		in, out := &in.PodCustomization, &out.PodCustomization
+		*out = new(PodCustomizationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonConfigSpec.
@@ -145,6 +267,67 @@ func (in *ConfigManagerSpec) DeepCopy() *ConfigManagerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRADriverSpec) DeepCopyInto(out *DRADriverSpec) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImageRegistrySecret != nil {
+		in, out := &in.ImageRegistrySecret, &out.ImageRegistrySecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(DaemonSetUpgradeSpec)
+		**out = **in
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rails != nil {
+		in, out := &in.Rails, &out.Rails
+		*out = make([]RailSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeviceClasses != nil {
+		in, out := &in.DeviceClasses, &out.DeviceClasses
+		*out = make([]DeviceClassSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodCustomization != nil {
+		in, out := &in.PodCustomization, &out.PodCustomization
+		*out = new(PodCustomizationOverrideSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRADriverSpec.
+func (in *DRADriverSpec) DeepCopy() *DRADriverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DRADriverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonSetUpgradeSpec) DeepCopyInto(out *DaemonSetUpgradeSpec) {
 	*out = *in
@@ -175,6 +358,56 @@ func (in *DeploymentStatus) DeepCopy() *DeploymentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevBuildSpec) DeepCopyInto(out *DevBuildSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevBuildSpec.
+func (in *DevBuildSpec) DeepCopy() *DevBuildSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevBuildSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassSpec) DeepCopyInto(out *DeviceClassSpec) {
+	*out = *in
+	if in.Selectors != nil {
+		in, out := &in.Selectors, &out.Selectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassSpec.
+func (in *DeviceClassSpec) DeepCopy() *DeviceClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevicePluginFlagsSpec) DeepCopyInto(out *DevicePluginFlagsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePluginFlagsSpec.
+func (in *DevicePluginFlagsSpec) DeepCopy() *DevicePluginFlagsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevicePluginFlagsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevicePluginSpec) DeepCopyInto(out *DevicePluginSpec) {
 	*out = *in
@@ -192,6 +425,20 @@ func (in *DevicePluginSpec) DeepCopyInto(out *DevicePluginSpec) {
 			(*out)[key] = val
 		}
 	}
+	out.DevicePluginFlags = in.DevicePluginFlags
+	in.GPUAffinity.DeepCopyInto(&out.GPUAffinity)
+	if in.ResourcePools != nil {
+		in, out := &in.ResourcePools, &out.ResourcePools
+		*out = make([]ResourcePoolSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnableDevicePlugin != nil {
+		in, out := &in.EnableDevicePlugin, &out.EnableDevicePlugin
+		*out = new(bool)
+		**out = **in
+	}
 	if in.NodeLabellerTolerations != nil {
 		in, out := &in.NodeLabellerTolerations, &out.NodeLabellerTolerations
 		*out = make([]v1.Toleration, len(*in))
@@ -209,11 +456,21 @@ func (in *DevicePluginSpec) DeepCopyInto(out *DevicePluginSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.NodeLabellerLabels != nil {
+		in, out := &in.NodeLabellerLabels, &out.NodeLabellerLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.UpgradePolicy != nil {
 		in, out := &in.UpgradePolicy, &out.UpgradePolicy
 		*out = new(DaemonSetUpgradeSpec)
 		**out = **in
 	}
+	if in.PodCustomization != nil {
+		in, out := &in.PodCustomization, &out.PodCustomization
+		*out = new(PodCustomizationOverrideSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevicePluginSpec.
@@ -226,6 +483,22 @@ func (in *DevicePluginSpec) DeepCopy() *DevicePluginSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DockerfileSpec) DeepCopyInto(out *DockerfileSpec) {
+	*out = *in
+	out.ConfigMap = in.ConfigMap
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DockerfileSpec.
+func (in *DockerfileSpec) DeepCopy() *DockerfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DockerfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DrainSpec) DeepCopyInto(out *DrainSpec) {
 	*out = *in
@@ -284,6 +557,21 @@ func (in *DriverSpec) DeepCopyInto(out *DriverSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.KernelMappings != nil {
+		in, out := &in.KernelMappings, &out.KernelMappings
+		*out = make([]KernelMappingSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.OSProfiles != nil {
+		in, out := &in.OSProfiles, &out.OSProfiles
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.DevBuild != nil {
+		in, out := &in.DevBuild, &out.DevBuild
+		*out = new(DevBuildSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverSpec.
@@ -333,78 +621,236 @@ func (in *DriverUpgradePolicySpec) DeepCopy() *DriverUpgradePolicySpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageBuildSpec) DeepCopyInto(out *ImageBuildSpec) {
+func (in *GPUAffinitySpec) DeepCopyInto(out *GPUAffinitySpec) {
 	*out = *in
-	in.BaseImageRegistryTLS.DeepCopyInto(&out.BaseImageRegistryTLS)
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBuildSpec.
-func (in *ImageBuildSpec) DeepCopy() *ImageBuildSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUAffinitySpec.
+func (in *GPUAffinitySpec) DeepCopy() *GPUAffinitySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageBuildSpec)
+	out := new(GPUAffinitySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSignSpec) DeepCopyInto(out *ImageSignSpec) {
+func (in *GrafanaDashboardConfig) DeepCopyInto(out *GrafanaDashboardConfig) {
 	*out = *in
-	if in.KeySecret != nil {
-		in, out := &in.KeySecret, &out.KeySecret
-		*out = new(v1.LocalObjectReference)
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
 		**out = **in
 	}
-	if in.CertSecret != nil {
-		in, out := &in.CertSecret, &out.CertSecret
-		*out = new(v1.LocalObjectReference)
-		**out = **in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSignSpec.
-func (in *ImageSignSpec) DeepCopy() *ImageSignSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboardConfig.
+func (in *GrafanaDashboardConfig) DeepCopy() *GrafanaDashboardConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSignSpec)
+	out := new(GrafanaDashboardConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeRbacConfig) DeepCopyInto(out *KubeRbacConfig) {
+func (in *HostConfigSpec) DeepCopyInto(out *HostConfigSpec) {
 	*out = *in
 	if in.Enable != nil {
 		in, out := &in.Enable, &out.Enable
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DisableHttps != nil {
-		in, out := &in.DisableHttps, &out.DisableHttps
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Secret != nil {
-		in, out := &in.Secret, &out.Secret
+	if in.ImageRegistrySecret != nil {
+		in, out := &in.ImageRegistrySecret, &out.ImageRegistrySecret
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
-	if in.ClientCAConfigMap != nil {
-		in, out := &in.ClientCAConfigMap, &out.ClientCAConfigMap
-		*out = new(v1.LocalObjectReference)
-		**out = **in
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.StaticAuthorization != nil {
-		in, out := &in.StaticAuthorization, &out.StaticAuthorization
-		*out = new(StaticAuthConfig)
-		**out = **in
+	if in.ModprobeOptions != nil {
+		in, out := &in.ModprobeOptions, &out.ModprobeOptions
+		*out = make([]ModprobeOptionsSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Blacklist != nil {
+		in, out := &in.Blacklist, &out.Blacklist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodCustomization != nil {
+		in, out := &in.PodCustomization, &out.PodCustomization
+		*out = new(PodCustomizationOverrideSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeRbacConfig.
-func (in *KubeRbacConfig) DeepCopy() *KubeRbacConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostConfigSpec.
+func (in *HostConfigSpec) DeepCopy() *HostConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HostConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostConfigStatus) DeepCopyInto(out *HostConfigStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostConfigStatus.
+func (in *HostConfigStatus) DeepCopy() *HostConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBuildSpec) DeepCopyInto(out *ImageBuildSpec) {
+	*out = *in
+	in.BaseImageRegistryTLS.DeepCopyInto(&out.BaseImageRegistryTLS)
+	if in.Dockerfiles != nil {
+		in, out := &in.Dockerfiles, &out.Dockerfiles
+		*out = make([]DockerfileSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.BuildArgs != nil {
+		in, out := &in.BuildArgs, &out.BuildArgs
+		*out = make([]BuildArg, len(*in))
+		copy(*out, *in)
+	}
+	if in.BuildSecrets != nil {
+		in, out := &in.BuildSecrets, &out.BuildSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.CABundleSecrets != nil {
+		in, out := &in.CABundleSecrets, &out.CABundleSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBuildSpec.
+func (in *ImageBuildSpec) DeepCopy() *ImageBuildSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBuildSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageSignSpec) DeepCopyInto(out *ImageSignSpec) {
+	*out = *in
+	if in.KeySecret != nil {
+		in, out := &in.KeySecret, &out.KeySecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.CertSecret != nil {
+		in, out := &in.CertSecret, &out.CertSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSignSpec.
+func (in *ImageSignSpec) DeepCopy() *ImageSignSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageSignSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelMappingSpec) DeepCopyInto(out *KernelMappingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelMappingSpec.
+func (in *KernelMappingSpec) DeepCopy() *KernelMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeRbacConfig) DeepCopyInto(out *KubeRbacConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableHttps != nil {
+		in, out := &in.DisableHttps, &out.DisableHttps
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.ClientCAConfigMap != nil {
+		in, out := &in.ClientCAConfigMap, &out.ClientCAConfigMap
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.StaticAuthorization != nil {
+		in, out := &in.StaticAuthorization, &out.StaticAuthorization
+		*out = new(StaticAuthConfig)
+		**out = **in
+	}
+	if in.AutoTLS != nil {
+		in, out := &in.AutoTLS, &out.AutoTLS
+		*out = new(AutoTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeRbacConfig.
+func (in *KubeRbacConfig) DeepCopy() *KubeRbacConfig {
 	if in == nil {
 		return nil
 	}
@@ -413,6 +859,22 @@ func (in *KubeRbacConfig) DeepCopy() *KubeRbacConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinkDownAlertConfig) DeepCopyInto(out *LinkDownAlertConfig) {
+	*out = *in
+	in.AlertRuleConfig.DeepCopyInto(&out.AlertRuleConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinkDownAlertConfig.
+func (in *LinkDownAlertConfig) DeepCopy() *LinkDownAlertConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LinkDownAlertConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogsLocationConfig) DeepCopyInto(out *LogsLocationConfig) {
 	*out = *in
@@ -454,6 +916,36 @@ func (in *MetricsConfig) DeepCopy() *MetricsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsExporterOptions) DeepCopyInto(out *MetricsExporterOptions) {
+	*out = *in
+	if in.MonitorNIC != nil {
+		in, out := &in.MonitorNIC, &out.MonitorNIC
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MonitorGPU != nil {
+		in, out := &in.MonitorGPU, &out.MonitorGPU
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsExporterOptions.
+func (in *MetricsExporterOptions) DeepCopy() *MetricsExporterOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsExporterOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricsExporterSpec) DeepCopyInto(out *MetricsExporterSpec) {
 	*out = *in
@@ -475,6 +967,11 @@ func (in *MetricsExporterSpec) DeepCopyInto(out *MetricsExporterSpec) {
 		}
 	}
 	out.Config = in.Config
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(MetricsExporterOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	in.RbacConfig.DeepCopyInto(&out.RbacConfig)
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
@@ -488,64 +985,548 @@ func (in *MetricsExporterSpec) DeepCopyInto(out *MetricsExporterSpec) {
 		*out = new(DaemonSetUpgradeSpec)
 		**out = **in
 	}
-	if in.HostNetwork != nil {
-		in, out := &in.HostNetwork, &out.HostNetwork
-		*out = new(bool)
-		**out = **in
+	if in.HostNetwork != nil {
+		in, out := &in.HostNetwork, &out.HostNetwork
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OTLP != nil {
+		in, out := &in.OTLP, &out.OTLP
+		*out = new(OTLPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodCustomization != nil {
+		in, out := &in.PodCustomization, &out.PodCustomization
+		*out = new(PodCustomizationOverrideSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsExporterSpec.
+func (in *MetricsExporterSpec) DeepCopy() *MetricsExporterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsExporterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModprobeOptionsSpec) DeepCopyInto(out *ModprobeOptionsSpec) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModprobeOptionsSpec.
+func (in *ModprobeOptionsSpec) DeepCopy() *ModprobeOptionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModprobeOptionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleStatus) DeepCopyInto(out *ModuleStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleStatus.
+func (in *ModuleStatus) DeepCopy() *ModuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MultusSpec) DeepCopyInto(out *MultusSpec) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImageRegistrySecret != nil {
+		in, out := &in.ImageRegistrySecret, &out.ImageRegistrySecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(DaemonSetUpgradeSpec)
+		**out = **in
+	}
+	if in.KubeconfigSecret != nil {
+		in, out := &in.KubeconfigSecret, &out.KubeconfigSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.WaitTimeoutSeconds != nil {
+		in, out := &in.WaitTimeoutSeconds, &out.WaitTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PodCustomization != nil {
+		in, out := &in.PodCustomization, &out.PodCustomization
+		*out = new(PodCustomizationOverrideSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MultusSpec.
+func (in *MultusSpec) DeepCopy() *MultusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MultusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NICIPAllocation) DeepCopyInto(out *NICIPAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NICIPAllocation.
+func (in *NICIPAllocation) DeepCopy() *NICIPAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(NICIPAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NICIPPool) DeepCopyInto(out *NICIPPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NICIPPool.
+func (in *NICIPPool) DeepCopy() *NICIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(NICIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NICIPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NICIPPoolList) DeepCopyInto(out *NICIPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NICIPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NICIPPoolList.
+func (in *NICIPPoolList) DeepCopy() *NICIPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(NICIPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NICIPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NICIPPoolSpec) DeepCopyInto(out *NICIPPoolSpec) {
+	*out = *in
+	if in.Allocations != nil {
+		in, out := &in.Allocations, &out.Allocations
+		*out = make(map[string]NICIPAllocation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NICIPPoolSpec.
+func (in *NICIPPoolSpec) DeepCopy() *NICIPPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NICIPPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAttachmentIPAMSpec) DeepCopyInto(out *NetworkAttachmentIPAMSpec) {
+	*out = *in
+	if in.Rails != nil {
+		in, out := &in.Rails, &out.Rails
+		*out = make([]NetworkAttachmentRailSpec, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAttachmentIPAMSpec.
+func (in *NetworkAttachmentIPAMSpec) DeepCopy() *NetworkAttachmentIPAMSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAttachmentIPAMSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAttachmentRailSpec) DeepCopyInto(out *NetworkAttachmentRailSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAttachmentRailSpec.
+func (in *NetworkAttachmentRailSpec) DeepCopy() *NetworkAttachmentRailSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAttachmentRailSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAttachmentSpec) DeepCopyInto(out *NetworkAttachmentSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tuning != nil {
+		in, out := &in.Tuning, &out.Tuning
+		*out = new(NetworkAttachmentTuningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.IPAM.DeepCopyInto(&out.IPAM)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAttachmentSpec.
+func (in *NetworkAttachmentSpec) DeepCopy() *NetworkAttachmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAttachmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAttachmentTuningSpec) DeepCopyInto(out *NetworkAttachmentTuningSpec) {
+	*out = *in
+	if in.MTU != nil {
+		in, out := &in.MTU, &out.MTU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAttachmentTuningSpec.
+func (in *NetworkAttachmentTuningSpec) DeepCopy() *NetworkAttachmentTuningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAttachmentTuningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfig) DeepCopyInto(out *NetworkConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfig.
+func (in *NetworkConfig) DeepCopy() *NetworkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfigList) DeepCopyInto(out *NetworkConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetworkConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigList.
+func (in *NetworkConfigList) DeepCopy() *NetworkConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfigSpec) DeepCopyInto(out *NetworkConfigSpec) {
+	*out = *in
+	in.Driver.DeepCopyInto(&out.Driver)
+	in.MetricsExporter.DeepCopyInto(&out.MetricsExporter)
+	in.ConfigManager.DeepCopyInto(&out.ConfigManager)
+	in.DevicePlugin.DeepCopyInto(&out.DevicePlugin)
+	in.HostConfig.DeepCopyInto(&out.HostConfig)
+	in.NodeLabeller.DeepCopyInto(&out.NodeLabeller)
+	in.DRADriver.DeepCopyInto(&out.DRADriver)
+	in.TestRunner.DeepCopyInto(&out.TestRunner)
+	in.CommonConfig.DeepCopyInto(&out.CommonConfig)
+	in.SecondaryNetwork.DeepCopyInto(&out.SecondaryNetwork)
+	out.NodeReadiness = in.NodeReadiness
+	in.Remediation.DeepCopyInto(&out.Remediation)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigSpec.
+func (in *NetworkConfigSpec) DeepCopy() *NetworkConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfigStatus) DeepCopyInto(out *NetworkConfigStatus) {
+	*out = *in
+	out.DevicePlugin = in.DevicePlugin
+	out.Drivers = in.Drivers
+	out.MetricsExporter = in.MetricsExporter
+	out.ConfigManager = in.ConfigManager
+	if in.NodeCNIPluginsStatus != nil {
+		in, out := &in.NodeCNIPluginsStatus, &out.NodeCNIPluginsStatus
+		*out = make(map[string]CNIPluginsStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.NodeHostConfigStatus != nil {
+		in, out := &in.NodeHostConfigStatus, &out.NodeHostConfigStatus
+		*out = make(map[string]HostConfigStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeModuleStatus != nil {
+		in, out := &in.NodeModuleStatus, &out.NodeModuleStatus
+		*out = make(map[string]ModuleStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigStatus.
+func (in *NetworkConfigStatus) DeepCopy() *NetworkConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLabellerSpec) DeepCopyInto(out *NodeLabellerSpec) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImageRegistrySecret != nil {
+		in, out := &in.ImageRegistrySecret, &out.ImageRegistrySecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(DaemonSetUpgradeSpec)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Prometheus != nil {
-		in, out := &in.Prometheus, &out.Prometheus
-		*out = new(PrometheusConfig)
+	if in.PodCustomization != nil {
+		in, out := &in.PodCustomization, &out.PodCustomization
+		*out = new(PodCustomizationOverrideSpec)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsExporterSpec.
-func (in *MetricsExporterSpec) DeepCopy() *MetricsExporterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLabellerSpec.
+func (in *NodeLabellerSpec) DeepCopy() *NodeLabellerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MetricsExporterSpec)
+	out := new(NodeLabellerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModuleStatus) DeepCopyInto(out *ModuleStatus) {
+func (in *NodeReadinessSpec) DeepCopyInto(out *NodeReadinessSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleStatus.
-func (in *ModuleStatus) DeepCopy() *ModuleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeReadinessSpec.
+func (in *NodeReadinessSpec) DeepCopy() *NodeReadinessSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ModuleStatus)
+	out := new(NodeReadinessSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfig) DeepCopyInto(out *NetworkConfig) {
+func (in *NodeRemediation) DeepCopyInto(out *NodeRemediation) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfig.
-func (in *NetworkConfig) DeepCopy() *NetworkConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRemediation.
+func (in *NodeRemediation) DeepCopy() *NodeRemediation {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfig)
+	out := new(NodeRemediation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NetworkConfig) DeepCopyObject() runtime.Object {
+func (in *NodeRemediation) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -553,31 +1534,31 @@ func (in *NetworkConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfigList) DeepCopyInto(out *NetworkConfigList) {
+func (in *NodeRemediationList) DeepCopyInto(out *NodeRemediationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NetworkConfig, len(*in))
+		*out = make([]NodeRemediation, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigList.
-func (in *NetworkConfigList) DeepCopy() *NetworkConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRemediationList.
+func (in *NodeRemediationList) DeepCopy() *NodeRemediationList {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfigList)
+	out := new(NodeRemediationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NetworkConfigList) DeepCopyObject() runtime.Object {
+func (in *NodeRemediationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -585,63 +1566,188 @@ func (in *NetworkConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfigSpec) DeepCopyInto(out *NetworkConfigSpec) {
+func (in *NodeRemediationSpec) DeepCopyInto(out *NodeRemediationSpec) {
 	*out = *in
-	in.Driver.DeepCopyInto(&out.Driver)
-	in.MetricsExporter.DeepCopyInto(&out.MetricsExporter)
-	in.ConfigManager.DeepCopyInto(&out.ConfigManager)
-	in.DevicePlugin.DeepCopyInto(&out.DevicePlugin)
-	in.TestRunner.DeepCopyInto(&out.TestRunner)
-	in.CommonConfig.DeepCopyInto(&out.CommonConfig)
-	in.SecondaryNetwork.DeepCopyInto(&out.SecondaryNetwork)
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRemediationSpec.
+func (in *NodeRemediationSpec) DeepCopy() *NodeRemediationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRemediationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRemediationStatus) DeepCopyInto(out *NodeRemediationStatus) {
+	*out = *in
+	if in.UnhealthySince != nil {
+		in, out := &in.UnhealthySince, &out.UnhealthySince
+		*out = (*in).DeepCopy()
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ResolvedTime != nil {
+		in, out := &in.ResolvedTime, &out.ResolvedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRemediationStatus.
+func (in *NodeRemediationStatus) DeepCopy() *NodeRemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OTLPConfig) DeepCopyInto(out *OTLPConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HeadersSecret != nil {
+		in, out := &in.HeadersSecret, &out.HeadersSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(OTLPTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceAttributes != nil {
+		in, out := &in.ResourceAttributes, &out.ResourceAttributes
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
+	if in.KeepService != nil {
+		in, out := &in.KeepService, &out.KeepService
+		*out = new(bool)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigSpec.
-func (in *NetworkConfigSpec) DeepCopy() *NetworkConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPConfig.
+func (in *OTLPConfig) DeepCopy() *OTLPConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfigSpec)
+	out := new(OTLPConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkConfigStatus) DeepCopyInto(out *NetworkConfigStatus) {
+func (in *OTLPTLSConfig) DeepCopyInto(out *OTLPTLSConfig) {
 	*out = *in
-	out.DevicePlugin = in.DevicePlugin
-	out.Drivers = in.Drivers
-	out.MetricsExporter = in.MetricsExporter
-	out.ConfigManager = in.ConfigManager
-	if in.NodeModuleStatus != nil {
-		in, out := &in.NodeModuleStatus, &out.NodeModuleStatus
-		*out = make(map[string]ModuleStatus, len(*in))
+	if in.CASecret != nil {
+		in, out := &in.CASecret, &out.CASecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.CertSecret != nil {
+		in, out := &in.CertSecret, &out.CertSecret
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OTLPTLSConfig.
+func (in *OTLPTLSConfig) DeepCopy() *OTLPTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OTLPTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodCustomizationOverrideSpec) DeepCopyInto(out *PodCustomizationOverrideSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodCustomizationOverrideSpec.
+func (in *PodCustomizationOverrideSpec) DeepCopy() *PodCustomizationOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodCustomizationOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodCustomizationSpec) DeepCopyInto(out *PodCustomizationSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeAffinity != nil {
+		in, out := &in.NodeAffinity, &out.NodeAffinity
+		*out = new(v1.NodeAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigStatus.
-func (in *NetworkConfigStatus) DeepCopy() *NetworkConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodCustomizationSpec.
+func (in *PodCustomizationSpec) DeepCopy() *PodCustomizationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkConfigStatus)
+	out := new(PodCustomizationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -674,6 +1780,16 @@ func (in *PrometheusConfig) DeepCopyInto(out *PrometheusConfig) {
 		*out = new(ServiceMonitorConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = new(PrometheusRuleConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrafanaDashboard != nil {
+		in, out := &in.GrafanaDashboard, &out.GrafanaDashboard
+		*out = new(GrafanaDashboardConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusConfig.
@@ -686,6 +1802,78 @@ func (in *PrometheusConfig) DeepCopy() *PrometheusConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRuleConfig) DeepCopyInto(out *PrometheusRuleConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LinkDown != nil {
+		in, out := &in.LinkDown, &out.LinkDown
+		*out = new(LinkDownAlertConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RDMAErrors != nil {
+		in, out := &in.RDMAErrors, &out.RDMAErrors
+		*out = new(AlertRuleConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExporterDown != nil {
+		in, out := &in.ExporterDown, &out.ExporterDown
+		*out = new(AlertRuleConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriverUpgradeStuck != nil {
+		in, out := &in.DriverUpgradeStuck, &out.DriverUpgradeStuck
+		*out = new(AlertRuleConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DevicePluginUnhealthy != nil {
+		in, out := &in.DevicePluginUnhealthy, &out.DevicePluginUnhealthy
+		*out = new(AlertRuleConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRuleConfig.
+func (in *PrometheusRuleConfig) DeepCopy() *PrometheusRuleConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRuleConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RailSpec) DeepCopyInto(out *RailSpec) {
+	*out = *in
+	if in.PFNames != nil {
+		in, out := &in.PFNames, &out.PFNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RailSpec.
+func (in *RailSpec) DeepCopy() *RailSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RailSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegistryTLS) DeepCopyInto(out *RegistryTLS) {
 	*out = *in
@@ -711,6 +1899,102 @@ func (in *RegistryTLS) DeepCopy() *RegistryTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationSpec) DeepCopyInto(out *RemediationSpec) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Cordon != nil {
+		in, out := &in.Cordon, &out.Cordon
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeDrainPolicy != nil {
+		in, out := &in.NodeDrainPolicy, &out.NodeDrainPolicy
+		*out = new(DrainSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationSpec.
+func (in *RemediationSpec) DeepCopy() *RemediationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePoolSelectors) DeepCopyInto(out *ResourcePoolSelectors) {
+	*out = *in
+	if in.Vendors != nil {
+		in, out := &in.Vendors, &out.Vendors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubsystemDevices != nil {
+		in, out := &in.SubsystemDevices, &out.SubsystemDevices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drivers != nil {
+		in, out := &in.Drivers, &out.Drivers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PFNames != nil {
+		in, out := &in.PFNames, &out.PFNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePoolSelectors.
+func (in *ResourcePoolSelectors) DeepCopy() *ResourcePoolSelectors {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePoolSelectors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePoolSpec) DeepCopyInto(out *ResourcePoolSpec) {
+	*out = *in
+	in.Selectors.DeepCopyInto(&out.Selectors)
+	if in.EnableExporterHealthCheck != nil {
+		in, out := &in.EnableExporterHealthCheck, &out.EnableExporterHealthCheck
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePoolSpec.
+func (in *ResourcePoolSpec) DeepCopy() *ResourcePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecondaryNetworkSpec) DeepCopyInto(out *SecondaryNetworkSpec) {
 	*out = *in
@@ -719,6 +2003,18 @@ func (in *SecondaryNetworkSpec) DeepCopyInto(out *SecondaryNetworkSpec) {
 		*out = new(CniPluginsSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Multus != nil {
+		in, out := &in.Multus, &out.Multus
+		*out = new(MultusSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkAttachments != nil {
+		in, out := &in.NetworkAttachments, &out.NetworkAttachments
+		*out = make([]NetworkAttachmentSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecondaryNetworkSpec.
@@ -870,6 +2166,11 @@ func (in *UtilsContainerSpec) DeepCopyInto(out *UtilsContainerSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.PodCustomization != nil {
+		in, out := &in.PodCustomization, &out.PodCustomization
+		*out = new(PodCustomizationOverrideSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UtilsContainerSpec.
@@ -58,6 +58,7 @@ import (
 	"github.com/ROCm/network-operator/internal/kmmmodule"
 	"github.com/ROCm/network-operator/internal/secondarynetwork"
 	"github.com/ROCm/network-operator/internal/workermgr"
+	netattachdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	//+kubebuilder:scaffold:imports
@@ -76,6 +77,7 @@ func init() {
 	utilruntime.Must(kmmv1beta1.AddToScheme(scheme))
 	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(monitoringv1.AddToScheme(scheme))
+	utilruntime.Must(netattachdefv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -113,7 +115,7 @@ func main() {
 	}
 
 	client := mgr.GetClient()
-	isOpenShift := utils.IsOpenShift(setupLogger)
+	isOpenShift := utils.IsOpenShift(setupLogger, mgr.GetAPIReader())
 	kmmHandler := kmmmodule.NewKMMModule(client, scheme, isOpenShift)
 	nlHandler := nodelabeller.NewNodeLabeller(scheme, isOpenShift)
 	metricsHandler := metricsexporter.NewMetricsExporter(scheme)
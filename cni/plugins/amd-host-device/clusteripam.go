@@ -0,0 +1,458 @@
+/*
+Copyright (c) Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// defaultRailPool is the pool of the NICs not listed in the rails
+	defaultRailPool = "*"
+
+	// nodeNameFile is written next to the kubeconfig by the CNI plugins pod of the node
+	nodeNameFile = "node-name"
+
+	ipPoolUpdateRetries = 10
+	apiRequestTimeout   = 10 * time.Second
+)
+
+// errIPPoolNotFound is returned when the pool was deleted, e.g. with its NetworkConfig
+var errIPPoolNotFound = errors.New("IP pool not found")
+
+// ClusterIPAMConf is the clusterIPAM config rendered by the operator into the NetworkAttachmentDefinition
+type ClusterIPAMConf struct {
+	Namespace  string            `json:"namespace"`
+	Kubeconfig string            `json:"kubeconfig"`
+	Pools      map[string]string `json:"pools"`
+}
+
+// NICIPPool mirrors the fields of the NICIPPool custom resource of the operator used by the plugin
+type NICIPPool struct {
+	APIVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Spec       NICIPPoolSpec          `json:"spec"`
+}
+
+type NICIPPoolSpec struct {
+	Range       string                     `json:"range"`
+	RangeStart  string                     `json:"rangeStart,omitempty"`
+	RangeEnd    string                     `json:"rangeEnd,omitempty"`
+	Gateway     string                     `json:"gateway,omitempty"`
+	Allocations map[string]NICIPAllocation `json:"allocations,omitempty"`
+}
+
+type NICIPAllocation struct {
+	PodRef       string `json:"podRef,omitempty"`
+	PodUID       string `json:"podUID,omitempty"`
+	ContainerID  string `json:"containerID,omitempty"`
+	IfName       string `json:"ifName,omitempty"`
+	Node         string `json:"node,omitempty"`
+	HostReserved bool   `json:"hostReserved,omitempty"`
+}
+
+// kubeconfig is the subset of the JSON kubeconfig written by the CNI plugins pod
+type kubeconfig struct {
+	Clusters []struct {
+		Cluster struct {
+			Server                   string `json:"server"`
+			CertificateAuthorityData string `json:"certificate-authority-data"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Users []struct {
+		User struct {
+			Token string `json:"token"`
+		} `json:"user"`
+	} `json:"users"`
+}
+
+type apiClient struct {
+	kubeconfig string
+	server     string
+	token      string
+	client     *http.Client
+}
+
+func getClusterIPAMConf(cniConf map[string]interface{}) (*ClusterIPAMConf, error) {
+	raw, ok := cniConf["clusterIPAM"]
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	conf := &ClusterIPAMConf{}
+	if err := json.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("invalid clusterIPAM config: %v", err)
+	}
+	return conf, nil
+}
+
+// getPool returns the pool of the rail of the host interface
+func (c *ClusterIPAMConf) getPool(hostInterfaceName string) (string, error) {
+	if pool, ok := c.Pools[hostInterfaceName]; ok {
+		return pool, nil
+	}
+	if pool, ok := c.Pools[defaultRailPool]; ok {
+		return pool, nil
+	}
+	return "", fmt.Errorf("no IP pool is configured for interface %s", hostInterfaceName)
+}
+
+func newAPIClient(kubeconfigPath string) (*apiClient, error) {
+	data, err := os.ReadFile(kubeconfigPath)
+	if err != nil {
+		// the kubeconfig is removed while the CNI plugins pod of the node is not running
+		return nil, fmt.Errorf("failed to read kubeconfig %s, the CNI plugins pod of the operator must be running on the node: %v", kubeconfigPath, err)
+	}
+	kc := kubeconfig{}
+	if err := json.Unmarshal(data, &kc); err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %s: %v", kubeconfigPath, err)
+	}
+	if len(kc.Clusters) == 0 || len(kc.Users) == 0 {
+		return nil, fmt.Errorf("kubeconfig %s has no cluster or user", kubeconfigPath)
+	}
+	ca, err := base64.StdEncoding.DecodeString(kc.Clusters[0].Cluster.CertificateAuthorityData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate authority in kubeconfig %s: %v", kubeconfigPath, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificate authority found in kubeconfig %s", kubeconfigPath)
+	}
+	return &apiClient{
+		kubeconfig: kubeconfigPath,
+		server:     kc.Clusters[0].Cluster.Server,
+		token:      kc.Users[0].User.Token,
+		client: &http.Client{
+			Timeout:   apiRequestTimeout,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+		},
+	}, nil
+}
+
+func (c *apiClient) poolURL(namespace, name string) string {
+	return fmt.Sprintf("%s/apis/amd.com/v1alpha1/namespaces/%s/nicippools/%s", c.server, namespace, name)
+}
+
+func (c *apiClient) do(method, url string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return resp.StatusCode, nil, fmt.Errorf("the service account token of kubeconfig %s was rejected with %d, the CNI plugins pod of the operator must be running on the node",
+			c.kubeconfig, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, data, err
+}
+
+func (c *apiClient) getPool(namespace, name string) (*NICIPPool, error) {
+	status, data, err := c.do(http.MethodGet, c.poolURL(namespace, name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IP pool %s/%s: %v", namespace, name, err)
+	}
+	if status == http.StatusNotFound {
+		return nil, fmt.Errorf("failed to get IP pool %s/%s: %w", namespace, name, errIPPoolNotFound)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to get IP pool %s/%s: %d %s", namespace, name, status, data)
+	}
+	pool := &NICIPPool{}
+	if err := json.Unmarshal(data, pool); err != nil {
+		return nil, fmt.Errorf("failed to parse IP pool %s/%s: %v", namespace, name, err)
+	}
+	return pool, nil
+}
+
+// updatePool writes the pool with its resource version, false is returned if the pool was updated concurrently
+func (c *apiClient) updatePool(namespace, name string, pool *NICIPPool) (bool, error) {
+	body, err := json.Marshal(pool)
+	if err != nil {
+		return false, err
+	}
+	status, data, err := c.do(http.MethodPut, c.poolURL(namespace, name), body)
+	if err != nil {
+		return false, fmt.Errorf("failed to update IP pool %s/%s: %v", namespace, name, err)
+	}
+	switch status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusConflict:
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to update IP pool %s/%s: %d %s", namespace, name, status, data)
+}
+
+// modifyPool applies the change to the latest pool and retries on concurrent updates by other nodes
+func (c *apiClient) modifyPool(namespace, name string, modify func(pool *NICIPPool) (bool, error)) error {
+	for i := 0; i < ipPoolUpdateRetries; i++ {
+		pool, err := c.getPool(namespace, name)
+		if err != nil {
+			return err
+		}
+		if pool.Spec.Allocations == nil {
+			pool.Spec.Allocations = map[string]NICIPAllocation{}
+		}
+		changed, err := modify(pool)
+		if err != nil || !changed {
+			return err
+		}
+		updated, err := c.updatePool(namespace, name, pool)
+		if err != nil || updated {
+			return err
+		}
+		log.Printf("IP pool %s/%s was updated concurrently, retrying", namespace, name)
+		time.Sleep(time.Duration(100*(i+1)) * time.Millisecond)
+	}
+	return fmt.Errorf("failed to update IP pool %s/%s after %d retries", namespace, name, ipPoolUpdateRetries)
+}
+
+func getNodeName(kubeconfigPath string) string {
+	if data, err := os.ReadFile(filepath.Join(filepath.Dir(kubeconfigPath), nodeNameFile)); err == nil {
+		if name := strings.TrimSpace(string(data)); name != "" {
+			return name
+		}
+	}
+	name, _ := os.Hostname()
+	return strings.ToLower(name)
+}
+
+// getPodRef returns namespace/name and the UID of the pod from the CNI_ARGS set by the runtime
+func getPodRef(cniArgs string) (string, string) {
+	var namespace, name, uid string
+	for _, arg := range strings.Split(cniArgs, ";") {
+		key, val, _ := strings.Cut(arg, "=")
+		switch key {
+		case "K8S_POD_NAMESPACE":
+			namespace = val
+		case "K8S_POD_NAME":
+			name = val
+		case "K8S_POD_UID":
+			uid = val
+		}
+	}
+	return namespace + "/" + name, uid
+}
+
+// getHostAddresses returns the addresses configured on the interfaces of the node,
+// they are reserved in the pools so that they are never leased to the pods
+func getHostAddresses() []net.IP {
+	var ips []net.IP
+	addrs, err := netlink.AddrList(nil, netlink.FAMILY_ALL)
+	if err != nil {
+		log.Printf("failed to list the host addresses: %v", err)
+		return ips
+	}
+	for _, a := range addrs {
+		ips = append(ips, a.IP)
+	}
+	return ips
+}
+
+func ipToInt(ip net.IP) *big.Int {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return new(big.Int).SetBytes(ip)
+}
+
+func intToIP(i *big.Int, ipv4 bool) net.IP {
+	size := net.IPv6len
+	if ipv4 {
+		size = net.IPv4len
+	}
+	b := i.Bytes()
+	ip := make(net.IP, size)
+	copy(ip[size-len(b):], b)
+	return ip
+}
+
+// getLeaseRange returns the first and last address which can be leased from the pool
+func getLeaseRange(spec NICIPPoolSpec) (*net.IPNet, *big.Int, *big.Int, error) {
+	_, subnet, err := net.ParseCIDR(spec.Range)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid IP pool range %s: %v", spec.Range, err)
+	}
+	ones, bits := subnet.Mask.Size()
+	first := ipToInt(subnet.IP)
+	last := new(big.Int).Add(first, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits-ones)), big.NewInt(1)))
+	if bits == 32 && bits-ones > 1 {
+		// skip the network and broadcast addresses, /31 and /32 use all their addresses
+		first.Add(first, big.NewInt(1))
+		last.Sub(last, big.NewInt(1))
+	}
+	if start := net.ParseIP(spec.RangeStart); start != nil && ipToInt(start).Cmp(first) > 0 {
+		first = ipToInt(start)
+	}
+	if end := net.ParseIP(spec.RangeEnd); end != nil && ipToInt(end).Cmp(last) < 0 {
+		last = ipToInt(end)
+	}
+	return subnet, first, last, nil
+}
+
+// leaseClusterIPAMAddress leases an address to the pod interface from the pool of the rail of the host interface,
+// the addresses of the host interfaces of the node within the range are reserved first
+func leaseClusterIPAMAddress(conf *ClusterIPAMConf, hostInterfaceName, containerID, ifName, cniArgs string) (map[string]interface{}, error) {
+	poolName, err := conf.getPool(hostInterfaceName)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newAPIClient(conf.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	node := getNodeName(conf.Kubeconfig)
+	podRef, podUID := getPodRef(cniArgs)
+	hostIPs := getHostAddresses()
+
+	var address map[string]interface{}
+	err = client.modifyPool(conf.Namespace, poolName, func(pool *NICIPPool) (bool, error) {
+		address = nil
+		subnet, first, last, err := getLeaseRange(pool.Spec)
+		if err != nil {
+			return false, err
+		}
+		ones, _ := subnet.Mask.Size()
+		ipv4 := subnet.IP.To4() != nil
+		changed := false
+
+		for _, ip := range hostIPs {
+			if !subnet.Contains(ip) {
+				continue
+			}
+			key := ip.String()
+			if allocation, ok := pool.Spec.Allocations[key]; ok {
+				if allocation.HostReserved || allocation.Node == node {
+					continue
+				}
+				// the address was leased to a pod of another node before it was configured on this node
+				log.Printf("host address %s of node %s is leased to %s on node %s", key, node, allocation.PodRef, allocation.Node)
+				continue
+			}
+			pool.Spec.Allocations[key] = NICIPAllocation{Node: node, HostReserved: true}
+			changed = true
+		}
+
+		result := func(ip net.IP) map[string]interface{} {
+			entry := map[string]interface{}{
+				"address": (&net.IPNet{IP: ip, Mask: subnet.Mask}).String(),
+			}
+			if pool.Spec.Gateway != "" {
+				entry["gateway"] = pool.Spec.Gateway
+			}
+			return entry
+		}
+
+		// the runtime retries ADD with the same container ID, keep its lease
+		for key, allocation := range pool.Spec.Allocations {
+			if allocation.ContainerID == containerID && allocation.IfName == ifName {
+				address = result(net.ParseIP(key))
+				return changed, nil
+			}
+		}
+
+		gateway := net.ParseIP(pool.Spec.Gateway)
+		for i := new(big.Int).Set(first); i.Cmp(last) <= 0; i.Add(i, big.NewInt(1)) {
+			ip := intToIP(i, ipv4)
+			if gateway != nil && gateway.Equal(ip) {
+				continue
+			}
+			if _, ok := pool.Spec.Allocations[ip.String()]; ok {
+				continue
+			}
+			pool.Spec.Allocations[ip.String()] = NICIPAllocation{
+				PodRef:      podRef,
+				PodUID:      podUID,
+				ContainerID: containerID,
+				IfName:      ifName,
+				Node:        node,
+			}
+			address = result(ip)
+			log.Printf("leased %s/%d of IP pool %s to %s", ip, ones, poolName, podRef)
+			return true, nil
+		}
+		return false, fmt.Errorf("IP pool %s/%s is exhausted", conf.Namespace, poolName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// releaseClusterIPAMAddress releases the leases of the pod interface from all the pools of the network
+func releaseClusterIPAMAddress(conf *ClusterIPAMConf, containerID, ifName string) error {
+	client, err := newAPIClient(conf.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	released := map[string]bool{}
+	for _, poolName := range conf.Pools {
+		if released[poolName] {
+			continue
+		}
+		released[poolName] = true
+		err := client.modifyPool(conf.Namespace, poolName, func(pool *NICIPPool) (bool, error) {
+			changed := false
+			for key, allocation := range pool.Spec.Allocations {
+				if allocation.ContainerID == containerID && allocation.IfName == ifName {
+					log.Printf("released %s of IP pool %s", key, poolName)
+					delete(pool.Spec.Allocations, key)
+					changed = true
+				}
+			}
+			return changed, nil
+		})
+		// nothing is leased from a deleted pool
+		if err != nil && !errors.Is(err, errIPPoolNotFound) {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to release the leases of %s: %v", containerID, errs)
+	}
+	return nil
+}
@@ -115,8 +115,26 @@ func cmdAdd(args *skel.CmdArgs) error {
 		}
 	}
 
+	clusterIPAMConf, err := getClusterIPAMConf(cniConf)
+	if err != nil {
+		log.Printf("failed to get cluster IPAM config, err: %v", err)
+		return err
+	}
+
 	// 3. Create static IPAM config.
-	if len(addresses) > 0 {
+	if clusterIPAMConf != nil {
+		// lease the pod address from the cluster-wide IP pool of the rail instead of reusing the host addresses
+		address, err := leaseClusterIPAMAddress(clusterIPAMConf, hostInterfaceName, args.ContainerID, args.IfName, args.Args)
+		if err != nil {
+			log.Printf("failed to lease an address for %s, err: %v", hostInterfaceName, err)
+			return err
+		}
+		delete(cniConf, "clusterIPAM")
+		cniConf["ipam"] = map[string]interface{}{
+			"type":      "static",
+			"addresses": []map[string]interface{}{address},
+		}
+	} else if len(addresses) > 0 {
 		log.Printf("got IP addresses %v from host interface %s", addrs, hostInterfaceName)
 		ipamConf := map[string]interface{}{
 			"ipam": map[string]interface{}{
@@ -143,6 +161,11 @@ func cmdAdd(args *skel.CmdArgs) error {
 	executeResult, err := execPlugin("host-device", "ADD", cniConfBytes, args, true)
 	if err != nil {
 		log.Printf("failed to execute host-device plugin %v: %v", string(cniConfBytes), err)
+		if clusterIPAMConf != nil {
+			if err := releaseClusterIPAMAddress(clusterIPAMConf, args.ContainerID, args.IfName); err != nil {
+				log.Printf("failed to release the leased address, err: %v", err)
+			}
+		}
 		return err
 	}
 
@@ -202,7 +225,17 @@ func cmdDel(args *skel.CmdArgs) error {
 		}
 	}
 
-	// 4. Cleanup & Restore (If mapping exists, we must clean it up regardless of DEL success)
+	// 4. Release the lease of the cluster IPAM, a failure is returned after the restore so that the runtime retries DEL
+	var releaseErr error
+	if clusterIPAMConf, confErr := getClusterIPAMConf(cniConf); confErr != nil {
+		log.Printf("failed to get cluster IPAM config, err: %v", confErr)
+	} else if clusterIPAMConf != nil {
+		if releaseErr = releaseClusterIPAMAddress(clusterIPAMConf, args.ContainerID, args.IfName); releaseErr != nil {
+			log.Printf("failed to release the leased address, err: %v", releaseErr)
+		}
+	}
+
+	// 5. Cleanup & Restore (If mapping exists, we must clean it up regardless of DEL success)
 	if interfaceMappingFound && m != nil {
 		var errs []error
 		if err := amdHostDeviceCNI.configureHostInterface(m.HostInterfaceName, m.State, m.HostInterfaceIPs); err != nil {
@@ -218,7 +251,9 @@ func cmdDel(args *skel.CmdArgs) error {
 		}
 	}
 
-	return nil // CNI DEL should almost always return nil to allow pod teardown
+	// CNI DEL should almost always return nil to allow pod teardown,
+	// except for the cluster IPAM lease which would otherwise stay leased until the operator sweeps it
+	return releaseErr
 }
 
 func cmdCheck(args *skel.CmdArgs) error {
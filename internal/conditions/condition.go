@@ -26,4 +26,6 @@ type ConditionUpdater interface {
 	SetErrorCondition(cr any, status metav1.ConditionStatus, reason string, message string)
 	DeleteReadyCondition(cr any)
 	DeleteErrorCondition(cr any)
+	SetMultusReadyCondition(cr any, status metav1.ConditionStatus, reason string, message string)
+	DeleteMultusReadyCondition(cr any)
 }
@@ -25,6 +25,8 @@ import (
 const (
 	ConditionTypeReady = "Ready"
 	ConditionTypeError = "Error"
+	// ConditionTypeMultusReady reports the rollout of the Multus thick plugin deployed by the operator
+	ConditionTypeMultusReady = "MultusReady"
 )
 
 // Condition Reason
@@ -35,6 +37,12 @@ const (
 	ErrorStatus = "Error"
 	// ReadyStatus represents operator in ready and healthy state
 	ReadyStatus = "OperatorReady"
+	// MultusReady is the reason when Multus is ready on all selected nodes
+	MultusReady = "MultusReady"
+	// MultusNotReady is the reason when Multus is not yet ready on some of the selected nodes
+	MultusNotReady = "MultusNotReady"
+	// MultusProvidedByPlatform is the reason when Multus is part of the cluster network (OpenShift)
+	MultusProvidedByPlatform = "MultusProvidedByPlatform"
 )
 
 type ConditionManager struct{}
@@ -80,6 +88,22 @@ func (cm *ConditionManager) DeleteErrorCondition(cr any) {
 	deleteCondition(&nwConfig.Status.Conditions, ConditionTypeError)
 }
 
+func (cm *ConditionManager) SetMultusReadyCondition(cr any, status metav1.ConditionStatus, reason string, message string) {
+	nwConfig := cr.(*amdv1alpha1.NetworkConfig)
+	setCondition(nwConfig, metav1.Condition{
+		Type:               ConditionTypeMultusReady,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func (cm *ConditionManager) DeleteMultusReadyCondition(cr any) {
+	nwConfig := cr.(*amdv1alpha1.NetworkConfig)
+	deleteCondition(&nwConfig.Status.Conditions, ConditionTypeMultusReady)
+}
+
 func setCondition(nwConfig *amdv1alpha1.NetworkConfig, newCondition metav1.Condition) {
 	existingCondition := findCondition(nwConfig.Status.Conditions, newCondition.Type)
 
@@ -0,0 +1,41 @@
+/*
+Copyright (c) Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+// Node Condition Type
+const (
+	// NodeConditionTypeAMDNetworkReady tells whether the node is ready to run AMD NIC workloads
+	NodeConditionTypeAMDNetworkReady = "AMDNetworkReady"
+)
+
+// Node Condition Reason
+const (
+	// NetworkReady means the driver, device plugin, NIC resources and CNI plugins are all ready on the node
+	NetworkReady = "NetworkReady"
+	// DriverNotReady means the driver is not loaded at the desired version
+	DriverNotReady = "DriverNotReady"
+	// DevicePluginNotRegistered means the device plugin didn't register any NIC resource
+	DevicePluginNotRegistered = "DevicePluginNotRegistered"
+	// NICCountMismatch means fewer NIC resources are allocatable than expected
+	NICCountMismatch = "NICCountMismatch"
+	// CNIPluginsNotReady means the CNI plugins are not installed on the node
+	CNIPluginsNotReady = "CNIPluginsNotReady"
+	// DRADriverNotReady means the DRA driver is not running on the node
+	DRADriverNotReady = "DRADriverNotReady"
+	// HostConfigNotInSync means the host config is not applied on the node yet
+	HostConfigNotInSync = "HostConfigNotInSync"
+)
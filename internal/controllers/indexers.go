@@ -0,0 +1,108 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Copyright (c) Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	nlinternal "github.com/ROCm/network-operator/internal/nodelabeller"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// podNodeNameIndexKey indexes the cached pods by the node they are scheduled on
+	podNodeNameIndexKey = "spec.nodeName"
+	// networkConfigSelectorIndexKey indexes the cached NetworkConfigs by each key=value pair of their node selector
+	networkConfigSelectorIndexKey = "spec.selector"
+	// selectAllNodesIndexValue is indexed for the NetworkConfigs without node selector, which select all nodes
+	selectAllNodesIndexValue = "*"
+)
+
+// setupIndexers registers the field indexers on the manager cache
+// so that the lookups in reconcile don't need to scan all the cached objects
+func setupIndexers(ctx context.Context, indexer client.FieldIndexer) error {
+	if err := indexer.IndexField(ctx, &v1.Pod{}, podNodeNameIndexKey, func(obj client.Object) []string {
+		pod, ok := obj.(*v1.Pod)
+		if !ok || pod.Spec.NodeName == "" {
+			return nil
+		}
+		return []string{pod.Spec.NodeName}
+	}); err != nil {
+		return err
+	}
+	return indexer.IndexField(ctx, &amdv1alpha1.NetworkConfig{}, networkConfigSelectorIndexKey, func(obj client.Object) []string {
+		nwConfig, ok := obj.(*amdv1alpha1.NetworkConfig)
+		if !ok {
+			return nil
+		}
+		values := []string{}
+		for _, selector := range getNetworkConfigSelectors(nwConfig) {
+			for _, value := range getSelectorIndexValues(selector) {
+				if !slices.Contains(values, value) {
+					values = append(values, value)
+				}
+			}
+		}
+		return values
+	})
+}
+
+// getNetworkConfigSelectors returns the node selectors of the NetworkConfig, including the node labeller override
+func getNetworkConfigSelectors(nwConfig *amdv1alpha1.NetworkConfig) []map[string]string {
+	selectors := []map[string]string{nwConfig.Spec.Selector}
+	if nlSelector := nlinternal.GetNodeLabellerSpec(nwConfig).Selector; !maps.Equal(nlSelector, nwConfig.Spec.Selector) {
+		selectors = append(selectors, nlSelector)
+	}
+	return selectors
+}
+
+// getSelectorIndexValues returns the selector index values for a node selector
+func getSelectorIndexValues(selector map[string]string) []string {
+	if len(selector) == 0 {
+		return []string{selectAllNodesIndexValue}
+	}
+	values := make([]string, 0, len(selector))
+	for k, v := range selector {
+		values = append(values, getSelectorIndexValue(k, v))
+	}
+	return values
+}
+
+func getSelectorIndexValue(key, value string) string {
+	return fmt.Sprintf("%s=%s", key, value)
+}
@@ -118,6 +118,34 @@ func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) finalizeNetworkConfi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "finalizeNetworkConfig", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).finalizeNetworkConfig), ctx, nwConfig, nodes)
 }
 
+// findNetworkConfigsForConfigMap mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) findNetworkConfigsForConfigMap(ctx context.Context, cm client.Object) []reconcile.Request {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "findNetworkConfigsForConfigMap", ctx, cm)
+	ret0, _ := ret[0].([]reconcile.Request)
+	return ret0
+}
+
+// findNetworkConfigsForConfigMap indicates an expected call of findNetworkConfigsForConfigMap.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) findNetworkConfigsForConfigMap(ctx, cm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findNetworkConfigsForConfigMap", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).findNetworkConfigsForConfigMap), ctx, cm)
+}
+
+// findNetworkConfigsForIPPoolPod mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) findNetworkConfigsForIPPoolPod(ctx context.Context, pod client.Object) []reconcile.Request {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "findNetworkConfigsForIPPoolPod", ctx, pod)
+	ret0, _ := ret[0].([]reconcile.Request)
+	return ret0
+}
+
+// findNetworkConfigsForIPPoolPod indicates an expected call of findNetworkConfigsForIPPoolPod.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) findNetworkConfigsForIPPoolPod(ctx, pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findNetworkConfigsForIPPoolPod", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).findNetworkConfigsForIPPoolPod), ctx, pod)
+}
+
 // findNetworkConfigsForNMC mocks base method.
 func (m *MocknetworkConfigReconcilerHelperAPI) findNetworkConfigsForNMC(ctx context.Context, nmc client.Object) []reconcile.Request {
 	m.ctrl.T.Helper()
@@ -132,32 +160,46 @@ func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) findNetworkConfigsFo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findNetworkConfigsForNMC", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).findNetworkConfigsForNMC), ctx, nmc)
 }
 
-// findNetworkConfigsForSecret mocks base method.
-func (m *MocknetworkConfigReconcilerHelperAPI) findNetworkConfigsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+// findNetworkConfigsForNamespace mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) findNetworkConfigsForNamespace(ctx context.Context, ns client.Object) []reconcile.Request {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "findNetworkConfigsForSecret", ctx, secret)
+	ret := m.ctrl.Call(m, "findNetworkConfigsForNamespace", ctx, ns)
 	ret0, _ := ret[0].([]reconcile.Request)
 	return ret0
 }
 
-// findNetworkConfigsForSecret indicates an expected call of findNetworkConfigsForSecret.
-func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) findNetworkConfigsForSecret(ctx, secret any) *gomock.Call {
+// findNetworkConfigsForNamespace indicates an expected call of findNetworkConfigsForNamespace.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) findNetworkConfigsForNamespace(ctx, ns any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findNetworkConfigsForSecret", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).findNetworkConfigsForSecret), ctx, secret)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findNetworkConfigsForNamespace", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).findNetworkConfigsForNamespace), ctx, ns)
 }
 
-// findNetworkConfigsWithKMM mocks base method.
-func (m *MocknetworkConfigReconcilerHelperAPI) findNetworkConfigsWithKMM(ctx context.Context, node client.Object) []reconcile.Request {
+// findNetworkConfigsForNode mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) findNetworkConfigsForNode(ctx context.Context, node *v1.Node) []reconcile.Request {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "findNetworkConfigsWithKMM", ctx, node)
+	ret := m.ctrl.Call(m, "findNetworkConfigsForNode", ctx, node)
 	ret0, _ := ret[0].([]reconcile.Request)
 	return ret0
 }
 
-// findNetworkConfigsWithKMM indicates an expected call of findNetworkConfigsWithKMM.
-func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) findNetworkConfigsWithKMM(ctx, node any) *gomock.Call {
+// findNetworkConfigsForNode indicates an expected call of findNetworkConfigsForNode.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) findNetworkConfigsForNode(ctx, node any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findNetworkConfigsWithKMM", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).findNetworkConfigsWithKMM), ctx, node)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findNetworkConfigsForNode", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).findNetworkConfigsForNode), ctx, node)
+}
+
+// findNetworkConfigsForSecret mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) findNetworkConfigsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "findNetworkConfigsForSecret", ctx, secret)
+	ret0, _ := ret[0].([]reconcile.Request)
+	return ret0
+}
+
+// findNetworkConfigsForSecret indicates an expected call of findNetworkConfigsForSecret.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) findNetworkConfigsForSecret(ctx, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "findNetworkConfigsForSecret", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).findNetworkConfigsForSecret), ctx, secret)
 }
 
 // getNetworkConfigOwnedKMMModule mocks base method.
@@ -204,6 +246,20 @@ func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleBuildConfigMap
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleBuildConfigMap", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleBuildConfigMap), ctx, nwConfig, nodes)
 }
 
+// handleDRADriver mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) handleDRADriver(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "handleDRADriver", ctx, nwConfig, nodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// handleDRADriver indicates an expected call of handleDRADriver.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleDRADriver(ctx, nwConfig, nodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleDRADriver", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleDRADriver), ctx, nwConfig, nodes)
+}
+
 // handleDevicePlugin mocks base method.
 func (m *MocknetworkConfigReconcilerHelperAPI) handleDevicePlugin(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, isOpenShift bool) error {
 	m.ctrl.T.Helper()
@@ -218,6 +274,34 @@ func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleDevicePlugin(c
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleDevicePlugin", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleDevicePlugin), ctx, nwConfig, isOpenShift)
 }
 
+// handleGPUAffinity mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) handleGPUAffinity(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "handleGPUAffinity", ctx, nwConfig, nodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// handleGPUAffinity indicates an expected call of handleGPUAffinity.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleGPUAffinity(ctx, nwConfig, nodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleGPUAffinity", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleGPUAffinity), ctx, nwConfig, nodes)
+}
+
+// handleHostConfig mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) handleHostConfig(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, nodes *v1.NodeList, isOpenShift bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "handleHostConfig", ctx, nwConfig, nodes, isOpenShift)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// handleHostConfig indicates an expected call of handleHostConfig.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleHostConfig(ctx, nwConfig, nodes, isOpenShift any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleHostConfig", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleHostConfig), ctx, nwConfig, nodes, isOpenShift)
+}
+
 // handleKMMModule mocks base method.
 func (m *MocknetworkConfigReconcilerHelperAPI) handleKMMModule(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, nodes *v1.NodeList) error {
 	m.ctrl.T.Helper()
@@ -246,6 +330,20 @@ func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleKMMVersionLabe
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleKMMVersionLabel", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleKMMVersionLabel), ctx, nwConfig, nodes)
 }
 
+// handleKmodSignatureVerification mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) handleKmodSignatureVerification(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "handleKmodSignatureVerification", ctx, nwConfig, nodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// handleKmodSignatureVerification indicates an expected call of handleKmodSignatureVerification.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleKmodSignatureVerification(ctx, nwConfig, nodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleKmodSignatureVerification", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleKmodSignatureVerification), ctx, nwConfig, nodes)
+}
+
 // handleMetricsExporter mocks base method.
 func (m *MocknetworkConfigReconcilerHelperAPI) handleMetricsExporter(ctx context.Context, nwConfig *v1alpha1.NetworkConfig) error {
 	m.ctrl.T.Helper()
@@ -289,18 +387,47 @@ func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleNodeLabeller(c
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleNodeLabeller", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleNodeLabeller), ctx, nwConfig, nodes, isOpenShift)
 }
 
+// handleNodeReadiness mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) handleNodeReadiness(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "handleNodeReadiness", ctx, nwConfig, nodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// handleNodeReadiness indicates an expected call of handleNodeReadiness.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleNodeReadiness(ctx, nwConfig, nodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleNodeReadiness", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleNodeReadiness), ctx, nwConfig, nodes)
+}
+
+// handleNodeRemediation mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) handleNodeRemediation(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, nodes *v1.NodeList) (controllerruntime.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "handleNodeRemediation", ctx, nwConfig, nodes)
+	ret0, _ := ret[0].(controllerruntime.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// handleNodeRemediation indicates an expected call of handleNodeRemediation.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleNodeRemediation(ctx, nwConfig, nodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleNodeRemediation", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleNodeRemediation), ctx, nwConfig, nodes)
+}
+
 // handleSecondaryNetwork mocks base method.
-func (m *MocknetworkConfigReconcilerHelperAPI) handleSecondaryNetwork(ctx context.Context, nwConfig *v1alpha1.NetworkConfig) error {
+func (m *MocknetworkConfigReconcilerHelperAPI) handleSecondaryNetwork(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, isOpenShift bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "handleSecondaryNetwork", ctx, nwConfig)
+	ret := m.ctrl.Call(m, "handleSecondaryNetwork", ctx, nwConfig, isOpenShift)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // handleSecondaryNetwork indicates an expected call of handleSecondaryNetwork.
-func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleSecondaryNetwork(ctx, nwConfig any) *gomock.Call {
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) handleSecondaryNetwork(ctx, nwConfig, isOpenShift any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleSecondaryNetwork", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleSecondaryNetwork), ctx, nwConfig)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleSecondaryNetwork", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).handleSecondaryNetwork), ctx, nwConfig, isOpenShift)
 }
 
 // listNetworkConfigs mocks base method.
@@ -318,6 +445,21 @@ func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) listNetworkConfigs(c
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "listNetworkConfigs", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).listNetworkConfigs), ctx)
 }
 
+// listNodes mocks base method.
+func (m *MocknetworkConfigReconcilerHelperAPI) listNodes(ctx context.Context, nwConfig *v1alpha1.NetworkConfig) (*v1.NodeList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "listNodes", ctx, nwConfig)
+	ret0, _ := ret[0].(*v1.NodeList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// listNodes indicates an expected call of listNodes.
+func (mr *MocknetworkConfigReconcilerHelperAPIMockRecorder) listNodes(ctx, nwConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "listNodes", reflect.TypeOf((*MocknetworkConfigReconcilerHelperAPI)(nil).listNodes), ctx, nwConfig)
+}
+
 // setCondition mocks base method.
 func (m *MocknetworkConfigReconcilerHelperAPI) setCondition(ctx context.Context, condition string, nwConfig *v1alpha1.NetworkConfig, status v10.ConditionStatus, reason, message string) error {
 	m.ctrl.T.Helper()
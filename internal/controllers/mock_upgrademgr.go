@@ -481,11 +481,12 @@ func (mr *MockupgradeMgrHelperAPIMockRecorder) isNodeNmcStatusMissing(ctx, node,
 }
 
 // isNodeReady mocks base method.
-func (m *MockupgradeMgrHelperAPI) isNodeReady(ctx context.Context, node *v1.Node, networkConfig *v1alpha1.NetworkConfig) bool {
+func (m *MockupgradeMgrHelperAPI) isNodeReady(ctx context.Context, node *v1.Node, networkConfig *v1alpha1.NetworkConfig) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "isNodeReady", ctx, node, networkConfig)
 	ret0, _ := ret[0].(bool)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // isNodeReady indicates an expected call of isNodeReady.
@@ -34,11 +34,17 @@ package controllers
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ROCm/common-infra-operator/pkg/deviceplugin"
 	"github.com/ROCm/common-infra-operator/pkg/metricsexporter"
@@ -53,19 +59,28 @@ import (
 	"github.com/ROCm/network-operator/internal/conditions"
 	"github.com/ROCm/network-operator/internal/controllers/watchers"
 	dpinternal "github.com/ROCm/network-operator/internal/deviceplugin"
+	drainternal "github.com/ROCm/network-operator/internal/dra"
+	hostconfiginternal "github.com/ROCm/network-operator/internal/hostconfig"
 	"github.com/ROCm/network-operator/internal/kmmmodule"
 	expinternal "github.com/ROCm/network-operator/internal/metricsexporter"
 	nlinternal "github.com/ROCm/network-operator/internal/nodelabeller"
 	"github.com/ROCm/network-operator/internal/secondarynetwork"
+	"github.com/ROCm/network-operator/internal/topology"
 	"github.com/ROCm/network-operator/internal/validator"
 	"github.com/ROCm/network-operator/internal/workermgr"
+	netattachdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -81,6 +96,13 @@ import (
 const (
 	NetworkConfigReconcilerName = "DriverAndPluginReconciler"
 	networkConfigFinalizer      = "amd.node.kubernetes.io/networkconfig-finalizer"
+	// kmodSigningCertKey is the key of the certificate within the imageSign cert secret, same as KMM expects
+	kmodSigningCertKey    = "cert"
+	kmodSignaturePending  = "Pending"
+	kmodSignatureVerified = "Verified"
+	kmodSignatureFailed   = "Failed"
+	// metricsCertCheckInterval is the period the kube-rbac-proxy certificates issued by the operator are checked for renewal
+	metricsCertCheckInterval = time.Hour
 )
 
 // ModuleReconciler reconciles a Module object
@@ -117,7 +139,10 @@ func NewNetworkConfigReconciler(
 //     the NetworkConfig object in their ref field need to be reconciled
 //  2. findNetworkConfigsForNMC: when a NMC changed, only trigger reconcile for related NetworkConfig
 func (r *NetworkConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := setupIndexers(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return fmt.Errorf("failed to setup indexers: %v", err)
+	}
+	bldr := ctrl.NewControllerManagedBy(mgr).
 		For(&amdv1alpha1.NetworkConfig{}).
 		Owns(&kmmv1beta1.Module{}).
 		Owns(&appsv1.DaemonSet{}).
@@ -144,15 +169,66 @@ func (r *NetworkConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				},
 			),
 		).
-		Watches(&v1.Node{}, // watch for Node resource to get latest kernel mapping for KMM CR
-			handler.EnqueueRequestsFromMapFunc(r.helper.findNetworkConfigsWithKMM),
-			builder.WithPredicates(NodeKernelVersionPredicate{}),
+		Watches(&v1.ConfigMap{}, // watch for user provided ConfigMaps referenced by NetworkConfig
+			handler.EnqueueRequestsFromMapFunc(r.helper.findNetworkConfigsForConfigMap),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(&v1.Node{}, // watch for Node changes affecting the NetworkConfigs selecting the node
+			newNodeEventHandler(r.helper.findNetworkConfigsForNode),
+			builder.WithPredicates(NodePredicate{}),
 		).
 		Watches( // watch pod event to auto-clean unknown status builder pod and cleanup workermgr pod
 			&v1.Pod{},
 			r.podEventHandler,
 			builder.WithPredicates(watchers.PodLabelPredicate{}),
-		).Complete(r)
+		).
+		Watches( // watch the deletion of the pods attached to secondary networks to release their NICIPPool leases
+			&v1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.helper.findNetworkConfigsForIPPoolPod),
+			builder.WithPredicates(
+				predicate.Funcs{
+					CreateFunc: func(e event.CreateEvent) bool {
+						return false
+					},
+					UpdateFunc: func(e event.UpdateEvent) bool {
+						return false
+					},
+					DeleteFunc: func(e event.DeleteEvent) bool {
+						_, ok := e.Object.GetAnnotations()[secondarynetwork.NetworkSelectionAnnotation]
+						return ok
+					},
+					GenericFunc: func(e event.GenericEvent) bool {
+						return false
+					},
+				},
+			),
+		).
+		Watches( // watch the creation of the namespaces of the network attachments to create their NetworkAttachmentDefinitions
+			&v1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.helper.findNetworkConfigsForNamespace),
+			builder.WithPredicates(
+				predicate.Funcs{
+					CreateFunc: func(e event.CreateEvent) bool {
+						return true
+					},
+					UpdateFunc: func(e event.UpdateEvent) bool {
+						return false
+					},
+					DeleteFunc: func(e event.DeleteEvent) bool {
+						return false
+					},
+					GenericFunc: func(e event.GenericEvent) bool {
+						return false
+					},
+				},
+			),
+		)
+	// the PrometheusRule CRD is only served when the Prometheus Operator is installed
+	if _, err := mgr.GetRESTMapper().RESTMapping(monitoringv1.SchemeGroupVersion.WithKind(monitoringv1.PrometheusRuleKind).GroupKind(),
+		monitoringv1.SchemeGroupVersion.Version); err == nil {
+		bldr = bldr.Owns(&monitoringv1.PrometheusRule{})
+	}
+	return bldr.Complete(r)
 }
 
 func (r *NetworkConfigReconciler) init(ctx context.Context) {
@@ -168,6 +244,8 @@ func (r *NetworkConfigReconciler) init(ctx context.Context) {
 //+kubebuilder:rbac:groups=amd.com,resources=networkconfigs,verbs=get;list;watch;create;patch;update
 //+kubebuilder:rbac:groups=amd.com,resources=networkconfigs/status,verbs=get;patch;update
 //+kubebuilder:rbac:groups=amd.com,resources=networkconfigs/finalizers,verbs=update
+//+kubebuilder:rbac:groups=amd.com,resources=nicippools,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=amd.com,resources=noderemediations,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modules,verbs=get;list;watch;create;patch;update;delete
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modules/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=modules/finalizers,verbs=get;update;watch
@@ -176,7 +254,7 @@ func (r *NetworkConfigReconciler) init(ctx context.Context) {
 //+kubebuilder:rbac:groups=kmm.sigs.x-k8s.io,resources=nodemodulesconfigs/finalizers,verbs=get;update;watch
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=create;delete;get;list;patch;watch;create
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;patch;list;watch
-//+kubebuilder:rbac:groups=core,resources=nodes/status,verbs=get;update;watch
+//+kubebuilder:rbac:groups=core,resources=nodes/status,verbs=get;patch;update;watch
 //+kubebuilder:rbac:groups=core,resources=nodes/finalizers,verbs=get;update;watch
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=create;delete;get;list;patch;watch
 //+kubebuilder:rbac:groups=apps,resources=daemonsets/status,verbs=create;delete;get;list;patch;watch
@@ -187,9 +265,17 @@ func (r *NetworkConfigReconciler) init(ctx context.Context) {
 //+kubebuilder:rbac:groups=core,resources=pods/status,verbs=delete;get;list;watch
 //+kubebuilder:rbac:groups=core,resources=pods/finalizers,verbs=delete;get;list;watch
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=create;delete;get;list;patch;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=delete;get;list;create
 //+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;delete
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=resource.k8s.io,resources=deviceclasses,verbs=create;delete;get;list;patch;update;watch
+//+kubebuilder:rbac:groups=resource.k8s.io,resources=resourceslices,verbs=delete;deletecollection;get;list;watch
+//+kubebuilder:rbac:groups=k8s.cni.cncf.io,resources=network-attachment-definitions,verbs=create;delete;get;list;patch;update;watch
+//+kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigs,verbs=create;delete;get;list;patch;update;watch
+//+kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigpools,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates;issuers,verbs=create;delete;get;list;patch;update;watch
 
 func (r *NetworkConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	res := ctrl.Result{}
@@ -213,7 +299,7 @@ func (r *NetworkConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return res, fmt.Errorf("failed to get the requested %s CR: %v", req.NamespacedName, err)
 	}
 
-	nodes, err := kmmmodule.GetK8SNodes(kmmmodule.MapToLabelSelector(nwConfig.Spec.Selector))
+	nodes, err := r.helper.listNodes(ctx, nwConfig)
 	if err != nil {
 		return res, fmt.Errorf("failed to list Node for NetworkConfig %s: %v", req.NamespacedName, err)
 	}
@@ -267,6 +353,12 @@ func (r *NetworkConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return res, fmt.Errorf("failed to handle build ConfigMap for NetworkConfig %s: %v", req.NamespacedName, err)
 	}
 
+	// the host config carries the blacklist and modprobe options, apply it before the driver is loaded
+	logger.Info("start host config reconciliation")
+	if err = r.helper.handleHostConfig(ctx, nwConfig, nodes, r.isOpenShift); err != nil {
+		return res, fmt.Errorf("failed to handle host config for NetworkConfig %s: %v", req.NamespacedName, err)
+	}
+
 	logger.Info("start module install/upgrade reconciliation")
 	res, err = r.helper.handleModuleUpgrade(ctx, nwConfig, nodes, false)
 	if err != nil {
@@ -278,11 +370,26 @@ func (r *NetworkConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return res, fmt.Errorf("failed to handle KMM module for NetworkConfig %s: %v", req.NamespacedName, err)
 	}
 
+	logger.Info("start kmod signature verification reconciliation")
+	if err = r.helper.handleKmodSignatureVerification(ctx, nwConfig, nodes); err != nil {
+		return res, fmt.Errorf("failed to handle kmod signature verification for NetworkConfig %s: %v", req.NamespacedName, err)
+	}
+
+	logger.Info("start NIC to GPU topology reconciliation")
+	if err = r.helper.handleGPUAffinity(ctx, nwConfig, nodes); err != nil {
+		return res, fmt.Errorf("failed to handle NIC to GPU topology for NetworkConfig %s: %v", req.NamespacedName, err)
+	}
+
 	logger.Info("start device-plugin reconciliation")
 	if err = r.helper.handleDevicePlugin(ctx, nwConfig, r.isOpenShift); err != nil {
 		return res, fmt.Errorf("failed to handle device-plugin for NetworkConfig %s: %v", req.NamespacedName, err)
 	}
 
+	logger.Info("start DRA driver reconciliation")
+	if err = r.helper.handleDRADriver(ctx, nwConfig, nodes); err != nil {
+		return res, fmt.Errorf("failed to handle DRA driver for NetworkConfig %s: %v", req.NamespacedName, err)
+	}
+
 	logger.Info("start kmm mod version label reconciliation")
 	err = r.helper.handleKMMVersionLabel(ctx, nwConfig, nodes)
 	if err != nil {
@@ -299,9 +406,13 @@ func (r *NetworkConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if err := r.helper.handleMetricsExporter(ctx, nwConfig); err != nil {
 		return res, fmt.Errorf("failed to handle metrics exporter for NetworkConfig %s: %v", req.NamespacedName, err)
 	}
+	if utils.IsKubeRbacAutoTLSEnable(nwConfig) && (res.RequeueAfter == 0 || res.RequeueAfter > metricsCertCheckInterval) {
+		// the certificates issued by the operator are renewed on reconcile
+		res.RequeueAfter = metricsCertCheckInterval
+	}
 
 	logger.Info("start secondary network plugins reconciliation")
-	if err := r.helper.handleSecondaryNetwork(ctx, nwConfig); err != nil {
+	if err := r.helper.handleSecondaryNetwork(ctx, nwConfig, r.isOpenShift); err != nil {
 		return res, fmt.Errorf("failed to handle secondary network for NetworkConfig %s: %v", req.NamespacedName, err)
 	}
 	/*--- To be enabled later
@@ -320,21 +431,40 @@ func (r *NetworkConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return res, fmt.Errorf("failed to build status for NetworkConfig %s: %v", req.NamespacedName, err)
 	}
 
+	// the node readiness and remediation failures are returned after the status update
+	var errs []error
+	logger.Info("start node readiness reconciliation")
+	if err := r.helper.handleNodeReadiness(ctx, nwConfig, nodes); err != nil {
+		errs = append(errs, fmt.Errorf("failed to handle node readiness for NetworkConfig %s: %v", req.NamespacedName, err))
+	}
+
+	logger.Info("start node remediation reconciliation")
+	remediationRes, err := r.helper.handleNodeRemediation(ctx, nwConfig, nodes)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to handle node remediation for NetworkConfig %s: %v", req.NamespacedName, err))
+	}
+	if remediationRes.RequeueAfter > 0 && (res.RequeueAfter == 0 || res.RequeueAfter > remediationRes.RequeueAfter) {
+		// the pending remediations and node drains are evaluated again
+		res.RequeueAfter = remediationRes.RequeueAfter
+	}
+
 	err = r.helper.updateNetworkConfigStatus(ctx, nwConfig)
 	if err != nil {
-		return res, fmt.Errorf("failed to update status for NetworkConfig %s: %v", req.NamespacedName, err)
+		errs = append(errs, fmt.Errorf("failed to update status for NetworkConfig %s: %v", req.NamespacedName, err))
+		return res, errors.Join(errs...)
 	}
 
 	// Update nodeAssignments after NetworkConfig status update
 	r.helper.updateNodeAssignments(req.NamespacedName.String(), nodes, false)
 
-	return res, nil
+	return res, errors.Join(errs...)
 }
 
 //go:generate mockgen -source=network_config_reconciler.go -package=controllers -destination=mock_network_config_reconciler.go networkConfigReconcilerHelperAPI
 type networkConfigReconcilerHelperAPI interface {
 	getRequestedNetworkConfig(ctx context.Context, namespacedName types.NamespacedName) (*amdv1alpha1.NetworkConfig, error)
 	listNetworkConfigs(ctx context.Context) (*amdv1alpha1.NetworkConfigList, error)
+	listNodes(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) (*v1.NodeList, error)
 	buildNodeAssignments(networkConfigList *amdv1alpha1.NetworkConfigList) error
 	validateNodeAssignments(namespacedName string, nodes *v1.NodeList) error
 	updateNodeAssignments(namespacedName string, nodes *v1.NodeList, isFinalizer bool)
@@ -344,15 +474,24 @@ type networkConfigReconcilerHelperAPI interface {
 	finalizeNetworkConfig(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
 	findNetworkConfigsForNMC(ctx context.Context, nmc client.Object) []reconcile.Request
 	findNetworkConfigsForSecret(ctx context.Context, secret client.Object) []reconcile.Request
-	findNetworkConfigsWithKMM(ctx context.Context, node client.Object) []reconcile.Request
+	findNetworkConfigsForConfigMap(ctx context.Context, cm client.Object) []reconcile.Request
+	findNetworkConfigsForNode(ctx context.Context, node *v1.Node) []reconcile.Request
+	findNetworkConfigsForIPPoolPod(ctx context.Context, pod client.Object) []reconcile.Request
+	findNetworkConfigsForNamespace(ctx context.Context, ns client.Object) []reconcile.Request
 	setFinalizer(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error
 	handleKMMModule(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
+	handleKmodSignatureVerification(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
+	handleGPUAffinity(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
 	handleDevicePlugin(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, isOpenShift bool) error
+	handleDRADriver(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
+	handleHostConfig(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList, isOpenShift bool) error
 	handleKMMVersionLabel(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
 	handleBuildConfigMap(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
 	handleNodeLabeller(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList, isOpenShift bool) error
 	handleMetricsExporter(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error
-	handleSecondaryNetwork(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error
+	handleSecondaryNetwork(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, isOpenShift bool) error
+	handleNodeReadiness(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
+	handleNodeRemediation(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) (ctrl.Result, error)
 	setCondition(ctx context.Context, condition string, nwConfig *amdv1alpha1.NetworkConfig, status metav1.ConditionStatus, reason string, message string) error
 	deleteCondition(ctx context.Context, condition string, nwConfig *amdv1alpha1.NetworkConfig) error
 	validateNetworkConfig(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) []string
@@ -372,6 +511,8 @@ type networkConfigReconcilerHelper struct {
 	upgradeMgrHandler       upgradeMgrAPI
 	workerMgr               workermgr.WorkerMgrAPI
 	namespace               string
+	// kmodSignatureErrors maps the NetworkConfig namespaced name to the nodes whose verification failed to start
+	kmodSignatureErrors map[string]map[string]string
 }
 
 func newNetworkConfigReconcilerHelper(client client.Client,
@@ -392,6 +533,7 @@ func newNetworkConfigReconcilerHelper(client client.Client,
 		devicepluginHandler:     devicepluginHandler,
 		secondaryNetworkHandler: secondaryNetworkHandler,
 		nodeAssignments:         make(map[string]string),
+		kmodSignatureErrors:     make(map[string]map[string]string),
 		conditionUpdater:        conditionUpdater,
 		validator:               validator,
 		upgradeMgrHandler:       upgradeMgrHandler,
@@ -410,6 +552,17 @@ func (dcrh *networkConfigReconcilerHelper) listNetworkConfigs(ctx context.Contex
 	return &nwConfigList, nil
 }
 
+// listNodes lists the nodes selected by the NetworkConfig from the manager cache
+func (dcrh *networkConfigReconcilerHelper) listNodes(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) (*v1.NodeList, error) {
+	nodes := v1.NodeList{}
+
+	if err := dcrh.client.List(ctx, &nodes, client.MatchingLabels(nwConfig.Spec.Selector)); err != nil {
+		return nil, fmt.Errorf("failed to list Nodes: %v", err)
+	}
+
+	return &nodes, nil
+}
+
 func (dcrh *networkConfigReconcilerHelper) getRequestedNetworkConfig(ctx context.Context, namespacedName types.NamespacedName) (*amdv1alpha1.NetworkConfig, error) {
 	nwConfig := amdv1alpha1.NetworkConfig{}
 
@@ -487,13 +640,38 @@ func (dcrh *networkConfigReconcilerHelper) hasSecretReference(secretName string,
 	if dcfg.Spec.Driver.ImageSign.CertSecret != nil && dcfg.Spec.Driver.ImageSign.CertSecret.Name == secretName {
 		return true
 	}
+	for _, secret := range slices.Concat(dcfg.Spec.Driver.ImageBuild.BuildSecrets, dcfg.Spec.Driver.ImageBuild.CABundleSecrets) {
+		if secret.Name == secretName {
+			return true
+		}
+	}
+	// the OTLP header keys are rendered into the collector config, and the TLS secrets are mounted into the collector
+	if otlp := dcfg.Spec.MetricsExporter.OTLP; otlp != nil {
+		if otlp.HeadersSecret != nil && otlp.HeadersSecret.Name == secretName {
+			return true
+		}
+		if otlp.TLS != nil && otlp.TLS.CASecret != nil && otlp.TLS.CASecret.Name == secretName {
+			return true
+		}
+		if otlp.TLS != nil && otlp.TLS.CertSecret != nil && otlp.TLS.CertSecret.Name == secretName {
+			return true
+		}
+	}
 	return false
 }
 
-// findNetworkConfigsWithKMM only reconcile networkconfigs with KMM enabled to manage out-of-tree kernel module
-func (drch *networkConfigReconcilerHelper) findNetworkConfigsWithKMM(ctx context.Context, node client.Object) []reconcile.Request {
+// findNetworkConfigsForConfigMap when a user provided ConfigMap changed, only trigger reconcile for related NetworkConfig
+func (drch *networkConfigReconcilerHelper) findNetworkConfigsForConfigMap(ctx context.Context, cm client.Object) []reconcile.Request {
 	reqs := []reconcile.Request{}
 	logger := log.FromContext(ctx)
+	cmObj, ok := cm.(*v1.ConfigMap)
+	if !ok {
+		logger.Error(fmt.Errorf("failed to convert object %+v to ConfigMap", cm), "")
+		return reqs
+	}
+	if cmObj.Namespace != drch.namespace {
+		return reqs
+	}
 	networkConfigList, err := drch.listNetworkConfigs(ctx)
 	if err != nil || networkConfigList == nil {
 		logger.Error(err, "failed to list networkconfigs")
@@ -501,8 +679,7 @@ func (drch *networkConfigReconcilerHelper) findNetworkConfigsWithKMM(ctx context
 	}
 	for _, dcfg := range networkConfigList.Items {
 		if dcfg.Namespace == drch.namespace &&
-			dcfg.Spec.Driver.Enable != nil &&
-			*dcfg.Spec.Driver.Enable {
+			drch.hasConfigMapReference(cmObj.Name, dcfg) {
 			reqs = append(reqs, reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Namespace: dcfg.Namespace,
@@ -515,6 +692,60 @@ func (drch *networkConfigReconcilerHelper) findNetworkConfigsWithKMM(ctx context
 	return reqs
 }
 
+func (dcrh *networkConfigReconcilerHelper) hasConfigMapReference(cmName string, dcfg amdv1alpha1.NetworkConfig) bool {
+	// user defined OS profiles change the driver build of selected nodes
+	if dcfg.Spec.Driver.OSProfiles != nil && dcfg.Spec.Driver.OSProfiles.Name == cmName {
+		return true
+	}
+	for _, dockerfile := range dcfg.Spec.Driver.ImageBuild.Dockerfiles {
+		if dockerfile.ConfigMap.Name == cmName {
+			return true
+		}
+	}
+	// the rendered device plugin config needs to be restored if modified
+	if dpinternal.GetDevicePluginConfigMapName(&dcfg) == cmName {
+		return true
+	}
+	return false
+}
+
+// findNetworkConfigsForNode returns the NetworkConfigs whose selector matches the node
+// the candidates are looked up from the selector index instead of listing all NetworkConfigs
+func (drch *networkConfigReconcilerHelper) findNetworkConfigsForNode(ctx context.Context, node *v1.Node) []reconcile.Request {
+	reqs := []reconcile.Request{}
+	logger := log.FromContext(ctx)
+	indexValues := []string{selectAllNodesIndexValue}
+	for k, v := range node.Labels {
+		indexValues = append(indexValues, getSelectorIndexValue(k, v))
+	}
+	found := map[types.NamespacedName]bool{}
+	for _, indexValue := range indexValues {
+		networkConfigList := amdv1alpha1.NetworkConfigList{}
+		if err := drch.client.List(ctx, &networkConfigList,
+			client.InNamespace(drch.namespace),
+			client.MatchingFields{networkConfigSelectorIndexKey: indexValue}); err != nil {
+			logger.Error(err, "failed to list networkconfigs")
+			continue
+		}
+		for _, dcfg := range networkConfigList.Items {
+			nsn := types.NamespacedName{Namespace: dcfg.Namespace, Name: dcfg.Name}
+			if found[nsn] {
+				continue
+			}
+			// the node labeller may select other nodes than the NetworkConfig
+			for _, selector := range getNetworkConfigSelectors(&dcfg) {
+				if k8slabels.SelectorFromSet(selector).Matches(k8slabels.Set(node.Labels)) {
+					found[nsn] = true
+					reqs = append(reqs, reconcile.Request{NamespacedName: nsn})
+					break
+				}
+			}
+		}
+	}
+
+	return reqs
+}
+
 func (dcrh *networkConfigReconcilerHelper) buildNetworkConfigStatus(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
 	// fetch NetworkConfig-owned custom resource
 	// then retrieve its status and put it to NetworkConfig's status fields
@@ -533,20 +764,25 @@ func (dcrh *networkConfigReconcilerHelper) buildNetworkConfigStatus(ctx context.
 		}
 	}
 
-	devPlDs := appsv1.DaemonSet{}
-	dsName := types.NamespacedName{
-		Namespace: nwConfig.Namespace,
-		Name:      fmt.Sprintf("%s-%s", nwConfig.Name, dpinternal.DevicePluginName),
-	}
+	// the device plugin DaemonSet is deleted when the NICs are only allocated by the DRA driver
+	if isDevicePluginEnabled(nwConfig) {
+		devPlDs := appsv1.DaemonSet{}
+		dsName := types.NamespacedName{
+			Namespace: nwConfig.Namespace,
+			Name:      fmt.Sprintf("%s-%s", nwConfig.Name, dpinternal.DevicePluginName),
+		}
 
-	if err := dcrh.client.Get(ctx, dsName, &devPlDs); err == nil {
-		nwConfig.Status.DevicePlugin = amdv1alpha1.DeploymentStatus{
-			NodesMatchingSelectorNumber: devPlDs.Status.NumberAvailable,
-			DesiredNumber:               devPlDs.Status.DesiredNumberScheduled,
-			AvailableNumber:             devPlDs.Status.NumberAvailable,
+		if err := dcrh.client.Get(ctx, dsName, &devPlDs); err == nil {
+			nwConfig.Status.DevicePlugin = amdv1alpha1.DeploymentStatus{
+				NodesMatchingSelectorNumber: devPlDs.Status.NumberAvailable,
+				DesiredNumber:               devPlDs.Status.DesiredNumberScheduled,
+				AvailableNumber:             devPlDs.Status.NumberAvailable,
+			}
+		} else {
+			return fmt.Errorf("failed to fetch device-plugin %+v: %+v", dsName, err)
 		}
 	} else {
-		return fmt.Errorf("failed to fetch device-plugin %+v: %+v", dsName, err)
+		nwConfig.Status.DevicePlugin = amdv1alpha1.DeploymentStatus{}
 	}
 
 	if nwConfig.Spec.MetricsExporter.Enable != nil && *nwConfig.Spec.MetricsExporter.Enable {
@@ -615,6 +851,10 @@ func (dcrh *networkConfigReconcilerHelper) updateNetworkConfigNodeStatus(ctx con
 		previousBootIds[nodeName] = moduleStatus.BootId
 	}
 	nwConfig.Status.NodeModuleStatus = map[string]amdv1alpha1.ModuleStatus{}
+	unsupportedNodes := map[string]string{}
+	if nwConfig.Spec.Driver.Enable != nil && *nwConfig.Spec.Driver.Enable {
+		unsupportedNodes = dcrh.kmmHandler.GetUnsupportedNodes(ctx, nwConfig, nodes)
+	}
 
 	// for each node, fetch its status of modules configured by given NetworkConfig
 	for _, node := range nodes.Items {
@@ -632,7 +872,8 @@ func (dcrh *networkConfigReconcilerHelper) updateNetworkConfigNodeStatus(ctx con
 		if bootId == "" {
 			bootId = previousBootIds[node.Name]
 		}
-		nwConfig.Status.NodeModuleStatus[node.Name] = amdv1alpha1.ModuleStatus{Status: dcrh.upgradeMgrHandler.GetNodeStatus(node.Name), UpgradeStartTime: upgradeStartTime, BootId: bootId}
+		nwConfig.Status.NodeModuleStatus[node.Name] = amdv1alpha1.ModuleStatus{Status: dcrh.upgradeMgrHandler.GetNodeStatus(node.Name), UpgradeStartTime: upgradeStartTime, BootId: bootId, Reason: unsupportedNodes[node.Name]}
+		nsn := types.NamespacedName{Namespace: nwConfig.Namespace, Name: nwConfig.Name}
 
 		nmc := kmmv1beta1.NodeModulesConfig{}
 		err := dcrh.client.Get(ctx, types.NamespacedName{Name: node.Name}, &nmc)
@@ -655,6 +896,12 @@ func (dcrh *networkConfigReconcilerHelper) updateNetworkConfigNodeStatus(ctx con
 						Status:             dcrh.upgradeMgrHandler.GetNodeStatus(node.Name),
 						UpgradeStartTime:   upgradeStartTime,
 						BootId:             bootId,
+						Reason:             unsupportedNodes[node.Name],
+					}
+					if nwConfig.Spec.Driver.ImageSign.CertSecret != nil {
+						moduleStatus := nwConfig.Status.NodeModuleStatus[node.Name]
+						moduleStatus.KmodSignature = dcrh.getKmodSignatureStatus(&node, nsn, module.Config.ContainerImage)
+						nwConfig.Status.NodeModuleStatus[node.Name] = moduleStatus
 					}
 				}
 			}
@@ -677,6 +924,19 @@ func (dcrh *networkConfigReconcilerHelper) setFinalizer(ctx context.Context, nwC
 func (dcrh *networkConfigReconcilerHelper) finalizeMetricsExporter(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
 	logger := log.FromContext(ctx)
 
+	if err := dcrh.deletePrometheusRule(ctx, nwConfig); err != nil {
+		return err
+	}
+	if err := dcrh.deleteGrafanaDashboard(ctx, nwConfig); err != nil {
+		return err
+	}
+	if err := dcrh.deleteOTLPConfigMap(ctx, nwConfig); err != nil {
+		return err
+	}
+	if err := dcrh.deleteMetricsExporterTLS(ctx, nwConfig); err != nil {
+		return err
+	}
+
 	// Handle ServiceMonitor deletion
 	serviceMonitor := &monitoringv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
@@ -774,6 +1034,27 @@ func (dcrh *networkConfigReconcilerHelper) finalizeCNIPlugins(ctx context.Contex
 	return nil
 }
 
+func (dcrh *networkConfigReconcilerHelper) finalizeMultus(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	logger := log.FromContext(ctx)
+
+	name := secondarynetwork.GetMultusName(nwConfig)
+	objs := []client.Object{
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: name}},
+		&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: name}},
+	}
+	for _, obj := range objs {
+		if err := dcrh.client.Delete(ctx, obj); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete Multus %T %s: %v", obj, name, err)
+			}
+			continue
+		}
+		logger.Info("deleted Multus object", "kind", fmt.Sprintf("%T", obj), "name", name)
+	}
+
+	return nil
+}
+
 func (dcrh *networkConfigReconcilerHelper) finalizeUpgradeWorkers(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
 	logger := log.FromContext(ctx)
 	label := dcrh.workerMgr.GetWorkReadyLabel(types.NamespacedName{Namespace: nwConfig.Namespace, Name: nwConfig.Name})
@@ -836,6 +1117,22 @@ func (dcrh *networkConfigReconcilerHelper) finalizeDevicePlugin(ctx context.Cont
 		}
 	}
 
+	cm := v1.ConfigMap{}
+	cmName := types.NamespacedName{
+		Namespace: nwConfig.Namespace,
+		Name:      dpinternal.GetDevicePluginConfigMapName(nwConfig),
+	}
+	if err := dcrh.client.Get(ctx, cmName, &cm); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get device-plugin config %s: %v", cmName, err)
+		}
+	} else {
+		logger.Info("deleting device-plugin config", "configmap", cmName)
+		if err := dcrh.client.Delete(ctx, &cm); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete device-plugin config %s: %v", cmName, err)
+		}
+	}
+
 	return nil
 }
 
@@ -969,6 +1266,16 @@ func (dcrh *networkConfigReconcilerHelper) finalizeNetworkConfig(ctx context.Con
 		return err
 	}
 
+	// finalize DRA driver, its DeviceClasses and ResourceSlices
+	if err := dcrh.finalizeDRADriver(ctx, nwConfig, nodes); err != nil {
+		return err
+	}
+
+	// finalize host config agent and MachineConfig
+	if err := dcrh.finalizeHostConfig(ctx, nwConfig); err != nil {
+		return err
+	}
+
 	// finalize node labeller
 	if err := dcrh.finalizeNodeLabeller(ctx, nwConfig); err != nil {
 		return err
@@ -979,6 +1286,38 @@ func (dcrh *networkConfigReconcilerHelper) finalizeNetworkConfig(ctx context.Con
 		return err
 	}
 
+	// finalize Multus
+	if err := dcrh.finalizeMultus(ctx, nwConfig); err != nil {
+		return err
+	}
+
+	// finalize the NetworkAttachmentDefinitions, they are not garbage collected by owner references
+	if err := dcrh.deleteNetworkAttachments(ctx, nwConfig, nil); err != nil {
+		return err
+	}
+
+	// remove the NIC to GPU topology ConfigMap and node labels
+	if err := dcrh.finalizeGPUAffinity(ctx, nwConfig, nodes); err != nil {
+		return err
+	}
+
+	// reverse the remediation of the nodes with unhealthy NICs
+	if err := dcrh.finalizeNodeRemediation(ctx, nwConfig); err != nil {
+		return err
+	}
+
+	// remove the AMDNetworkReady condition and label from the nodes, including the ones not selected anymore
+	if err := dcrh.finalizeNodeReadiness(ctx, nodes); err != nil {
+		return err
+	}
+	unselectedNodes, err := dcrh.getUnselectedLabelledNodes(ctx, nwConfig, nodes, utils.NetworkReadyLabelKey)
+	if err != nil {
+		return err
+	}
+	if err := dcrh.finalizeNodeReadiness(ctx, unselectedNodes); err != nil {
+		return err
+	}
+
 	// finalize existing workers created for driver upgrade and related node labels
 	// in case the NetworkConfig is deleted during driver upgrade
 	if err := dcrh.finalizeUpgradeWorkers(ctx, nwConfig, nodes); err != nil {
@@ -1033,16 +1372,30 @@ func (dcrh *networkConfigReconcilerHelper) handleBuildConfigMap(ctx context.Cont
 		return nil
 	}
 	if nodes == nil || len(nodes.Items) == 0 {
-		return fmt.Errorf("no nodes found for the label selector %s", kmmmodule.MapToLabelSelector(nwConfig.Spec.Selector))
+		return fmt.Errorf("no nodes found for the label selector %s", k8slabels.SelectorFromSet(nwConfig.Spec.Selector))
+	}
+
+	profiles, err := dcrh.kmmHandler.GetOSProfiles(ctx, nwConfig)
+	if err != nil {
+		return err
 	}
 
 	savedCMName := map[string]bool{}
 	buildOK := true
 	for _, node := range nodes.Items {
-		osName, err := kmmmodule.GetOSName(node, nwConfig)
+		profile, osVersion, err := profiles.Match(node)
 		if err != nil {
+			if kmmmodule.SkipUnsupportedNodes(nwConfig) {
+				logger.Info("skip building dockerfile ConfigMap for unsupported node", "node", node.Name, "reason", err.Error())
+				continue
+			}
 			return fmt.Errorf("invalid node %s, err: %v", node.Name, err)
 		}
+		osName := profile.OSName(osVersion)
+		if kmmmodule.GetUserDockerfileConfigMap(nwConfig, profile.Name, osName) != nil {
+			// user supplied Dockerfile is referenced by KMM module directly
+			continue
+		}
 		cmName := kmmmodule.GetCMName(osName, nwConfig)
 		if savedCMName[cmName] {
 			// already saved a docker file for the OS-Version combo
@@ -1057,7 +1410,7 @@ func (dcrh *networkConfigReconcilerHelper) handleBuildConfigMap(ctx context.Cont
 		}
 
 		opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, buildDockerfileCM, func() error {
-			return dcrh.kmmHandler.SetBuildConfigMapAsDesired(buildDockerfileCM, nwConfig)
+			return dcrh.kmmHandler.SetBuildConfigMapAsDesired(buildDockerfileCM, nwConfig, profile, osVersion)
 		})
 
 		if err == nil {
@@ -1103,259 +1456,1783 @@ func (dcrh *networkConfigReconcilerHelper) handleKMMModule(ctx context.Context,
 	return nil
 }
 
-func (dcrh *networkConfigReconcilerHelper) handleDevicePlugin(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, isOpenShift bool) error {
+// handleKmodSignatureVerification triggers the verification of the loaded kernel module signatures
+// on the nodes whose verification result is missing or out of date
+func (dcrh *networkConfigReconcilerHelper) handleKmodSignatureVerification(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
 	logger := log.FromContext(ctx)
-	ds := &appsv1.DaemonSet{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: nwConfig.Namespace,
-			Name:      fmt.Sprintf("%s-%s", nwConfig.Name, deviceplugin.DevicePluginName)},
+	nsn := types.NamespacedName{Namespace: nwConfig.Namespace, Name: nwConfig.Name}
+	delete(dcrh.kmodSignatureErrors, nsn.String())
+	if nwConfig.Spec.Driver.Enable == nil || !*nwConfig.Spec.Driver.Enable ||
+		nwConfig.Spec.Driver.ImageSign.CertSecret == nil {
+		return nil
 	}
 
-	dpOut := dpinternal.GenerateCommonDevicePluginSpec(nwConfig, isOpenShift)
-	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
-		scheme, dcrhErr := dcrh.devicepluginHandler.SetDevicePluginAsDesired(ds, dpOut)
-		if dcrhErr != nil {
-			return dcrhErr
-		}
-		// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
-		return controllerutil.SetControllerReference(nwConfig, ds, scheme)
-	})
+	certSKID, err := dcrh.getCertSKID(ctx, nwConfig)
 	if err != nil {
 		return err
 	}
-	logger.Info("Reconciled device-plugin", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
-
-	return nil
-}
 
-func (dcrh *networkConfigReconcilerHelper) handleKMMVersionLabel(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
-	// label corresponding node with given kmod version
-	// so that KMM could manage the upgrade by watching the node's version label change
-	if nwConfig.Spec.Driver.Enable != nil && *nwConfig.Spec.Driver.Enable {
-		err := dcrh.kmmHandler.SetNodeVersionLabelAsDesired(ctx, nwConfig, nodes)
-		if err != nil {
-			return fmt.Errorf("failed to update node version label for NetworkConfig %s/%s: %v", nwConfig.Namespace, nwConfig.Name, err)
+	// the nodes whose verifier pod can't be created are reported as failed in the status
+	verifyErrors := map[string]string{}
+	for _, node := range nodes.Items {
+		moduleStatus, ok := nwConfig.Status.NodeModuleStatus[node.Name]
+		if !ok || moduleStatus.ContainerImage == "" {
+			// the kernel modules are not loaded on this node yet
+			continue
+		}
+		if result := workermgr.GetKmodSignatureResult(&node, nsn); result != nil &&
+			result.BootID == node.Status.NodeInfo.BootID &&
+			result.ContainerImage == moduleStatus.ContainerImage {
+			continue
+		}
+		if err := dcrh.workerMgr.VerifyKmodSignature(ctx, nwConfig, &node, moduleStatus.ContainerImage, certSKID, kmmmodule.GetOutOfTreeKmods()); err != nil {
+			logger.Error(err, fmt.Sprintf("failed to verify kmod signature on node %v", node.Name))
+			verifyErrors[node.Name] = err.Error()
 		}
 	}
+	dcrh.kmodSignatureErrors[nsn.String()] = verifyErrors
 	return nil
 }
 
-func (dcrh *networkConfigReconcilerHelper) handleNodeLabeller(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList, isOpenShift bool) error {
+// handleGPUAffinity discovers the NIC to GPU topology of the nodes which haven't been discovered since their last boot
+func (dcrh *networkConfigReconcilerHelper) handleGPUAffinity(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
 	logger := log.FromContext(ctx)
+	if !dpinternal.IsGPUAffinityEnabled(nwConfig) {
+		return dcrh.finalizeGPUAffinity(ctx, nwConfig, nodes)
+	}
 
-	nlFullName := fmt.Sprintf("%s-%s", nwConfig.Name, nlinternal.NodeLabellerNameSuffix)
-	if nwConfig.Spec.DevicePlugin.EnableNodeLabeller == nil || !*nwConfig.Spec.DevicePlugin.EnableNodeLabeller {
-		if err := dcrh.finalizeNodeLabeller(ctx, nwConfig); err != nil {
-			return err
-		}
-
-		// clean up node labeller's label when node labeller is disabled
-		// if no label need to be removed, updateNodeLabels won't send request
-		if err := dcrh.updateNodeLabels(ctx, nwConfig, nodes, false); err != nil {
-			logger.Error(err, "failed to remove node labeller's labels when node labeller is disabled")
+	selectedNodes := map[string]bool{}
+	if nodes != nil {
+		for _, node := range nodes.Items {
+			selectedNodes[node.Name] = true
 		}
-		logger.Info("skip handling node labeller as it is disbaled", "namespace", nwConfig.Namespace, "name", nlFullName)
-		return nil
 	}
-
-	ds := &appsv1.DaemonSet{
-		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: nlFullName},
+	// the discovery pods publish the topology into the ConfigMap, only the nodes no longer selected are pruned here
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nwConfig.Namespace,
+			Name:      topology.GetTopologyConfigMapName(nwConfig.Name),
+		},
 	}
-	nlOut := nlinternal.GenerateCommonNodeLabellerSpec(nwConfig, isOpenShift)
-	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
-		scheme, dcrhErr := dcrh.nlHandler.SetNodeLabellerAsDesired(ds, nlOut)
-		if dcrhErr != nil {
-			return dcrhErr
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, cm, func() error {
+		for nodeName := range cm.Data {
+			if !selectedNodes[nodeName] {
+				delete(cm.Data, nodeName)
+			}
 		}
-		// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
-		return controllerutil.SetControllerReference(nwConfig, ds, scheme)
+		return controllerutil.SetControllerReference(nwConfig, cm, dcrh.client.Scheme())
 	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile NIC to GPU topology configmap %s: %v", cm.Name, err)
+	}
+	logger.Info("Reconciled NIC to GPU topology configmap", "namespace", cm.Namespace, "name", cm.Name, "result", opRes)
+
+	if nodes != nil {
+		for _, node := range nodes.Items {
+			if topo := topology.GetNodeTopology(cm, node.Name); topo != nil && topo.BootID == node.Status.NodeInfo.BootID {
+				continue
+			}
+			if err := dcrh.workerMgr.DiscoverTopology(ctx, nwConfig, &node); err != nil {
+				logger.Error(err, fmt.Sprintf("failed to discover NIC to GPU topology on node %v", node.Name))
+			}
+		}
+	}
 
+	// remove the affinity label from the nodes which are not selected anymore
+	unselectedNodes, err := dcrh.getUnselectedLabelledNodes(ctx, nwConfig, nodes, utils.NICGPUAffinityLabelKey)
 	if err != nil {
 		return err
 	}
+	return dcrh.removeGPUAffinityLabel(ctx, unselectedNodes)
+}
 
-	logger.Info("Reconciled node labeller", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
-
-	// todo: temp. cleanup labels set by node-labeller
-	// not required once label cleanup is added in node-labeller
-	nodeLabels := func() string {
-		// nodes without network, kmm, dev-plugin //GSM
-		sel := []string{
-			"! " + utils.NodeFeatureLabelAmdNic,
-			"! " + utils.NodeFeatureLabelAmdVNic,
-			"! " + labels.GetKernelModuleReadyNodeLabel(nwConfig.Namespace, nwConfig.Name),
-			"! " + labels.GetDevicePluginNodeLabel(nwConfig.Namespace, nwConfig.Name),
+// finalizeGPUAffinity removes the NIC to GPU topology ConfigMap and the affinity label from the nodes,
+// including the ones not selected anymore
+func (dcrh *networkConfigReconcilerHelper) finalizeGPUAffinity(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	logger := log.FromContext(ctx)
+	cm := v1.ConfigMap{}
+	cmName := types.NamespacedName{
+		Namespace: nwConfig.Namespace,
+		Name:      topology.GetTopologyConfigMapName(nwConfig.Name),
+	}
+	if err := dcrh.client.Get(ctx, cmName, &cm); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get NIC to GPU topology configmap %s: %v", cmName, err)
 		}
-
-		for k, v := range nwConfig.Spec.Selector {
-			if k == utils.NodeFeatureLabelAmdNic ||
-				k == utils.NodeFeatureLabelAmdVNic { // skip
-				continue
-			}
-			sel = append(sel, fmt.Sprintf("%s=%s", k, v))
+	} else {
+		logger.Info("deleting NIC to GPU topology configmap", "configmap", cmName)
+		if err := dcrh.client.Delete(ctx, &cm); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete NIC to GPU topology configmap %s: %v", cmName, err)
 		}
-		return strings.Join(sel, ",")
-	}()
+	}
 
-	its, err := kmmmodule.GetK8SNodes(nodeLabels)
+	if err := dcrh.removeGPUAffinityLabel(ctx, nodes); err != nil {
+		return err
+	}
+	unselectedNodes, err := dcrh.getUnselectedLabelledNodes(ctx, nwConfig, nodes, utils.NICGPUAffinityLabelKey)
 	if err != nil {
-		logger.Info("failed to get node list ", err)
-		return nil
+		return err
 	}
-	logger.Info(fmt.Sprintf("select (%v) found %v nodes", nodeLabels, len(its.Items)))
+	return dcrh.removeGPUAffinityLabel(ctx, unselectedNodes)
+}
 
-	if err := dcrh.updateNodeLabels(ctx, nwConfig, its, false); err != nil {
-		logger.Error(err, "failed to update node labels")
+// removeGPUAffinityLabel removes the NIC to GPU affinity label from the nodes
+func (dcrh *networkConfigReconcilerHelper) removeGPUAffinityLabel(ctx context.Context, nodes *v1.NodeList) error {
+	if nodes == nil {
+		return nil
+	}
+	for _, item := range nodes.Items {
+		if _, ok := item.Labels[utils.NICGPUAffinityLabelKey]; !ok {
+			continue
+		}
+		node := item.DeepCopy()
+		delete(node.Labels, utils.NICGPUAffinityLabelKey)
+		if err := dcrh.client.Patch(ctx, node, client.MergeFrom(&item)); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to remove %v label from node %v: %v", utils.NICGPUAffinityLabelKey, node.Name, err)
+		}
 	}
 	return nil
 }
 
-func (dcrh *networkConfigReconcilerHelper) handleModuleUpgrade(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList, delete bool) (ctrl.Result, error) {
-	if delete {
-		return dcrh.upgradeMgrHandler.HandleDelete(ctx, nwConfig, nodes)
+// getCertSKID returns the subject key identifier of the imageSign certificate in lower case hex
+func (dcrh *networkConfigReconcilerHelper) getCertSKID(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) (string, error) {
+	secret := v1.Secret{}
+	secretName := nwConfig.Spec.Driver.ImageSign.CertSecret.Name
+	if err := dcrh.client.Get(ctx, types.NamespacedName{Namespace: nwConfig.Namespace, Name: secretName}, &secret); err != nil {
+		return "", fmt.Errorf("failed to get imageSign cert secret %v: %v", secretName, err)
 	}
-	return dcrh.upgradeMgrHandler.HandleUpgrade(ctx, nwConfig, nodes)
+	certBytes, ok := secret.Data[kmodSigningCertKey]
+	if !ok {
+		return "", fmt.Errorf("imageSign cert secret %v doesn't have the %v key", secretName, kmodSigningCertKey)
+	}
+	// the certificate could be either PEM or DER encoded
+	if block, _ := pem.Decode(certBytes); block != nil {
+		certBytes = block.Bytes
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse imageSign cert secret %v: %v", secretName, err)
+	}
+	if len(cert.SubjectKeyId) == 0 {
+		return "", fmt.Errorf("imageSign cert secret %v doesn't have the subject key identifier", secretName)
+	}
+	return hex.EncodeToString(cert.SubjectKeyId), nil
+}
+
+// getKmodSignatureStatus returns the kmod signature verification status for the given node and driver image
+func (dcrh *networkConfigReconcilerHelper) getKmodSignatureStatus(node *v1.Node, nsn types.NamespacedName, containerImage string) string {
+	if msg, ok := dcrh.kmodSignatureErrors[nsn.String()][node.Name]; ok {
+		return fmt.Sprintf("%v: failed to start the verification: %v", kmodSignatureFailed, msg)
+	}
+	result := workermgr.GetKmodSignatureResult(node, nsn)
+	if result == nil || result.BootID != node.Status.NodeInfo.BootID || result.ContainerImage != containerImage {
+		return kmodSignaturePending
+	}
+	if result.Verified {
+		return kmodSignatureVerified
+	}
+	return fmt.Sprintf("%v: %v", kmodSignatureFailed, result.Message)
+}
+
+func (dcrh *networkConfigReconcilerHelper) handleDevicePlugin(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, isOpenShift bool) error {
+	logger := log.FromContext(ctx)
+	if !isDevicePluginEnabled(nwConfig) {
+		logger.Info("skip handling device-plugin as it is disabled", "namespace", nwConfig.Namespace, "name", nwConfig.Name)
+		return dcrh.finalizeDevicePlugin(ctx, nwConfig)
+	}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nwConfig.Namespace,
+			Name:      fmt.Sprintf("%s-%s", nwConfig.Name, deviceplugin.DevicePluginName)},
+	}
+
+	// render the device plugin config before the DaemonSet, so the pods can mount it on start
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nwConfig.Namespace,
+			Name:      dpinternal.GetDevicePluginConfigMapName(nwConfig),
+		},
+	}
+	var configHash string
+	cmRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, cm, func() error {
+		var dcrhErr error
+		if configHash, dcrhErr = dpinternal.SetDevicePluginConfigMapAsDesired(cm, nwConfig); dcrhErr != nil {
+			return dcrhErr
+		}
+		return controllerutil.SetControllerReference(nwConfig, cm, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile device-plugin config %s: %v", cm.Name, err)
+	}
+	logger.Info("Reconciled device-plugin config", "namespace", cm.Namespace, "name", cm.Name, "result", cmRes)
+
+	dpOut := dpinternal.GenerateCommonDevicePluginSpec(nwConfig, isOpenShift)
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
+		scheme, dcrhErr := dcrh.devicepluginHandler.SetDevicePluginAsDesired(ds, dpOut)
+		if dcrhErr != nil {
+			return dcrhErr
+		}
+		// roll the device plugin pods when the config changes, the device plugin only reads it on start
+		if ds.Spec.Template.Annotations == nil {
+			ds.Spec.Template.Annotations = map[string]string{}
+		}
+		ds.Spec.Template.Annotations[dpinternal.DevicePluginConfigHashAnnotation] = configHash
+		// the common device plugin handler doesn't render the container arguments
+		for i := range ds.Spec.Template.Spec.Containers {
+			if ds.Spec.Template.Spec.Containers[i].Name == deviceplugin.DevicePluginName {
+				ds.Spec.Template.Spec.Containers[i].Args = dpOut.MainContainer.Arguments
+			}
+		}
+		utils.ApplyPodCustomization(&ds.Spec.Template.ObjectMeta, &ds.Spec.Template.Spec, utils.GetPodCustomization(nwConfig, nwConfig.Spec.DevicePlugin.PodCustomization))
+		// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
+		return controllerutil.SetControllerReference(nwConfig, ds, scheme)
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("Reconciled device-plugin", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
+
+	return nil
+}
+
+// handleDRADriver deploys the DRA kubelet plugin with its config and manages the DeviceClasses of the NetworkConfig
+func (dcrh *networkConfigReconcilerHelper) handleDRADriver(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	logger := log.FromContext(ctx)
+	if !drainternal.IsDRADriverEnabled(nwConfig) {
+		return dcrh.finalizeDRADriver(ctx, nwConfig, nodes)
+	}
+
+	// render the DRA driver config before the DaemonSet, so the pods can mount it on start
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nwConfig.Namespace,
+			Name:      drainternal.GetDRADriverConfigMapName(nwConfig),
+		},
+	}
+	var configHash string
+	cmRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, cm, func() error {
+		var dcrhErr error
+		if configHash, dcrhErr = drainternal.SetDRADriverConfigMapAsDesired(cm, nwConfig); dcrhErr != nil {
+			return dcrhErr
+		}
+		return controllerutil.SetControllerReference(nwConfig, cm, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile DRA driver config %s: %v", cm.Name, err)
+	}
+	logger.Info("Reconciled DRA driver config", "namespace", cm.Namespace, "name", cm.Name, "result", cmRes)
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nwConfig.Namespace,
+			Name:      drainternal.GetDRADriverName(nwConfig),
+		},
+	}
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
+		if dcrhErr := drainternal.SetDRADriverAsDesired(ds, nwConfig, configHash); dcrhErr != nil {
+			return dcrhErr
+		}
+		utils.ApplyPodCustomization(&ds.Spec.Template.ObjectMeta, &ds.Spec.Template.Spec, utils.GetPodCustomization(nwConfig, nwConfig.Spec.DRADriver.PodCustomization))
+		return controllerutil.SetControllerReference(nwConfig, ds, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile DRA driver %s: %v", ds.Name, err)
+	}
+	logger.Info("Reconciled DRA driver", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
+
+	// the DeviceClasses are cluster scoped, they are tracked by the owner labels instead of owner references
+	desiredClasses := map[string]bool{}
+	for _, classSpec := range drainternal.GetDeviceClasses(nwConfig) {
+		desiredClasses[classSpec.Name] = true
+		dc := &resourcev1beta1.DeviceClass{
+			ObjectMeta: metav1.ObjectMeta{Name: classSpec.Name},
+		}
+		dcRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, dc, func() error {
+			if !dc.CreationTimestamp.IsZero() && !isOwnedByNetworkConfig(dc.Labels, nwConfig) {
+				return fmt.Errorf("DeviceClass %s is not managed by NetworkConfig %s/%s", dc.Name, nwConfig.Namespace, nwConfig.Name)
+			}
+			drainternal.SetDeviceClassAsDesired(dc, classSpec, nwConfig)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reconcile DeviceClass %s: %v", dc.Name, err)
+		}
+		logger.Info("Reconciled DeviceClass", "name", dc.Name, "result", dcRes)
+	}
+	if err := dcrh.deleteDeviceClasses(ctx, nwConfig, desiredClasses); err != nil {
+		return err
+	}
+
+	// the DRA driver isn't scheduled on the nodes which are not selected anymore, their ResourceSlices would keep advertising the NICs
+	selected := map[string]bool{}
+	for _, node := range nodes.Items {
+		selected[node.Name] = true
+	}
+	return dcrh.deleteResourceSlices(ctx, nwConfig, selected)
+}
+
+// deleteResourceSlices deletes the ResourceSlices published by the DRA driver on the nodes which are not kept,
+// the nodes assigned to another NetworkConfig are left to it
+func (dcrh *networkConfigReconcilerHelper) deleteResourceSlices(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, keepNodes map[string]bool) error {
+	logger := log.FromContext(ctx)
+	sliceList := resourcev1beta1.ResourceSliceList{}
+	if err := dcrh.client.List(ctx, &sliceList); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list ResourceSlices: %v", err)
+	}
+	namespacedName := types.NamespacedName{Namespace: nwConfig.Namespace, Name: nwConfig.Name}.String()
+	for _, slice := range sliceList.Items {
+		if slice.Spec.Driver != drainternal.DriverName || keepNodes[slice.Spec.NodeName] {
+			continue
+		}
+		if owner, ok := dcrh.nodeAssignments[slice.Spec.NodeName]; ok && owner != namespacedName {
+			continue
+		}
+		logger.Info("deleting ResourceSlice", "name", slice.Name, "node", slice.Spec.NodeName)
+		if err := dcrh.client.Delete(ctx, &slice); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ResourceSlice %s: %v", slice.Name, err)
+		}
+	}
+	return nil
+}
+
+// finalizeDRADriver deletes the DRA driver with its config, the DeviceClasses and the ResourceSlices it published on the nodes
+func (dcrh *networkConfigReconcilerHelper) finalizeDRADriver(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	logger := log.FromContext(ctx)
+
+	ds := appsv1.DaemonSet{}
+	dsName := types.NamespacedName{Namespace: nwConfig.Namespace, Name: drainternal.GetDRADriverName(nwConfig)}
+	if err := dcrh.client.Get(ctx, dsName, &ds); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get DRA driver daemonset %s: %v", dsName, err)
+		}
+	} else {
+		logger.Info("deleting DRA driver daemonset", "daemonset", dsName)
+		if err := dcrh.client.Delete(ctx, &ds); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete DRA driver daemonset %s: %v", dsName, err)
+		}
+		// the ResourceSlices of the stopped DRA driver would keep advertising the NICs,
+		// including the ones of the nodes which were deselected before
+		if err := dcrh.deleteResourceSlices(ctx, nwConfig, nil); err != nil {
+			return err
+		}
+	}
+
+	cm := v1.ConfigMap{}
+	cmName := types.NamespacedName{Namespace: nwConfig.Namespace, Name: drainternal.GetDRADriverConfigMapName(nwConfig)}
+	if err := dcrh.client.Get(ctx, cmName, &cm); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get DRA driver config %s: %v", cmName, err)
+		}
+	} else {
+		logger.Info("deleting DRA driver config", "configmap", cmName)
+		if err := dcrh.client.Delete(ctx, &cm); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete DRA driver config %s: %v", cmName, err)
+		}
+	}
+
+	return dcrh.deleteDeviceClasses(ctx, nwConfig, nil)
+}
+
+// deleteDeviceClasses deletes the DeviceClasses managed for the NetworkConfig which are not desired anymore
+func (dcrh *networkConfigReconcilerHelper) deleteDeviceClasses(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, desiredClasses map[string]bool) error {
+	logger := log.FromContext(ctx)
+	if !dcrh.isDRAAPIAvailable() {
+		// nothing could have been created
+		return nil
+	}
+	classes := resourcev1beta1.DeviceClassList{}
+	if err := dcrh.client.List(ctx, &classes, client.MatchingLabels(utils.GetOwnerLabels(nwConfig))); err != nil {
+		return fmt.Errorf("failed to list DeviceClasses: %v", err)
+	}
+	for _, dc := range classes.Items {
+		if desiredClasses[dc.Name] {
+			continue
+		}
+		logger.Info("deleting DeviceClass", "name", dc.Name)
+		if err := dcrh.client.Delete(ctx, &dc); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete DeviceClass %s: %v", dc.Name, err)
+		}
+	}
+	return nil
+}
+
+// handleHostConfig applies the modprobe options, blacklist and sysctls of the host config on the nodes
+// by the host config agent, or by a MachineConfig on OpenShift, and reports whether each node is in sync
+func (dcrh *networkConfigReconcilerHelper) handleHostConfig(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList, isOpenShift bool) error {
+	if !hostconfiginternal.IsHostConfigEnabled(nwConfig) {
+		nwConfig.Status.NodeHostConfigStatus = nil
+		return dcrh.finalizeHostConfig(ctx, nwConfig)
+	}
+
+	var nodeStatus map[string]amdv1alpha1.HostConfigStatus
+	var err error
+	if isOpenShift {
+		nodeStatus, err = dcrh.handleHostMachineConfig(ctx, nwConfig, nodes)
+	} else {
+		nodeStatus, err = dcrh.handleHostConfigAgent(ctx, nwConfig, nodes)
+	}
+	if err != nil {
+		return err
+	}
+	nwConfig.Status.NodeHostConfigStatus = nodeStatus
+	return nil
+}
+
+// handleHostConfigAgent deploys the host config agent with the rendered host config
+// a node is in sync once its agent pod runs the current config hash and is ready
+func (dcrh *networkConfigReconcilerHelper) handleHostConfigAgent(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) (map[string]amdv1alpha1.HostConfigStatus, error) {
+	logger := log.FromContext(ctx)
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nwConfig.Namespace,
+			Name:      hostconfiginternal.GetHostConfigName(nwConfig),
+		},
+	}
+	var configHash string
+	cmRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, cm, func() error {
+		configHash = hostconfiginternal.SetHostConfigMapAsDesired(cm, nwConfig)
+		return controllerutil.SetControllerReference(nwConfig, cm, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile host config %s: %v", cm.Name, err)
+	}
+	logger.Info("Reconciled host config", "namespace", cm.Namespace, "name", cm.Name, "result", cmRes)
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nwConfig.Namespace,
+			Name:      hostconfiginternal.GetHostConfigName(nwConfig),
+		},
+	}
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
+		if dcrhErr := hostconfiginternal.SetHostConfigAgentAsDesired(ds, nwConfig, configHash); dcrhErr != nil {
+			return dcrhErr
+		}
+		utils.ApplyPodCustomization(&ds.Spec.Template.ObjectMeta, &ds.Spec.Template.Spec, utils.GetPodCustomization(nwConfig, nwConfig.Spec.HostConfig.PodCustomization))
+		return controllerutil.SetControllerReference(nwConfig, ds, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile host config agent %s: %v", ds.Name, err)
+	}
+	logger.Info("Reconciled host config agent", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
+
+	pods := v1.PodList{}
+	if err := dcrh.client.List(ctx, &pods,
+		client.InNamespace(nwConfig.Namespace),
+		client.MatchingLabels{"daemonset-name": ds.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list host config agent pods: %v", err)
+	}
+	podsByNode := map[string]v1.Pod{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" && pod.DeletionTimestamp == nil {
+			podsByNode[pod.Spec.NodeName] = pod
+		}
+	}
+
+	nodeStatus := map[string]amdv1alpha1.HostConfigStatus{}
+	if nodes == nil {
+		return nodeStatus, nil
+	}
+	for _, node := range nodes.Items {
+		status := amdv1alpha1.HostConfigStatus{ConfigHash: configHash}
+		pod, ok := podsByNode[node.Name]
+		switch {
+		case !ok:
+			status.Message = "host config agent is not running"
+		case pod.Annotations[hostconfiginternal.HostConfigHashAnnotation] != configHash:
+			status.Message = "host config agent is rolling out the updated host config"
+		default:
+			for _, condition := range pod.Status.Conditions {
+				if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+					status.InSync = true
+				}
+			}
+			if !status.InSync {
+				status.Message = "host config agent is applying the host config"
+			}
+		}
+		nodeStatus[node.Name] = status
+	}
+	return nodeStatus, nil
+}
+
+// handleHostMachineConfig renders the host config into a MachineConfig of the configured pool on OpenShift
+// a node is in sync once it runs the config rendered by the pool including the MachineConfig
+func (dcrh *networkConfigReconcilerHelper) handleHostMachineConfig(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) (map[string]amdv1alpha1.HostConfigStatus, error) {
+	logger := log.FromContext(ctx)
+	configHash := hostconfiginternal.GetHostConfigHash(nwConfig)
+
+	// the MachineConfig is cluster scoped, it is tracked by the owner labels instead of owner references
+	mc := &unstructured.Unstructured{}
+	mc.SetGroupVersionKind(hostconfiginternal.MachineConfigGVK)
+	mc.SetName(hostconfiginternal.GetMachineConfigName(nwConfig))
+	mcRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, mc, func() error {
+		if mc.GetResourceVersion() != "" && !isOwnedByNetworkConfig(mc.GetLabels(), nwConfig) {
+			return fmt.Errorf("MachineConfig %s is not managed by NetworkConfig %s/%s", mc.GetName(), nwConfig.Namespace, nwConfig.Name)
+		}
+		return hostconfiginternal.SetMachineConfigAsDesired(mc, nwConfig)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile MachineConfig %s: %v", mc.GetName(), err)
+	}
+	logger.Info("Reconciled host MachineConfig", "name", mc.GetName(), "result", mcRes)
+
+	// a MachineConfig left over from a previous pool is not desired anymore
+	if err := dcrh.deleteHostMachineConfigs(ctx, nwConfig, mc.GetName()); err != nil {
+		return nil, err
+	}
+
+	nodeStatus := map[string]amdv1alpha1.HostConfigStatus{}
+	if nodes == nil {
+		return nodeStatus, nil
+	}
+	mcp := &unstructured.Unstructured{}
+	mcp.SetGroupVersionKind(hostconfiginternal.MachineConfigPoolGVK)
+	poolName := hostconfiginternal.GetMachineConfigPool(nwConfig)
+	poolErr := dcrh.client.Get(ctx, client.ObjectKey{Name: poolName}, mcp)
+	if poolErr != nil && !k8serrors.IsNotFound(poolErr) {
+		return nil, fmt.Errorf("failed to get MachineConfigPool %s: %v", poolName, poolErr)
+	}
+	for _, node := range nodes.Items {
+		status := amdv1alpha1.HostConfigStatus{ConfigHash: configHash}
+		if poolErr != nil {
+			status.Message = fmt.Sprintf("MachineConfigPool %s not found", poolName)
+		} else {
+			status.InSync, status.Message = hostconfiginternal.GetNodeMachineConfigStatus(&node, mcp, mc.GetName())
+		}
+		nodeStatus[node.Name] = status
+	}
+	return nodeStatus, nil
+}
+
+// finalizeHostConfig deletes the host config agent with its config and the MachineConfig on OpenShift
+// the host config files written by the agent are left on the nodes
+func (dcrh *networkConfigReconcilerHelper) finalizeHostConfig(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	logger := log.FromContext(ctx)
+
+	ds := appsv1.DaemonSet{}
+	dsName := types.NamespacedName{Namespace: nwConfig.Namespace, Name: hostconfiginternal.GetHostConfigName(nwConfig)}
+	if err := dcrh.client.Get(ctx, dsName, &ds); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get host config agent daemonset %s: %v", dsName, err)
+		}
+	} else {
+		logger.Info("deleting host config agent daemonset", "daemonset", dsName)
+		if err := dcrh.client.Delete(ctx, &ds); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete host config agent daemonset %s: %v", dsName, err)
+		}
+	}
+
+	cm := v1.ConfigMap{}
+	cmName := types.NamespacedName{Namespace: nwConfig.Namespace, Name: hostconfiginternal.GetHostConfigName(nwConfig)}
+	if err := dcrh.client.Get(ctx, cmName, &cm); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get host config %s: %v", cmName, err)
+		}
+	} else {
+		logger.Info("deleting host config", "configmap", cmName)
+		if err := dcrh.client.Delete(ctx, &cm); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete host config %s: %v", cmName, err)
+		}
+	}
+
+	return dcrh.deleteHostMachineConfigs(ctx, nwConfig, "")
+}
+
+// deleteHostMachineConfigs deletes the MachineConfigs managed for the NetworkConfig except the desired one
+func (dcrh *networkConfigReconcilerHelper) deleteHostMachineConfigs(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, desiredName string) error {
+	logger := log.FromContext(ctx)
+	mcList := &unstructured.UnstructuredList{}
+	mcList.SetGroupVersionKind(hostconfiginternal.MachineConfigGVK.GroupVersion().WithKind(hostconfiginternal.MachineConfigGVK.Kind + "List"))
+	if err := dcrh.client.List(ctx, mcList, client.MatchingLabels(utils.GetOwnerLabels(nwConfig))); err != nil {
+		if meta.IsNoMatchError(err) {
+			// not an OpenShift cluster, nothing could have been created
+			return nil
+		}
+		return fmt.Errorf("failed to list MachineConfigs: %v", err)
+	}
+	for _, mc := range mcList.Items {
+		if mc.GetName() == desiredName {
+			continue
+		}
+		logger.Info("deleting host MachineConfig", "name", mc.GetName())
+		if err := dcrh.client.Delete(ctx, &mc); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete MachineConfig %s: %v", mc.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// isDRAAPIAvailable checks if the cluster serves the DRA API used by the DRA driver
+func (dcrh *networkConfigReconcilerHelper) isDRAAPIAvailable() bool {
+	_, err := dcrh.client.RESTMapper().RESTMapping(resourcev1beta1.SchemeGroupVersion.WithKind("DeviceClass").GroupKind(), resourcev1beta1.SchemeGroupVersion.Version)
+	return err == nil
+}
+
+// isOwnedByNetworkConfig checks the owner labels of a cluster scoped object
+func isOwnedByNetworkConfig(labels map[string]string, nwConfig *amdv1alpha1.NetworkConfig) bool {
+	for key, val := range utils.GetOwnerLabels(nwConfig) {
+		if labels[key] != val {
+			return false
+		}
+	}
+	return true
+}
+
+func (dcrh *networkConfigReconcilerHelper) handleKMMVersionLabel(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	// label corresponding node with given kmod version
+	// so that KMM could manage the upgrade by watching the node's version label change
+	if nwConfig.Spec.Driver.Enable != nil && *nwConfig.Spec.Driver.Enable {
+		err := dcrh.kmmHandler.SetNodeVersionLabelAsDesired(ctx, nwConfig, nodes)
+		if err != nil {
+			return fmt.Errorf("failed to update node version label for NetworkConfig %s/%s: %v", nwConfig.Namespace, nwConfig.Name, err)
+		}
+	}
+	return nil
+}
+
+func (dcrh *networkConfigReconcilerHelper) handleNodeLabeller(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList, isOpenShift bool) error {
+	logger := log.FromContext(ctx)
+
+	nlFullName := fmt.Sprintf("%s-%s", nwConfig.Name, nlinternal.NodeLabellerNameSuffix)
+	if !nlinternal.IsNodeLabellerEnabled(nwConfig) {
+		if err := dcrh.finalizeNodeLabeller(ctx, nwConfig); err != nil {
+			return err
+		}
+
+		// clean up node labeller's label when node labeller is disabled
+		// if no label need to be removed, updateNodeLabels won't send request
+		labelledNodes, err := dcrh.getLabelledNodes(ctx, nwConfig, nodes)
+		if err != nil {
+			return err
+		}
+		if err := dcrh.updateNodeLabels(ctx, nwConfig, labelledNodes, false); err != nil {
+			logger.Error(err, "failed to remove node labeller's labels when node labeller is disabled")
+		}
+		logger.Info("skip handling node labeller as it is disbaled", "namespace", nwConfig.Namespace, "name", nlFullName)
+		return nil
+	}
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: nlFullName},
+	}
+	nlOut := nlinternal.GenerateCommonNodeLabellerSpec(nwConfig, isOpenShift)
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
+		scheme, dcrhErr := dcrh.nlHandler.SetNodeLabellerAsDesired(ds, nlOut)
+		if dcrhErr != nil {
+			return dcrhErr
+		}
+		utils.ApplyPodCustomization(&ds.Spec.Template.ObjectMeta, &ds.Spec.Template.Spec, utils.GetPodCustomization(nwConfig, nwConfig.Spec.NodeLabeller.PodCustomization))
+		// resources are not part of the common node labeller spec
+		if resources := nwConfig.Spec.NodeLabeller.Resources; resources != nil && len(ds.Spec.Template.Spec.Containers) > 0 {
+			ds.Spec.Template.Spec.Containers[0].Resources = *resources
+		}
+		// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
+		return controllerutil.SetControllerReference(nwConfig, ds, scheme)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Reconciled node labeller", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
+
+	// the node labeller selector overrides the NetworkConfig selector
+	if selector := nwConfig.Spec.NodeLabeller.Selector; len(selector) > 0 {
+		nodes = &v1.NodeList{}
+		if err := dcrh.client.List(ctx, nodes, client.MatchingLabels(selector)); err != nil {
+			return fmt.Errorf("failed to list the nodes selected by the node labeller: %v", err)
+		}
+	}
+
+	// keep the labels of the enabled label families only on the selected nodes
+	// and clean up the labels of the nodes which are no longer selected
+	if err := dcrh.cleanupNodeLabellerLabels(ctx, nwConfig, nodes); err != nil {
+		return fmt.Errorf("failed to clean up node labeller labels: %v", err)
+	}
+	return nil
+}
+
+// cleanupNodeLabellerLabels removes the labels of the disabled label families from the selected nodes and marks them as labelled,
+// the nodes marked as labelled but no longer selected get all the node labeller labels removed
+func (dcrh *networkConfigReconcilerHelper) cleanupNodeLabellerLabels(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	logger := log.FromContext(ctx)
+	labelledKey := nlinternal.GetLabelledNodeLabel(nwConfig)
+
+	selected := map[string]bool{}
+	for _, node := range nodes.Items {
+		selected[node.Name] = true
+		if err := dcrh.patchNodeLabels(ctx, node.Name, func(nodeLabels map[string]string) bool {
+			updated := false
+			for _, key := range nlinternal.GetStaleLabels(nwConfig, nodeLabels) {
+				delete(nodeLabels, key)
+				updated = true
+			}
+			if _, ok := nodeLabels[labelledKey]; !ok {
+				nodeLabels[labelledKey] = ""
+				updated = true
+			}
+			return updated
+		}); err != nil {
+			logger.Error(err, fmt.Sprintf("failed to clean up node labeller labels of node %v", node.Name))
+		}
+	}
+
+	labelledNodes := &v1.NodeList{}
+	if err := dcrh.client.List(ctx, labelledNodes, client.HasLabels{labelledKey}); err != nil {
+		return fmt.Errorf("failed to list the nodes labelled by the node labeller: %v", err)
+	}
+	unselectedNodes := &v1.NodeList{}
+	for _, node := range labelledNodes.Items {
+		if !selected[node.Name] {
+			unselectedNodes.Items = append(unselectedNodes.Items, node)
+		}
+	}
+	return dcrh.updateNodeLabels(ctx, nwConfig, unselectedNodes, false)
+}
+
+// getLabelledNodes returns the selected nodes and the nodes still carrying the labels of the node labeller
+func (dcrh *networkConfigReconcilerHelper) getLabelledNodes(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) (*v1.NodeList, error) {
+	labelledNodes := &v1.NodeList{}
+	if err := dcrh.client.List(ctx, labelledNodes, client.HasLabels{nlinternal.GetLabelledNodeLabel(nwConfig)}); err != nil {
+		return nil, fmt.Errorf("failed to list the nodes labelled by the node labeller: %v", err)
+	}
+	allNodes := nodes.DeepCopy()
+	selected := map[string]bool{}
+	for _, node := range nodes.Items {
+		selected[node.Name] = true
+	}
+	for _, node := range labelledNodes.Items {
+		if !selected[node.Name] {
+			allNodes.Items = append(allNodes.Items, node)
+		}
+	}
+	return allNodes, nil
+}
+
+func (dcrh *networkConfigReconcilerHelper) handleModuleUpgrade(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList, delete bool) (ctrl.Result, error) {
+	if delete {
+		return dcrh.upgradeMgrHandler.HandleDelete(ctx, nwConfig, nodes)
+	}
+	return dcrh.upgradeMgrHandler.HandleUpgrade(ctx, nwConfig, nodes)
 }
 
 func (dcrh *networkConfigReconcilerHelper) handleMetricsExporter(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
 	logger := log.FromContext(ctx)
-	ds := &appsv1.DaemonSet{
-		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: nwConfig.Name + "-" + metricsexporter.ExporterName},
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: nwConfig.Name + "-" + metricsexporter.ExporterName},
+	}
+
+	// delete if disabled
+	if nwConfig.Spec.MetricsExporter.Enable == nil || !*nwConfig.Spec.MetricsExporter.Enable {
+		return dcrh.finalizeMetricsExporter(ctx, nwConfig)
+	}
+
+	caHash, err := dcrh.handleMetricsExporterTLS(ctx, nwConfig)
+	if err != nil {
+		return err
+	}
+
+	mxOut := expinternal.GenerateCommonExporterSpec(nwConfig)
+	if nwConfig.Spec.MetricsExporter.RbacConfig.StaticAuthorization != nil && nwConfig.Spec.MetricsExporter.RbacConfig.StaticAuthorization.Enable {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: nwConfig.Namespace,
+				Name:      mxOut.RbacConfig.StaticAuthorization.SecretName,
+			},
+		}
+		opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, secret, func() error {
+			scheme, dcrhErr := dcrh.metricsHandler.SetStaticAuthSecretAsDesired(secret, mxOut)
+			if dcrhErr != nil {
+				return dcrhErr
+			}
+			// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
+			return controllerutil.SetControllerReference(nwConfig, secret, scheme)
+		})
+		if err != nil {
+			return err
+		}
+		logger.Info("Reconciled static auth secret", "namespace", secret.Namespace, "name", secret.Name, "result", opRes)
+	}
+
+	// render the collector config before the DaemonSet, so the sidecar can mount it on start
+	var otlpHeaderKeys []string
+	var otlpConfigHash string
+	if utils.IsOTLPEnable(nwConfig) {
+		keys, err := dcrh.getOTLPHeaderKeys(ctx, nwConfig)
+		if err != nil {
+			return err
+		}
+		otlpHeaderKeys = keys
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetOTLPConfigMapName(nwConfig)},
+		}
+		cmRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, cm, func() error {
+			var dcrhErr error
+			if otlpConfigHash, dcrhErr = expinternal.SetOTLPConfigMapAsDesired(cm, nwConfig, otlpHeaderKeys); dcrhErr != nil {
+				return dcrhErr
+			}
+			return controllerutil.SetControllerReference(nwConfig, cm, dcrh.client.Scheme())
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reconcile OTLP collector config %s: %v", cm.Name, err)
+		}
+		logger.Info("Reconciled OTLP collector config", "namespace", cm.Namespace, "name", cm.Name, "result", cmRes)
+	} else if err := dcrh.deleteOTLPConfigMap(ctx, nwConfig); err != nil {
+		return err
+	}
+
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
+		scheme, dcrhErr := dcrh.metricsHandler.SetMetricsExporterAsDesired(ds, mxOut)
+		if dcrhErr != nil {
+			return dcrhErr
+		}
+		utils.ApplyPodCustomization(&ds.Spec.Template.ObjectMeta, &ds.Spec.Template.Spec, utils.GetPodCustomization(nwConfig, nwConfig.Spec.MetricsExporter.PodCustomization))
+		if utils.IsOTLPEnable(nwConfig) {
+			expinternal.SetOTLPCollectorAsDesired(ds, nwConfig, otlpHeaderKeys, otlpConfigHash)
+		}
+		if caHash != "" {
+			// roll the exporter pods when the client CA changes
+			if ds.Spec.Template.Annotations == nil {
+				ds.Spec.Template.Annotations = map[string]string{}
+			}
+			ds.Spec.Template.Annotations[expinternal.KubeRbacCAHashAnnotation] = caHash
+		}
+		// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
+		return controllerutil.SetControllerReference(nwConfig, ds, scheme)
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("Reconciled metrics exporter", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: nwConfig.Name + "-" + metricsexporter.ExporterName},
+	}
+	if utils.IsMetricsServiceEnable(nwConfig) {
+		opRes, err = controllerutil.CreateOrPatch(ctx, dcrh.client, svc, func() error {
+			scheme, dcrhErr := dcrh.metricsHandler.SetMetricsServiceAsDesired(svc, mxOut)
+			if dcrhErr != nil {
+				return dcrhErr
+			}
+			// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
+			return controllerutil.SetControllerReference(nwConfig, svc, scheme)
+		})
+
+		if err != nil {
+			return err
+		}
+		logger.Info("Reconciled metrics service", "namespace", svc.Namespace, "name", svc.Name, "result", opRes)
+	} else if err := dcrh.client.Delete(ctx, svc); err != nil && !k8serrors.IsNotFound(err) {
+		// the metrics are pushed over OTLP, the service is optional
+		return fmt.Errorf("failed to delete metrics exporter service %s: %v", svc.Name, err)
+	}
+
+	if utils.IsPrometheusServiceMonitorEnable(nwConfig) {
+		// Create or update the ServiceMonitor resource
+		sm := &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: nwConfig.Namespace,
+				Name:      nwConfig.Name + "-" + metricsexporter.ExporterName,
+			},
+		}
+
+		opRes, err = controllerutil.CreateOrPatch(ctx, dcrh.client, sm, func() error {
+			scheme, dcrhErr := dcrh.metricsHandler.SetServiceMonitorAsDesired(sm, mxOut)
+			if dcrhErr != nil {
+				return dcrhErr
+			}
+			// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
+			return controllerutil.SetControllerReference(nwConfig, sm, scheme)
+		})
+
+		if err != nil {
+			return err
+		}
+		logger.Info("Reconciled ServiceMonitor", "namespace", sm.Namespace, "name", sm.Name, "result", opRes)
+	} else {
+		// Delete any existing ServiceMonitor if the feature is disabled
+		sm := &monitoringv1.ServiceMonitor{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: nwConfig.Namespace,
+				Name:      nwConfig.Name + "-" + metricsexporter.ExporterName,
+			},
+		}
+
+		err = dcrh.client.Get(ctx, client.ObjectKeyFromObject(sm), sm)
+		if err == nil {
+			// ServiceMonitor exists but the feature is disabled, delete it
+			logger.Info("ServiceMonitor feature is disabled, removing existing ServiceMonitor",
+				"namespace", sm.Namespace, "name", sm.Name)
+			if err := dcrh.client.Delete(ctx, sm); err != nil && !k8serrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete ServiceMonitor: %v", err)
+			}
+		} else if !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			// Some other error occurred
+			return fmt.Errorf("failed to get ServiceMonitor: %v", err)
+		}
+		// If error is IsNotFound or NoMatch (CRD not available), then there's nothing to delete
+	}
+
+	if err := dcrh.handlePrometheusRule(ctx, nwConfig); err != nil {
+		return err
+	}
+	return dcrh.handleGrafanaDashboard(ctx, nwConfig)
+}
+
+// handleMetricsExporterTLS issues the kube-rbac-proxy certificates with the internal CA or cert-manager,
+// deleting them when disabled, and returns the hash of the client CA
+func (dcrh *networkConfigReconcilerHelper) handleMetricsExporterTLS(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) (string, error) {
+	logger := log.FromContext(ctx)
+	if !utils.IsKubeRbacAutoTLSEnable(nwConfig) {
+		return "", dcrh.deleteMetricsExporterTLS(ctx, nwConfig)
+	}
+
+	var caCert []byte
+	var err error
+	if expinternal.IsCertManagerProvider(nwConfig) {
+		caCert, err = dcrh.handleMetricsCertManagerCertificates(ctx, nwConfig)
+	} else {
+		// Certificates left over from the cert-manager provider would overwrite the secrets
+		if err = dcrh.deleteMetricsCertManagerObjects(ctx, nwConfig, true); err != nil {
+			return "", err
+		}
+		caCert, err = dcrh.handleMetricsInternalCertificates(ctx, nwConfig)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetClientCAConfigMapName(nwConfig)},
+	}
+	var caHash string
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, cm, func() error {
+		caHash = expinternal.SetClientCAConfigMapAsDesired(cm, nwConfig, caCert)
+		return controllerutil.SetControllerReference(nwConfig, cm, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to reconcile kube-rbac-proxy client CA %s: %v", cm.Name, err)
+	}
+	logger.Info("Reconciled kube-rbac-proxy client CA", "namespace", cm.Namespace, "name", cm.Name, "result", opRes)
+	return caHash, nil
+}
+
+// handleMetricsInternalCertificates issues the kube-rbac-proxy certificates with the internal CA and returns the CA certificate
+func (dcrh *networkConfigReconcilerHelper) handleMetricsInternalCertificates(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) ([]byte, error) {
+	logger := log.FromContext(ctx)
+	now := time.Now()
+	caSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetCASecretName(nwConfig)},
+	}
+	secrets := []struct {
+		secret *v1.Secret
+		set    func(*v1.Secret) error
+	}{
+		{caSecret, func(secret *v1.Secret) error { return expinternal.SetCASecretAsDesired(secret, nwConfig, now) }},
+		{
+			&v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetServingTLSSecretName(nwConfig)}},
+			func(secret *v1.Secret) error {
+				return expinternal.SetServingTLSSecretAsDesired(secret, caSecret, nwConfig, now)
+			},
+		},
+		{
+			&v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetClientTLSSecretName(nwConfig)}},
+			func(secret *v1.Secret) error {
+				return expinternal.SetClientTLSSecretAsDesired(secret, caSecret, nwConfig, now)
+			},
+		},
+	}
+	for _, s := range secrets {
+		opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, s.secret, func() error {
+			if err := s.set(s.secret); err != nil {
+				return err
+			}
+			return controllerutil.SetControllerReference(nwConfig, s.secret, dcrh.client.Scheme())
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile kube-rbac-proxy certificate secret %s: %v", s.secret.Name, err)
+		}
+		logger.Info("Reconciled kube-rbac-proxy certificate secret", "namespace", s.secret.Namespace, "name", s.secret.Name, "result", opRes)
+	}
+	return caSecret.Data[expinternal.CACertKey], nil
+}
+
+// handleMetricsCertManagerCertificates issues the kube-rbac-proxy certificates with cert-manager and returns the CA
+// of the client certificate, cert-manager renews the certificates
+func (dcrh *networkConfigReconcilerHelper) handleMetricsCertManagerCertificates(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) ([]byte, error) {
+	logger := log.FromContext(ctx)
+	type certManagerObject struct {
+		obj *unstructured.Unstructured
+		set func(*unstructured.Unstructured, *amdv1alpha1.NetworkConfig) error
+	}
+	objects := []certManagerObject{}
+	if expinternal.IsOwnCAIssuer(nwConfig) {
+		objects = append(objects,
+			certManagerObject{newCertManagerObject(expinternal.IssuerGVK, nwConfig, expinternal.GetSelfSignedIssuerName(nwConfig)), expinternal.SetSelfSignedIssuerAsDesired},
+			certManagerObject{newCertManagerObject(expinternal.CertificateGVK, nwConfig, expinternal.GetCAIssuerName(nwConfig)), expinternal.SetCACertificateAsDesired},
+			certManagerObject{newCertManagerObject(expinternal.IssuerGVK, nwConfig, expinternal.GetCAIssuerName(nwConfig)), expinternal.SetCAIssuerAsDesired},
+		)
+	} else if err := dcrh.deleteMetricsCertManagerObjects(ctx, nwConfig, false); err != nil {
+		return nil, err
+	}
+	objects = append(objects,
+		certManagerObject{newCertManagerObject(expinternal.CertificateGVK, nwConfig, expinternal.GetServingTLSSecretName(nwConfig)), expinternal.SetServingCertificateAsDesired},
+		certManagerObject{newCertManagerObject(expinternal.CertificateGVK, nwConfig, expinternal.GetClientTLSSecretName(nwConfig)), expinternal.SetClientCertificateAsDesired},
+	)
+	for _, o := range objects {
+		opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, o.obj, func() error {
+			if err := o.set(o.obj, nwConfig); err != nil {
+				return err
+			}
+			return controllerutil.SetControllerReference(nwConfig, o.obj, dcrh.client.Scheme())
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile cert-manager %s %s: %v", o.obj.GetKind(), o.obj.GetName(), err)
+		}
+		logger.Info("Reconciled cert-manager "+o.obj.GetKind(), "namespace", o.obj.GetNamespace(), "name", o.obj.GetName(), "result", opRes)
+	}
+
+	secret := &v1.Secret{}
+	secretName := expinternal.GetClientTLSSecretName(nwConfig)
+	if err := dcrh.client.Get(ctx, types.NamespacedName{Namespace: nwConfig.Namespace, Name: secretName}, secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("waiting for cert-manager to issue the client certificate secret %s", secretName)
+		}
+		return nil, fmt.Errorf("failed to get client certificate secret %s: %v", secretName, err)
+	}
+	caCert := secret.Data[expinternal.CACertKey]
+	if len(caCert) == 0 {
+		return nil, fmt.Errorf("client certificate secret %s doesn't have the %s key, the issuer must provide its CA", secretName, expinternal.CACertKey)
+	}
+	return caCert, nil
+}
+
+// newCertManagerObject returns a cert-manager object of the NetworkConfig namespace, used as unstructured as the cert-manager API isn't vendored
+func newCertManagerObject(gvk schema.GroupVersionKind, nwConfig *amdv1alpha1.NetworkConfig, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(nwConfig.Namespace)
+	obj.SetName(name)
+	return obj
+}
+
+// deleteMetricsCertManagerObjects deletes the cert-manager objects of the kube-rbac-proxy certificates,
+// all of them or only the CA issuer owned by the operator
+func (dcrh *networkConfigReconcilerHelper) deleteMetricsCertManagerObjects(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, all bool) error {
+	objects := []*unstructured.Unstructured{
+		newCertManagerObject(expinternal.IssuerGVK, nwConfig, expinternal.GetSelfSignedIssuerName(nwConfig)),
+		newCertManagerObject(expinternal.CertificateGVK, nwConfig, expinternal.GetCAIssuerName(nwConfig)),
+		newCertManagerObject(expinternal.IssuerGVK, nwConfig, expinternal.GetCAIssuerName(nwConfig)),
+	}
+	if all {
+		objects = append(objects,
+			newCertManagerObject(expinternal.CertificateGVK, nwConfig, expinternal.GetServingTLSSecretName(nwConfig)),
+			newCertManagerObject(expinternal.CertificateGVK, nwConfig, expinternal.GetClientTLSSecretName(nwConfig)),
+		)
+	}
+	for _, obj := range objects {
+		if err := dcrh.client.Delete(ctx, obj); err != nil && !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to delete cert-manager %s %s: %v", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// deleteMetricsExporterTLS deletes the kube-rbac-proxy certificates issued by the operator,
+// the secrets issued by cert-manager aren't owned by the NetworkConfig
+func (dcrh *networkConfigReconcilerHelper) deleteMetricsExporterTLS(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	if err := dcrh.deleteMetricsCertManagerObjects(ctx, nwConfig, true); err != nil {
+		return err
+	}
+	for _, name := range []string{
+		expinternal.GetCASecretName(nwConfig),
+		expinternal.GetServingTLSSecretName(nwConfig),
+		expinternal.GetClientTLSSecretName(nwConfig),
+	} {
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: name}}
+		if err := dcrh.client.Delete(ctx, secret); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete kube-rbac-proxy certificate secret %s: %v", name, err)
+		}
+	}
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetClientCAConfigMapName(nwConfig)},
+	}
+	if err := dcrh.client.Delete(ctx, cm); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete kube-rbac-proxy client CA %s: %v", cm.Name, err)
+	}
+	return nil
+}
+
+// getOTLPHeaderKeys returns the sorted keys of the OTLP headers secret, each key is sent as a header
+func (dcrh *networkConfigReconcilerHelper) getOTLPHeaderKeys(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) ([]string, error) {
+	ref := nwConfig.Spec.MetricsExporter.OTLP.HeadersSecret
+	if ref == nil {
+		return nil, nil
+	}
+	secret := &v1.Secret{}
+	if err := dcrh.client.Get(ctx, types.NamespacedName{Namespace: nwConfig.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get OTLP headers secret %s: %v", ref.Name, err)
+	}
+	keys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+// deleteOTLPConfigMap deletes the OTLP collector config of the metrics exporter
+func (dcrh *networkConfigReconcilerHelper) deleteOTLPConfigMap(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetOTLPConfigMapName(nwConfig)},
+	}
+	if err := dcrh.client.Delete(ctx, cm); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete OTLP collector config %s: %v", cm.Name, err)
+	}
+	return nil
+}
+
+// handlePrometheusRule reconciles the alert rules of the AMD NICs, deleting them when disabled
+func (dcrh *networkConfigReconcilerHelper) handlePrometheusRule(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	logger := log.FromContext(ctx)
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetPrometheusRuleName(nwConfig)},
+	}
+	if !utils.IsPrometheusRuleEnable(nwConfig) {
+		return dcrh.deletePrometheusRule(ctx, nwConfig)
+	}
+
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, rule, func() error {
+		if err := expinternal.SetPrometheusRuleAsDesired(rule, nwConfig); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(nwConfig, rule, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile PrometheusRule %s: %v", rule.Name, err)
+	}
+	logger.Info("Reconciled PrometheusRule", "namespace", rule.Namespace, "name", rule.Name, "result", opRes)
+	return nil
+}
+
+// handleGrafanaDashboard reconciles the Grafana dashboard ConfigMap, deleting it when disabled
+func (dcrh *networkConfigReconcilerHelper) handleGrafanaDashboard(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	logger := log.FromContext(ctx)
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetGrafanaDashboardName(nwConfig)},
+	}
+	if !utils.IsGrafanaDashboardEnable(nwConfig) {
+		return dcrh.deleteGrafanaDashboard(ctx, nwConfig)
+	}
+
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, cm, func() error {
+		if err := expinternal.SetGrafanaDashboardAsDesired(cm, nwConfig); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(nwConfig, cm, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile Grafana dashboard %s: %v", cm.Name, err)
+	}
+	logger.Info("Reconciled Grafana dashboard", "namespace", cm.Namespace, "name", cm.Name, "result", opRes)
+	return nil
+}
+
+func (dcrh *networkConfigReconcilerHelper) deletePrometheusRule(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetPrometheusRuleName(nwConfig)},
+	}
+	// the PrometheusRule CRD may not be installed
+	if err := dcrh.client.Delete(ctx, rule); err != nil && !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete PrometheusRule %s: %v", rule.Name, err)
+	}
+	return nil
+}
+
+func (dcrh *networkConfigReconcilerHelper) deleteGrafanaDashboard(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: expinternal.GetGrafanaDashboardName(nwConfig)},
+	}
+	if err := dcrh.client.Delete(ctx, cm); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Grafana dashboard %s: %v", cm.Name, err)
+	}
+	return nil
+}
+
+func (dcrh *networkConfigReconcilerHelper) handleSecondaryNetwork(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, isOpenShift bool) error {
+	logger := log.FromContext(ctx)
+
+	if err := dcrh.handleMultus(ctx, nwConfig, isOpenShift); err != nil {
+		return err
+	}
+
+	nwConfig.Status.NodeCNIPluginsStatus = nil
+	if nwConfig.Spec.SecondaryNetwork.CniPlugins != nil {
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: nwConfig.Name + "-" + secondarynetwork.CNIPluginsName},
+		}
+
+		// delete if disabled
+		if nwConfig.Spec.SecondaryNetwork.CniPlugins.Enable == nil || !*nwConfig.Spec.SecondaryNetwork.CniPlugins.Enable {
+			if err := dcrh.finalizeCNIPlugins(ctx, nwConfig); err != nil {
+				return err
+			}
+		} else {
+			opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
+				scheme, dcrhErr := dcrh.secondaryNetworkHandler.SetCNIPluginsAsDesired(nwConfig.Name, ds, nwConfig.Spec.SecondaryNetwork.CniPlugins, nwConfig.Spec.Selector,
+					secondarynetwork.UsesClusterIPAM(nwConfig))
+				if dcrhErr != nil {
+					return dcrhErr
+				}
+				utils.ApplyPodCustomization(&ds.Spec.Template.ObjectMeta, &ds.Spec.Template.Spec,
+					utils.GetPodCustomization(nwConfig, nwConfig.Spec.SecondaryNetwork.CniPlugins.PodCustomization))
+				return controllerutil.SetControllerReference(nwConfig, ds, scheme)
+			})
+			if err != nil {
+				return err
+			}
+			logger.Info("Reconciled CNI plugins", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
+
+			if nwConfig.Status.NodeCNIPluginsStatus, err = dcrh.getCNIPluginsStatus(ctx, nwConfig, ds.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return dcrh.handleNetworkAttachments(ctx, nwConfig)
+}
+
+// handleMultus deploys the Multus thick plugin if enabled and reports its rollout in the MultusReady condition,
+// on OpenShift Multus is part of the cluster network and is never deployed by the operator
+func (dcrh *networkConfigReconcilerHelper) handleMultus(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, isOpenShift bool) error {
+	logger := log.FromContext(ctx)
+
+	multus := nwConfig.Spec.SecondaryNetwork.Multus
+	if multus == nil || multus.Enable == nil || !*multus.Enable {
+		dcrh.conditionUpdater.DeleteMultusReadyCondition(nwConfig)
+		return dcrh.finalizeMultus(ctx, nwConfig)
+	}
+	if !secondarynetwork.IsMultusEnabled(nwConfig, isOpenShift) {
+		dcrh.conditionUpdater.SetMultusReadyCondition(nwConfig, metav1.ConditionTrue, conditions.MultusProvidedByPlatform,
+			"Multus is provided by OpenShift, the operator does not deploy it")
+		return dcrh.finalizeMultus(ctx, nwConfig)
+	}
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: secondarynetwork.GetMultusName(nwConfig)},
+	}
+	configHash := ""
+	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, cm, func() error {
+		var err error
+		if configHash, err = secondarynetwork.SetMultusConfigMapAsDesired(cm, nwConfig); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(nwConfig, cm, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile Multus daemon config: %v", err)
+	}
+	logger.Info("Reconciled Multus daemon config", "namespace", cm.Namespace, "name", cm.Name, "result", opRes)
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: secondarynetwork.GetMultusName(nwConfig)},
+	}
+	opRes, err = controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
+		if err := secondarynetwork.SetMultusAsDesired(ds, nwConfig, configHash); err != nil {
+			return err
+		}
+		utils.ApplyPodCustomization(&ds.Spec.Template.ObjectMeta, &ds.Spec.Template.Spec, utils.GetPodCustomization(nwConfig, nwConfig.Spec.SecondaryNetwork.Multus.PodCustomization))
+		return controllerutil.SetControllerReference(nwConfig, ds, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile Multus daemonset: %v", err)
+	}
+	logger.Info("Reconciled Multus", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
+
+	if ds.Status.DesiredNumberScheduled > 0 && ds.Status.NumberReady == ds.Status.DesiredNumberScheduled &&
+		ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled {
+		dcrh.conditionUpdater.SetMultusReadyCondition(nwConfig, metav1.ConditionTrue, conditions.MultusReady,
+			fmt.Sprintf("Multus is ready on %d nodes", ds.Status.NumberReady))
+	} else {
+		dcrh.conditionUpdater.SetMultusReadyCondition(nwConfig, metav1.ConditionFalse, conditions.MultusNotReady,
+			fmt.Sprintf("Multus is ready on %d of %d nodes, %d updated", ds.Status.NumberReady,
+				ds.Status.DesiredNumberScheduled, ds.Status.UpdatedNumberScheduled))
+	}
+	return nil
+}
+
+// getCNIPluginsStatus reads the install status of the CNI plugins from the install container of the CNI plugins pod on each node
+func (dcrh *networkConfigReconcilerHelper) getCNIPluginsStatus(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, dsName string) (map[string]amdv1alpha1.CNIPluginsStatus, error) {
+	pods := v1.PodList{}
+	if err := dcrh.client.List(ctx, &pods,
+		client.InNamespace(nwConfig.Namespace),
+		client.MatchingLabels{"daemonset-name": dsName}); err != nil {
+		return nil, fmt.Errorf("failed to list CNI plugins pods: %v", err)
+	}
+	nodeStatus := map[string]amdv1alpha1.CNIPluginsStatus{}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		status := amdv1alpha1.CNIPluginsStatus{}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+				status.Ready = true
+			}
+		}
+		for _, containerStatus := range pod.Status.InitContainerStatuses {
+			if containerStatus.Name != secondarynetwork.CNIPluginsInstallContainer {
+				continue
+			}
+			// a failed install is restarted, its result is kept in the last termination state
+			terminated := containerStatus.State.Terminated
+			if terminated == nil {
+				terminated = containerStatus.LastTerminationState.Terminated
+			}
+			if terminated != nil {
+				status.Plugins = secondarynetwork.ParseCNIPluginsStatus(terminated.Message)
+			}
+		}
+		nodeStatus[pod.Spec.NodeName] = status
+	}
+	return nodeStatus, nil
+}
+
+// handleNetworkAttachments renders the NetworkAttachmentDefinitions of the network attachments in their namespaces
+// and deletes the ones which are not desired anymore
+func (dcrh *networkConfigReconcilerHelper) handleNetworkAttachments(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	logger := log.FromContext(ctx)
+
+	desiredNADs := map[types.NamespacedName]bool{}
+	for _, attachment := range nwConfig.Spec.SecondaryNetwork.NetworkAttachments {
+		for _, namespace := range attachment.Namespaces {
+			nad := &netattachdefv1.NetworkAttachmentDefinition{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: attachment.Name},
+			}
+			desiredNADs[types.NamespacedName{Namespace: namespace, Name: attachment.Name}] = true
+			opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, nad, func() error {
+				if !nad.CreationTimestamp.IsZero() && !isOwnedByNetworkConfig(nad.Labels, nwConfig) {
+					return fmt.Errorf("NetworkAttachmentDefinition %s/%s is not managed by NetworkConfig %s/%s", nad.Namespace, nad.Name, nwConfig.Namespace, nwConfig.Name)
+				}
+				return secondarynetwork.SetNetworkAttachmentAsDesired(nad, attachment, nwConfig)
+			})
+			if err != nil {
+				if k8serrors.IsNotFound(err) {
+					// the namespace doesn't exist yet, its creation triggers the reconcile creating the NetworkAttachmentDefinition
+					logger.Info("skipping NetworkAttachmentDefinition, namespace not found", "namespace", namespace, "name", attachment.Name)
+					continue
+				}
+				return fmt.Errorf("failed to reconcile NetworkAttachmentDefinition %s/%s: %v", namespace, attachment.Name, err)
+			}
+			logger.Info("Reconciled NetworkAttachmentDefinition", "namespace", namespace, "name", attachment.Name, "result", opRes)
+		}
+	}
+	if err := dcrh.deleteNetworkAttachments(ctx, nwConfig, desiredNADs); err != nil {
+		return err
 	}
+	return dcrh.handleIPPools(ctx, nwConfig)
+}
 
-	// delete if disabled
-	if nwConfig.Spec.MetricsExporter.Enable == nil || !*nwConfig.Spec.MetricsExporter.Enable {
-		return dcrh.finalizeMetricsExporter(ctx, nwConfig)
+// handleIPPools reconciles the NICIPPools of the network attachments using the cluster IPAM mode,
+// the leases of the pods which are gone are released and the pools not desired anymore are deleted
+func (dcrh *networkConfigReconcilerHelper) handleIPPools(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	logger := log.FromContext(ctx)
+
+	desiredPools := secondarynetwork.GetDesiredIPPools(nwConfig)
+	for name, spec := range desiredPools {
+		pool := &amdv1alpha1.NICIPPool{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: name},
+		}
+		var opRes controllerutil.OperationResult
+		// the CNI plugin updates the leases concurrently, update with the resource version so that no lease is lost
+		if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			current := &amdv1alpha1.NICIPPool{}
+			staleAllocations := map[string]amdv1alpha1.NICIPAllocation{}
+			if err := dcrh.client.Get(ctx, client.ObjectKeyFromObject(pool), current); err == nil {
+				staleAllocations = dcrh.getStaleIPPoolAllocations(ctx, current)
+			} else if !k8serrors.IsNotFound(err) {
+				return err
+			}
+			var err error
+			opRes, err = controllerutil.CreateOrUpdate(ctx, dcrh.client, pool, func() error {
+				if err := secondarynetwork.SetIPPoolAsDesired(pool, spec, nwConfig); err != nil {
+					return err
+				}
+				for ip, allocation := range staleAllocations {
+					// a lease which changed since it was checked is kept
+					if pool.Spec.Allocations[ip] == allocation {
+						logger.Info("releasing stale NICIPPool lease", "pool", pool.Name, "ip", ip,
+							"podRef", allocation.PodRef, "podUID", allocation.PodUID, "node", allocation.Node)
+						delete(pool.Spec.Allocations, ip)
+					}
+				}
+				return controllerutil.SetControllerReference(nwConfig, pool, dcrh.client.Scheme())
+			})
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to reconcile NICIPPool %s: %v", name, err)
+		}
+		logger.Info("Reconciled NICIPPool", "namespace", pool.Namespace, "name", pool.Name, "result", opRes)
 	}
 
-	mxOut := expinternal.GenerateCommonExporterSpec(nwConfig)
-	if nwConfig.Spec.MetricsExporter.RbacConfig.StaticAuthorization != nil && nwConfig.Spec.MetricsExporter.RbacConfig.StaticAuthorization.Enable {
-		secret := &v1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: nwConfig.Namespace,
-				Name:      mxOut.RbacConfig.StaticAuthorization.SecretName,
-			},
+	pools := amdv1alpha1.NICIPPoolList{}
+	if err := dcrh.client.List(ctx, &pools, client.InNamespace(nwConfig.Namespace), client.MatchingLabels{utils.CRNameLabel: nwConfig.Name}); err != nil {
+		if meta.IsNoMatchError(err) && len(desiredPools) == 0 {
+			return nil
 		}
-		opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, secret, func() error {
-			scheme, dcrhErr := dcrh.metricsHandler.SetStaticAuthSecretAsDesired(secret, mxOut)
-			if dcrhErr != nil {
-				return dcrhErr
+		return fmt.Errorf("failed to list NICIPPools: %v", err)
+	}
+	for _, pool := range pools.Items {
+		if _, ok := desiredPools[pool.Name]; ok {
+			continue
+		}
+		logger.Info("deleting NICIPPool", "namespace", pool.Namespace, "name", pool.Name)
+		if err := dcrh.client.Delete(ctx, &pool); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete NICIPPool %s: %v", pool.Name, err)
+		}
+	}
+	return nil
+}
+
+// getStaleIPPoolAllocations returns the leases the CNI plugin didn't release, e.g. when the node went down,
+// a pod lease is stale once the pod is gone, terminated or recreated, a host address reservation once the node is gone
+func (dcrh *networkConfigReconcilerHelper) getStaleIPPoolAllocations(ctx context.Context, pool *amdv1alpha1.NICIPPool) map[string]amdv1alpha1.NICIPAllocation {
+	staleAllocations := map[string]amdv1alpha1.NICIPAllocation{}
+	for ip, allocation := range pool.Spec.Allocations {
+		stale := false
+		if allocation.HostReserved {
+			node := v1.Node{}
+			stale = k8serrors.IsNotFound(dcrh.client.Get(ctx, types.NamespacedName{Name: allocation.Node}, &node))
+		} else {
+			namespace, name, _ := strings.Cut(allocation.PodRef, "/")
+			pod := v1.Pod{}
+			if err := dcrh.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &pod); err != nil {
+				stale = k8serrors.IsNotFound(err)
+			} else {
+				// a pod recreated with the same name, e.g. by a StatefulSet, gets its own lease
+				stale = pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed ||
+					(allocation.PodUID != "" && string(pod.UID) != allocation.PodUID) ||
+					(allocation.Node != "" && pod.Spec.NodeName != "" && pod.Spec.NodeName != allocation.Node)
 			}
-			// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
-			return controllerutil.SetControllerReference(nwConfig, secret, scheme)
-		})
-		if err != nil {
-			return err
 		}
-		logger.Info("Reconciled static auth secret", "namespace", secret.Namespace, "name", secret.Name, "result", opRes)
+		if stale {
+			staleAllocations[ip] = allocation
+		}
 	}
+	return staleAllocations
+}
 
-	opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
-		scheme, dcrhErr := dcrh.metricsHandler.SetMetricsExporterAsDesired(ds, mxOut)
-		if dcrhErr != nil {
-			return dcrhErr
+// findNetworkConfigsForIPPoolPod returns the NetworkConfigs leasing addresses to the pods of the namespace of the deleted pod,
+// their reconcile releases the leases of the pod if the CNI plugin didn't
+func (dcrh *networkConfigReconcilerHelper) findNetworkConfigsForIPPoolPod(ctx context.Context, pod client.Object) []reconcile.Request {
+	reqs := []reconcile.Request{}
+	logger := log.FromContext(ctx)
+	networkConfigList, err := dcrh.listNetworkConfigs(ctx)
+	if err != nil {
+		logger.Error(err, "failed to list networkconfigs")
+		return reqs
+	}
+	for _, nwConfig := range networkConfigList.Items {
+		for _, attachment := range nwConfig.Spec.SecondaryNetwork.NetworkAttachments {
+			if attachment.IPAM.Mode == "cluster" && slices.Contains(attachment.Namespaces, pod.GetNamespace()) {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: nwConfig.Namespace, Name: nwConfig.Name}})
+				break
+			}
 		}
-		// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
-		return controllerutil.SetControllerReference(nwConfig, ds, scheme)
-	})
+	}
+	return reqs
+}
+
+// findNetworkConfigsForNamespace returns the NetworkConfigs with network attachments in the created namespace,
+// their NetworkAttachmentDefinitions are skipped until the namespace exists
+func (dcrh *networkConfigReconcilerHelper) findNetworkConfigsForNamespace(ctx context.Context, ns client.Object) []reconcile.Request {
+	reqs := []reconcile.Request{}
+	logger := log.FromContext(ctx)
+	networkConfigList, err := dcrh.listNetworkConfigs(ctx)
 	if err != nil {
-		return err
+		logger.Error(err, "failed to list networkconfigs")
+		return reqs
 	}
-	logger.Info("Reconciled metrics exporter", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
+	for _, nwConfig := range networkConfigList.Items {
+		for _, attachment := range nwConfig.Spec.SecondaryNetwork.NetworkAttachments {
+			if slices.Contains(attachment.Namespaces, ns.GetName()) {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: nwConfig.Namespace, Name: nwConfig.Name}})
+				break
+			}
+		}
+	}
+	return reqs
+}
 
-	svc := &v1.Service{
-		ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: nwConfig.Name + "-" + metricsexporter.ExporterName},
+// deleteNetworkAttachments deletes the NetworkAttachmentDefinitions managed for the NetworkConfig which are not desired anymore
+func (dcrh *networkConfigReconcilerHelper) deleteNetworkAttachments(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, desiredNADs map[types.NamespacedName]bool) error {
+	logger := log.FromContext(ctx)
+	nads := netattachdefv1.NetworkAttachmentDefinitionList{}
+	if err := dcrh.client.List(ctx, &nads, client.MatchingLabels(utils.GetOwnerLabels(nwConfig))); err != nil {
+		if meta.IsNoMatchError(err) {
+			// Multus is not installed, nothing could have been created
+			return nil
+		}
+		return fmt.Errorf("failed to list NetworkAttachmentDefinitions: %v", err)
 	}
-	opRes, err = controllerutil.CreateOrPatch(ctx, dcrh.client, svc, func() error {
-		scheme, dcrhErr := dcrh.metricsHandler.SetMetricsServiceAsDesired(svc, mxOut)
-		if dcrhErr != nil {
-			return dcrhErr
+	for _, nad := range nads.Items {
+		if desiredNADs[types.NamespacedName{Namespace: nad.Namespace, Name: nad.Name}] {
+			continue
 		}
-		// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
-		return controllerutil.SetControllerReference(nwConfig, svc, scheme)
-	})
+		logger.Info("deleting NetworkAttachmentDefinition", "namespace", nad.Namespace, "name", nad.Name)
+		if err := dcrh.client.Delete(ctx, &nad); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete NetworkAttachmentDefinition %s/%s: %v", nad.Namespace, nad.Name, err)
+		}
+	}
+	return nil
+}
+
+// handleNodeReadiness publishes the AMDNetworkReady condition and label on the selected nodes
+func (dcrh *networkConfigReconcilerHelper) handleNodeReadiness(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
+	logger := log.FromContext(ctx)
+	var profiles *kmmmodule.OSProfiles
+	if nwConfig.Spec.Driver.Enable != nil && *nwConfig.Spec.Driver.Enable {
+		var err error
+		if profiles, err = dcrh.kmmHandler.GetOSProfiles(ctx, nwConfig); err != nil {
+			return err
+		}
+	}
+	for _, node := range nodes.Items {
+		condition := dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, &node, profiles)
+		if err := dcrh.setNodeNetworkReadyCondition(ctx, node.Name, condition); err != nil {
+			logger.Error(err, fmt.Sprintf("failed to set %v condition on node %v", conditions.NodeConditionTypeAMDNetworkReady, node.Name))
+		}
+	}
 
+	// remove the condition and label from the nodes which are not selected anymore
+	unselectedNodes, err := dcrh.getUnselectedLabelledNodes(ctx, nwConfig, nodes, utils.NetworkReadyLabelKey)
 	if err != nil {
 		return err
 	}
-	logger.Info("Reconciled metrics service", "namespace", svc.Namespace, "name", svc.Name, "result", opRes)
+	return dcrh.finalizeNodeReadiness(ctx, unselectedNodes)
+}
 
-	if utils.IsPrometheusServiceMonitorEnable(nwConfig) {
-		// Create or update the ServiceMonitor resource
-		sm := &monitoringv1.ServiceMonitor{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: nwConfig.Namespace,
-				Name:      nwConfig.Name + "-" + metricsexporter.ExporterName,
-			},
+// getUnselectedLabelledNodes returns the nodes carrying the label which are not in nodes,
+// the nodes assigned to another NetworkConfig are left to it
+func (dcrh *networkConfigReconcilerHelper) getUnselectedLabelledNodes(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList, labelKey string) (*v1.NodeList, error) {
+	labelledNodes := &v1.NodeList{}
+	if err := dcrh.client.List(ctx, labelledNodes, client.HasLabels{labelKey}); err != nil {
+		return nil, fmt.Errorf("failed to list the nodes labelled with %v: %v", labelKey, err)
+	}
+	selected := map[string]bool{}
+	if nodes != nil {
+		for _, node := range nodes.Items {
+			selected[node.Name] = true
+		}
+	}
+	namespacedName := types.NamespacedName{Namespace: nwConfig.Namespace, Name: nwConfig.Name}.String()
+	unselectedNodes := &v1.NodeList{}
+	for _, node := range labelledNodes.Items {
+		if selected[node.Name] {
+			continue
+		}
+		if owner, ok := dcrh.nodeAssignments[node.Name]; ok && owner != namespacedName {
+			continue
+		}
+		unselectedNodes.Items = append(unselectedNodes.Items, node)
+	}
+	return unselectedNodes, nil
+}
+
+// getNodeNetworkReadyCondition evaluates the AMDNetworkReady condition of the node, the first unmet requirement is reported
+func (dcrh *networkConfigReconcilerHelper) getNodeNetworkReadyCondition(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, node *v1.Node, profiles *kmmmodule.OSProfiles) v1.NodeCondition {
+	notReady := func(reason, message string) v1.NodeCondition {
+		return v1.NodeCondition{
+			Type:    conditions.NodeConditionTypeAMDNetworkReady,
+			Status:  v1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
 		}
+	}
 
-		opRes, err = controllerutil.CreateOrPatch(ctx, dcrh.client, sm, func() error {
-			scheme, dcrhErr := dcrh.metricsHandler.SetServiceMonitorAsDesired(sm, mxOut)
-			if dcrhErr != nil {
-				return dcrhErr
+	// driver loaded at the desired version
+	if profiles != nil {
+		if !utils.HasNodeLabelKey(*node, labels.GetKernelModuleReadyNodeLabel(nwConfig.Namespace, nwConfig.Name)) {
+			return notReady(conditions.DriverNotReady, "driver is not loaded")
+		}
+		desiredVersion, err := profiles.GetDriverVersion(*node, *nwConfig)
+		if err != nil {
+			return notReady(conditions.DriverNotReady, err.Error())
+		}
+		containerImage := nwConfig.Status.NodeModuleStatus[node.Name].ContainerImage
+		if !strings.HasSuffix(containerImage, "-"+desiredVersion) {
+			return notReady(conditions.DriverNotReady, fmt.Sprintf("loaded driver image %q is not at the desired version %v", containerImage, desiredVersion))
+		}
+	}
+
+	// device plugin registered and expected NIC count advertised
+	capacity, allocatable := getNICResourceCount(nwConfig, node)
+	if isDevicePluginEnabled(nwConfig) {
+		if capacity == 0 {
+			return notReady(conditions.DevicePluginNotRegistered, "device plugin didn't register any NIC resource")
+		}
+		if expected := int64(nwConfig.Spec.NodeReadiness.ExpectedNICCount); expected > 0 {
+			if allocatable < expected {
+				return notReady(conditions.NICCountMismatch, fmt.Sprintf("%v NIC resources are allocatable, expected %v", allocatable, expected))
 			}
-			// Probably can switch to storing "scheme" in NetworkConfigReconciler struct
-			return controllerutil.SetControllerReference(nwConfig, sm, scheme)
-		})
+		} else if allocatable < capacity {
+			return notReady(conditions.NICCountMismatch, fmt.Sprintf("%v of %v NIC resources are allocatable", allocatable, capacity))
+		}
+	}
 
+	// CNI plugins installed
+	cniPlugins := nwConfig.Spec.SecondaryNetwork.CniPlugins
+	if cniPlugins != nil && cniPlugins.Enable != nil && *cniPlugins.Enable {
+		ready, err := dcrh.isDaemonSetPodReady(ctx, nwConfig, nwConfig.Name+"-"+secondarynetwork.CNIPluginsName, node.Name)
 		if err != nil {
-			return err
+			return notReady(conditions.CNIPluginsNotReady, err.Error())
 		}
-		logger.Info("Reconciled ServiceMonitor", "namespace", sm.Namespace, "name", sm.Name, "result", opRes)
-	} else {
-		// Delete any existing ServiceMonitor if the feature is disabled
-		sm := &monitoringv1.ServiceMonitor{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace: nwConfig.Namespace,
-				Name:      nwConfig.Name + "-" + metricsexporter.ExporterName,
-			},
+		if !ready {
+			return notReady(conditions.CNIPluginsNotReady, "CNI plugins are not installed")
 		}
+	}
 
-		err = dcrh.client.Get(ctx, client.ObjectKeyFromObject(sm), sm)
-		if err == nil {
-			// ServiceMonitor exists but the feature is disabled, delete it
-			logger.Info("ServiceMonitor feature is disabled, removing existing ServiceMonitor",
-				"namespace", sm.Namespace, "name", sm.Name)
-			if err := dcrh.client.Delete(ctx, sm); err != nil && !k8serrors.IsNotFound(err) {
-				return fmt.Errorf("failed to delete ServiceMonitor: %v", err)
-			}
-		} else if !k8serrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
-			// Some other error occurred
-			return fmt.Errorf("failed to get ServiceMonitor: %v", err)
+	// DRA driver running
+	if drainternal.IsDRADriverEnabled(nwConfig) {
+		ready, err := dcrh.isDaemonSetPodReady(ctx, nwConfig, drainternal.GetDRADriverName(nwConfig), node.Name)
+		if err != nil {
+			return notReady(conditions.DRADriverNotReady, err.Error())
+		}
+		if !ready {
+			return notReady(conditions.DRADriverNotReady, "DRA driver is not running")
 		}
-		// If error is IsNotFound or NoMatch (CRD not available), then there's nothing to delete
 	}
 
-	return nil
+	// host config applied
+	if hostconfiginternal.IsHostConfigEnabled(nwConfig) {
+		if status, ok := nwConfig.Status.NodeHostConfigStatus[node.Name]; !ok || !status.InSync {
+			return notReady(conditions.HostConfigNotInSync, "host config is not applied")
+		}
+	}
+
+	message := "NICs are allocated by the DRA driver"
+	if isDevicePluginEnabled(nwConfig) {
+		message = fmt.Sprintf("%v NIC resources are allocatable", allocatable)
+	}
+	return v1.NodeCondition{
+		Type:    conditions.NodeConditionTypeAMDNetworkReady,
+		Status:  v1.ConditionTrue,
+		Reason:  conditions.NetworkReady,
+		Message: message,
+	}
 }
 
-func (dcrh *networkConfigReconcilerHelper) handleSecondaryNetwork(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
-	logger := log.FromContext(ctx)
+// isDevicePluginEnabled returns true if the device plugin is deployed for the NetworkConfig
+func isDevicePluginEnabled(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	return nwConfig.Spec.DevicePlugin.EnableDevicePlugin == nil || *nwConfig.Spec.DevicePlugin.EnableDevicePlugin
+}
 
-	if nwConfig.Spec.SecondaryNetwork.CniPlugins != nil {
-		ds := &appsv1.DaemonSet{
-			ObjectMeta: metav1.ObjectMeta{Namespace: nwConfig.Namespace, Name: nwConfig.Name + "-" + secondarynetwork.CNIPluginsName},
+// isDaemonSetPodReady checks if the pod of the operand DaemonSet on the node is ready
+func (dcrh *networkConfigReconcilerHelper) isDaemonSetPodReady(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, dsName, nodeName string) (bool, error) {
+	pods := v1.PodList{}
+	if err := dcrh.client.List(ctx, &pods,
+		client.InNamespace(nwConfig.Namespace),
+		client.MatchingFields{podNodeNameIndexKey: nodeName},
+		client.MatchingLabels{"daemonset-name": dsName}); err != nil {
+		return false, fmt.Errorf("failed to list %v pods: %v", dsName, err)
+	}
+	for _, pod := range pods.Items {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+				return true, nil
+			}
 		}
+	}
+	return false, nil
+}
 
-		// delete if disabled
-		if nwConfig.Spec.SecondaryNetwork.CniPlugins.Enable == nil || !*nwConfig.Spec.SecondaryNetwork.CniPlugins.Enable {
-			return dcrh.finalizeCNIPlugins(ctx, nwConfig)
-		}
+// setNodeNetworkReadyCondition updates the AMDNetworkReady condition and label of the node if changed
+func (dcrh *networkConfigReconcilerHelper) setNodeNetworkReadyCondition(ctx context.Context, nodeName string, condition v1.NodeCondition) error {
+	node := &v1.Node{}
+	if err := dcrh.client.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		return err
+	}
 
-		opRes, err := controllerutil.CreateOrPatch(ctx, dcrh.client, ds, func() error {
-			scheme, dcrhErr := dcrh.secondaryNetworkHandler.SetCNIPluginsAsDesired(nwConfig.Name, ds, nwConfig.Spec.SecondaryNetwork.CniPlugins, nwConfig.Spec.Selector)
-			if dcrhErr != nil {
-				return dcrhErr
-			}
-			return controllerutil.SetControllerReference(nwConfig, ds, scheme)
-		})
-		if err != nil {
+	nodeCopy := node.DeepCopy()
+	now := metav1.Now()
+	condition.LastHeartbeatTime = now
+	condition.LastTransitionTime = now
+	found := false
+	for i, existing := range node.Status.Conditions {
+		if existing.Type != condition.Type {
+			continue
+		}
+		found = true
+		if existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+			break
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		node.Status.Conditions[i] = condition
+	}
+	if !found {
+		node.Status.Conditions = append(node.Status.Conditions, condition)
+	}
+	// use strategic merge patch to only touch our own condition, the other ones are owned by kubelet
+	if !equality.Semantic.DeepEqual(nodeCopy.Status.Conditions, node.Status.Conditions) {
+		if err := dcrh.client.Status().Patch(ctx, node, client.StrategicMergeFrom(nodeCopy)); err != nil {
 			return err
 		}
-		logger.Info("Reconciled CNI plugins", "namespace", ds.Namespace, "name", ds.Name, "result", opRes)
 	}
 
+	labelValue := strconv.FormatBool(condition.Status == v1.ConditionTrue)
+	if node.Labels[utils.NetworkReadyLabelKey] != labelValue {
+		nodeCopy = node.DeepCopy()
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[utils.NetworkReadyLabelKey] = labelValue
+		return dcrh.client.Patch(ctx, node, client.MergeFrom(nodeCopy))
+	}
+	return nil
+}
+
+// finalizeNodeReadiness removes the AMDNetworkReady condition and label from the nodes
+func (dcrh *networkConfigReconcilerHelper) finalizeNodeReadiness(ctx context.Context, nodes *v1.NodeList) error {
+	logger := log.FromContext(ctx)
+	if nodes == nil {
+		return nil
+	}
+	for _, item := range nodes.Items {
+		node := &v1.Node{}
+		if err := dcrh.client.Get(ctx, client.ObjectKey{Name: item.Name}, node); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				logger.Error(err, fmt.Sprintf("failed to get node %v", item.Name))
+			}
+			continue
+		}
+		nodeCopy := node.DeepCopy()
+		conds := []v1.NodeCondition{}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type != conditions.NodeConditionTypeAMDNetworkReady {
+				conds = append(conds, condition)
+			}
+		}
+		if len(conds) != len(node.Status.Conditions) {
+			node.Status.Conditions = conds
+			if err := dcrh.client.Status().Patch(ctx, node, client.StrategicMergeFrom(nodeCopy)); err != nil {
+				return fmt.Errorf("failed to remove %v condition from node %v: %v", conditions.NodeConditionTypeAMDNetworkReady, node.Name, err)
+			}
+		}
+		if _, ok := node.Labels[utils.NetworkReadyLabelKey]; ok {
+			nodeCopy = node.DeepCopy()
+			delete(node.Labels, utils.NetworkReadyLabelKey)
+			if err := dcrh.client.Patch(ctx, node, client.MergeFrom(nodeCopy)); err != nil {
+				return fmt.Errorf("failed to remove %v label from node %v: %v", utils.NetworkReadyLabelKey, node.Name, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -1418,48 +3295,57 @@ func (dcrh *networkConfigReconcilerHelper) handleConfigManager(ctx context.Conte
 func (dcrh *networkConfigReconcilerHelper) updateNodeLabels(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList, isFinalizer bool) error {
 	logger := log.FromContext(ctx)
 	labelKey, _ := kmmmodule.GetVersionLabelKV(nwConfig)
+	labelledKey := nlinternal.GetLabelledNodeLabel(nwConfig)
 
 	for _, node := range nodes.Items {
-		// add retry logic here
-		// in case Node resource is being updated by multiple clients concurrently
-		if retryErr := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := dcrh.patchNodeLabels(ctx, node.Name, func(nodeLabels map[string]string) bool {
 			updated := false
-			nodeObj := &v1.Node{}
-			if err := dcrh.client.Get(ctx, client.ObjectKey{Name: node.Name}, nodeObj); err != nil {
-				return err
-			}
-			nodeObjCopy := nodeObj.DeepCopy()
-
 			if isFinalizer {
-				if _, ok := nodeObj.Labels[labelKey]; ok {
-					delete(nodeObj.Labels, labelKey)
+				if _, ok := nodeLabels[labelKey]; ok {
+					delete(nodeLabels, labelKey)
 					updated = true
 				}
 			}
 
-			for k := range nodeObjCopy.Labels {
-				if strings.HasPrefix(k, "beta.amd.com/nic") ||
-					strings.HasPrefix(k, "amd.com/nic") {
-					delete(nodeObj.Labels, k)
+			for k := range nodeLabels {
+				if k == labelledKey || nlinternal.IsNodeLabellerLabel(k) {
+					delete(nodeLabels, k)
 					updated = true
 				}
 			}
-
-			// use PATCH instead of UPDATE
-			// to minimize the resource usage, compared to update the whole Node resource
-			if updated {
-				logger.Info(fmt.Sprintf("updating node-labeller labels in %v", nodeObj.Name))
-				return dcrh.client.Patch(ctx, nodeObj, client.MergeFrom(nodeObjCopy))
-			}
-
-			return nil
-		}); retryErr != nil {
-			logger.Error(retryErr, fmt.Sprintf("failed to remove labels from node %+v", node.Name))
+			return updated
+		}); err != nil {
+			logger.Error(err, fmt.Sprintf("failed to remove labels from node %+v", node.Name))
 		}
 	}
 	return nil
 }
 
+// patchNodeLabels patches the labels of the node when mutate reports an update
+func (dcrh *networkConfigReconcilerHelper) patchNodeLabels(ctx context.Context, nodeName string, mutate func(nodeLabels map[string]string) bool) error {
+	logger := log.FromContext(ctx)
+	// add retry logic here
+	// in case Node resource is being updated by multiple clients concurrently
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		nodeObj := &v1.Node{}
+		if err := dcrh.client.Get(ctx, client.ObjectKey{Name: nodeName}, nodeObj); err != nil {
+			return err
+		}
+		nodeObjCopy := nodeObj.DeepCopy()
+		if nodeObj.Labels == nil {
+			nodeObj.Labels = map[string]string{}
+		}
+
+		// use PATCH instead of UPDATE
+		// to minimize the resource usage, compared to update the whole Node resource
+		if mutate(nodeObj.Labels) {
+			logger.Info(fmt.Sprintf("updating node-labeller labels in %v", nodeObj.Name))
+			return dcrh.client.Patch(ctx, nodeObj, client.MergeFrom(nodeObjCopy))
+		}
+		return nil
+	})
+}
+
 func (dcrh *networkConfigReconcilerHelper) validateNodeAssignments(namespacedName string, nodes *v1.NodeList) error {
 	var err error
 
@@ -1511,6 +3397,7 @@ func (dcrh *networkConfigReconcilerHelper) buildNodeAssignments(networkConfigLis
 
 func (dcrh *networkConfigReconcilerHelper) updateNodeAssignments(namespacedName string, nodes *v1.NodeList, isFinalizer bool) {
 	if isFinalizer {
+		delete(dcrh.kmodSignatureErrors, namespacedName)
 		if nodes != nil {
 			for _, node := range nodes.Items {
 				delete(dcrh.nodeAssignments, node.Name)
@@ -1538,6 +3425,9 @@ func (dcrh *networkConfigReconcilerHelper) setCondition(ctx context.Context, con
 	case conditions.ConditionTypeError:
 		dcrh.conditionUpdater.SetErrorCondition(nwConfig, status, reason, message)
 		return dcrh.updateNetworkConfigStatus(ctx, nwConfig)
+	case conditions.ConditionTypeMultusReady:
+		dcrh.conditionUpdater.SetMultusReadyCondition(nwConfig, status, reason, message)
+		return dcrh.updateNetworkConfigStatus(ctx, nwConfig)
 	}
 	return fmt.Errorf("Condition %s not supported", condition)
 }
@@ -1550,6 +3440,9 @@ func (dcrh *networkConfigReconcilerHelper) deleteCondition(ctx context.Context,
 	case conditions.ConditionTypeError:
 		dcrh.conditionUpdater.DeleteErrorCondition(nwConfig)
 		return dcrh.updateNetworkConfigStatus(ctx, nwConfig)
+	case conditions.ConditionTypeMultusReady:
+		dcrh.conditionUpdater.DeleteMultusReadyCondition(nwConfig)
+		return dcrh.updateNetworkConfigStatus(ctx, nwConfig)
 	}
 	return fmt.Errorf("Condition %s not supported", condition)
 }
@@ -34,23 +34,45 @@ package controllers
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"time"
 
 	"github.com/ROCm/common-infra-operator/pkg/metricsexporter"
 	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
 	mock_client "github.com/ROCm/network-operator/internal/client"
+	"github.com/ROCm/network-operator/internal/conditions"
+	dpinternal "github.com/ROCm/network-operator/internal/deviceplugin"
+	drainternal "github.com/ROCm/network-operator/internal/dra"
+	hostconfiginternal "github.com/ROCm/network-operator/internal/hostconfig"
 	"github.com/ROCm/network-operator/internal/kmmmodule"
+	expinternal "github.com/ROCm/network-operator/internal/metricsexporter"
+	nlinternal "github.com/ROCm/network-operator/internal/nodelabeller"
+	"github.com/ROCm/network-operator/internal/secondarynetwork"
+	"github.com/ROCm/network-operator/internal/topology"
+	netattachdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 	"go.uber.org/mock/gomock"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -130,6 +152,1961 @@ var _ = Describe("getLabelsPerModules", func() {
 	})
 })
 
+var _ = Describe("listNodes", func() {
+	var (
+		kubeClient *mock_client.MockClient
+		dcrh       networkConfigReconcilerHelperAPI
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient = mock_client.NewMockClient(ctrl)
+		dcrh = newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil)
+	})
+
+	ctx := context.Background()
+	nwConfig := &amdv1alpha1.NetworkConfig{
+		Spec: amdv1alpha1.NetworkConfigSpec{
+			Selector: map[string]string{utils.NodeFeatureLabelAmdNic: "true"},
+		},
+	}
+
+	It("good flow", func() {
+		kubeClient.EXPECT().List(ctx, gomock.Any(), client.MatchingLabels(nwConfig.Spec.Selector)).Do(
+			func(_ interface{}, nodes *v1.NodeList, _ ...client.ListOption) {
+				nodes.Items = testNodeList.Items
+			},
+		)
+
+		nodes, err := dcrh.listNodes(ctx, nwConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodes).To(Equal(testNodeList))
+	})
+
+	It("error flow", func() {
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		nodes, err := dcrh.listNodes(ctx, nwConfig)
+		Expect(err).To(HaveOccurred())
+		Expect(nodes).To(BeNil())
+	})
+})
+
+var _ = Describe("getPodsToDrainOrDelete", func() {
+	var (
+		kubeClient *mock_client.MockClient
+		h          upgradeMgrHelperAPI
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient = mock_client.NewMockClient(ctrl)
+		h = newUpgradeMgrHelperHandler(kubeClient, nil, false, nil)
+	})
+
+	ctx := context.Background()
+	nwConfig := &amdv1alpha1.NetworkConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nwConfigName,
+			Namespace: nwConfigNamespace,
+		},
+	}
+	node := &testNodeList.Items[0]
+
+	It("should only list the pods on the node from the cache index", func() {
+		kubeClient.EXPECT().List(ctx, gomock.Any(), client.MatchingFields{podNodeNameIndexKey: node.Name}).Do(
+			func(_ interface{}, pods *v1.PodList, _ ...client.ListOption) {
+				pods.Items = []v1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName + "-device-plugin-abcde"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}},
+				}
+			},
+		)
+
+		pods, err := h.getPodsToDrainOrDelete(ctx, nwConfig, node)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pods).To(HaveLen(1))
+		Expect(pods[0].Name).To(Equal(nwConfigName + "-device-plugin-abcde"))
+	})
+})
+
+var _ = Describe("getNodeNetworkReadyCondition", func() {
+	var (
+		kubeClient *mock_client.MockClient
+		dcrh       *networkConfigReconcilerHelper
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient = mock_client.NewMockClient(ctrl)
+		dcrh = newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+	})
+
+	ctx := context.Background()
+	newNode := func(capacity, allocatable int64) *v1.Node {
+		node := testNodeList.Items[0].DeepCopy()
+		node.Status.Capacity = v1.ResourceList{"amd.com/nic": *resource.NewQuantity(capacity, resource.DecimalSI)}
+		node.Status.Allocatable = v1.ResourceList{"amd.com/nic": *resource.NewQuantity(allocatable, resource.DecimalSI)}
+		return node
+	}
+
+	It("should report the unmet NIC resource requirements", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{}
+
+		condition := dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, newNode(0, 0), nil)
+		Expect(condition.Status).To(Equal(v1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(conditions.DevicePluginNotRegistered))
+
+		condition = dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, newNode(2, 1), nil)
+		Expect(condition.Status).To(Equal(v1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(conditions.NICCountMismatch))
+
+		nwConfig.Spec.NodeReadiness.ExpectedNICCount = 4
+		condition = dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, newNode(2, 2), nil)
+		Expect(condition.Status).To(Equal(v1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(conditions.NICCountMismatch))
+	})
+
+	It("should count the NIC resources named by the resource pools", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{}
+		nwConfig.Spec.DevicePlugin.DevicePluginFlags.ResourcePrefix = "example.com"
+		nwConfig.Spec.DevicePlugin.ResourcePools = []amdv1alpha1.ResourcePoolSpec{{Name: "rdma_nic"}, {Name: "pf", Prefix: "vendor.io"}}
+		node := newNode(2, 2)
+		node.Status.Capacity["example.com/rdma_nic"] = *resource.NewQuantity(4, resource.DecimalSI)
+		node.Status.Allocatable["example.com/rdma_nic"] = *resource.NewQuantity(3, resource.DecimalSI)
+		node.Status.Capacity["vendor.io/pf"] = *resource.NewQuantity(1, resource.DecimalSI)
+		node.Status.Allocatable["vendor.io/pf"] = *resource.NewQuantity(1, resource.DecimalSI)
+
+		capacity, allocatable := getNICResourceCount(nwConfig, node)
+		Expect(capacity).To(Equal(int64(5)))
+		Expect(allocatable).To(Equal(int64(4)))
+		Expect(getUnhealthyNICCount(nwConfig, node)).To(Equal(int32(1)))
+
+		// the node predicate lets the changes of the custom resources through
+		updated := node.DeepCopy()
+		updated.Status.Allocatable["example.com/rdma_nic"] = *resource.NewQuantity(4, resource.DecimalSI)
+		Expect(nodeStateChanged(node, updated)).To(BeTrue())
+		updated = node.DeepCopy()
+		updated.Status.Allocatable[v1.ResourceMemory] = resource.MustParse("1Gi")
+		Expect(nodeStateChanged(node, updated)).To(BeFalse())
+	})
+
+	It("should remove the network-ready label from the nodes which are not selected anymore", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		dcrh.nodeAssignments["other-node"] = nwConfigNamespace + "/other"
+		labelled := func(name string) v1.Node {
+			return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{utils.NetworkReadyLabelKey: "true"}}}
+		}
+
+		kubeClient.EXPECT().List(ctx, gomock.Any(), client.HasLabels{utils.NetworkReadyLabelKey}).Do(
+			func(_ interface{}, nodes *v1.NodeList, _ ...client.ListOption) {
+				nodes.Items = []v1.Node{labelled("deselected-node"), labelled("other-node")}
+			})
+		kubeClient.EXPECT().Get(ctx, client.ObjectKey{Name: "deselected-node"}, gomock.Any()).Do(
+			func(_ interface{}, _ client.ObjectKey, node *v1.Node, _ ...client.GetOption) {
+				*node = labelled("deselected-node")
+			})
+		var patched *v1.Node
+		kubeClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, node *v1.Node, _ client.Patch, _ ...client.PatchOption) {
+				patched = node.DeepCopy()
+			})
+
+		Expect(dcrh.handleNodeReadiness(ctx, nwConfig, &v1.NodeList{})).To(Succeed())
+		Expect(patched.Name).To(Equal("deselected-node"))
+		Expect(patched.Labels).ToNot(HaveKey(utils.NetworkReadyLabelKey))
+	})
+
+	It("should require the driver to be loaded at the desired version", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				Driver: amdv1alpha1.DriverSpec{Version: "1.117.1-a-63"},
+			},
+			Status: amdv1alpha1.NetworkConfigStatus{
+				NodeModuleStatus: map[string]amdv1alpha1.ModuleStatus{
+					"unit-test-node": {ContainerImage: "registry/amdainic_kmod:ubuntu-22.04-6.8.0-40-generic-1.117.1-a-42"},
+				},
+			},
+		}
+		node := newNode(2, 2)
+		profiles, err := kmmmodule.DefaultOSProfiles()
+		Expect(err).ToNot(HaveOccurred())
+
+		condition := dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, node, profiles)
+		Expect(condition.Reason).To(Equal(conditions.DriverNotReady))
+
+		node.Labels = map[string]string{fmt.Sprintf("kmm.node.kubernetes.io/%v.%v.ready", nwConfigNamespace, nwConfigName): ""}
+		condition = dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, node, profiles)
+		Expect(condition.Reason).To(Equal(conditions.DriverNotReady))
+
+		nwConfig.Spec.Driver.Version = "1.117.1-a-42"
+		condition = dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, node, profiles)
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(conditions.NetworkReady))
+	})
+
+	It("should require the CNI plugins pod to be ready when enabled", func() {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				SecondaryNetwork: amdv1alpha1.SecondaryNetworkSpec{
+					CniPlugins: &amdv1alpha1.CniPluginsSpec{Enable: &enable},
+				},
+			},
+		}
+
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(nil)
+		condition := dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, newNode(2, 2), nil)
+		Expect(condition.Reason).To(Equal(conditions.CNIPluginsNotReady))
+
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, pods *v1.PodList, _ ...client.ListOption) {
+				pods.Items = []v1.Pod{{Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}}}}
+			},
+		)
+		condition = dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, newNode(2, 2), nil)
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+	})
+})
+
+var _ = Describe("device plugin config", func() {
+	It("should render the default AMD NIC resource pools", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName}}
+		config, err := dpinternal.GenerateDevicePluginConfig(nwConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(MatchJSON(`{
+			"resourceList": [
+				{"resourceName": "nic", "resourcePrefix": "amd.com", "enableExporterHealthCheck": true, "excludeTopology": false,
+				 "selectors": {"vendors": ["1dd8"], "devices": ["1002"], "drivers": ["ionic"], "isRdma": true}},
+				{"resourceName": "vnic", "resourcePrefix": "amd.com", "enableExporterHealthCheck": true, "excludeTopology": false,
+				 "selectors": {"vendors": ["1dd8"], "devices": ["1003"], "drivers": ["ionic"], "isRdma": true}}
+			]
+		}`))
+	})
+
+	It("should render the user defined resource pools and change the hash with them", func() {
+		disabled := false
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{
+					ResourcePools: []amdv1alpha1.ResourcePoolSpec{
+						{
+							Name:                      "rdma_nic",
+							Prefix:                    "example.com",
+							ExcludeTopology:           true,
+							EnableExporterHealthCheck: &disabled,
+							Selectors: amdv1alpha1.ResourcePoolSelectors{
+								Vendors:          []string{"1dd8"},
+								SubsystemDevices: []string{"5201"},
+								PFNames:          []string{"enp1s0f0"},
+							},
+						},
+					},
+				},
+			},
+		}
+		cm := &v1.ConfigMap{}
+		hash, err := dpinternal.SetDevicePluginConfigMapAsDesired(cm, nwConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data[dpinternal.DevicePluginConfigKey]).To(MatchJSON(`{
+			"resourceList": [
+				{"resourceName": "rdma_nic", "resourcePrefix": "example.com", "enableExporterHealthCheck": false, "excludeTopology": true,
+				 "selectors": {"vendors": ["1dd8"], "subsystemDevices": ["5201"], "pfNames": ["enp1s0f0"]}}
+			]
+		}`))
+
+		nwConfig.Spec.DevicePlugin.ResourcePools[0].Selectors.IsRdma = true
+		newHash, err := dpinternal.SetDevicePluginConfigMapAsDesired(cm, nwConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newHash).ToNot(Equal(hash))
+	})
+})
+
+var _ = Describe("device plugin flags", func() {
+	It("should render the typed flags over the legacy arguments", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{
+					DevicePluginArguments: map[string]string{
+						utils.ResourceNamingStrategyFlag: utils.SingleStrategy,
+						utils.LogLevelFlag:               "info",
+					},
+					DevicePluginFlags: amdv1alpha1.DevicePluginFlagsSpec{
+						ResourceNamingStrategy:     utils.MixedStrategy,
+						HealthCheckIntervalSeconds: 30,
+						KubeletSocketPath:          "/var/lib/k0s/kubelet/device-plugins/kubelet.sock",
+					},
+				},
+			},
+		}
+		Expect(dpinternal.ValidateDevicePluginFlags(nwConfig)).To(Succeed())
+		dpOut := dpinternal.GenerateCommonDevicePluginSpec(nwConfig, false)
+		Expect(dpOut.MainContainer.Arguments).To(Equal([]string{
+			"-health_check_interval=30",
+			"-kubelet_socket=/var/lib/k0s/kubelet/device-plugins/kubelet.sock",
+			"-log_level=info",
+			"-resource_naming_strategy=mixed",
+		}))
+		Expect(dpOut.MainContainer.VolumeMounts).To(ContainElement(v1.VolumeMount{
+			Name:      "kubelet-device-plugins",
+			MountPath: "/var/lib/k0s/kubelet/device-plugins",
+		}))
+	})
+
+	It("should default the resource pool prefix to the resource prefix flag", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{
+					DevicePluginFlags: amdv1alpha1.DevicePluginFlagsSpec{ResourcePrefix: "example.com"},
+				},
+			},
+		}
+		config, err := dpinternal.GenerateDevicePluginConfig(nwConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(ContainSubstring(`"resourcePrefix": "example.com"`))
+		Expect(config).ToNot(ContainSubstring(`"resourcePrefix": "amd.com"`))
+	})
+
+	It("should reject the flags unsupported by the device plugin image", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{
+					DevicePluginArguments: map[string]string{"unknown": "value"},
+				},
+			},
+		}
+		Expect(dpinternal.ValidateDevicePluginFlags(nwConfig)).ToNot(Succeed())
+
+		nwConfig.Spec.DevicePlugin.DevicePluginArguments = map[string]string{utils.HealthCheckIntervalFlag: "0"}
+		Expect(dpinternal.ValidateDevicePluginFlags(nwConfig)).ToNot(Succeed())
+
+		nwConfig.Spec.DevicePlugin.DevicePluginArguments = nil
+		nwConfig.Spec.DevicePlugin.DevicePluginFlags.LogLevel = "debug"
+		nwConfig.Spec.DevicePlugin.DevicePluginImage = "docker.io/rocm/k8s-network-device-plugin:v1.1.0"
+		Expect(dpinternal.ValidateDevicePluginFlags(nwConfig)).ToNot(Succeed())
+
+		nwConfig.Spec.DevicePlugin.DevicePluginImage = "registry.example.com:5000/k8s-network-device-plugin:latest"
+		Expect(dpinternal.ValidateDevicePluginFlags(nwConfig)).To(Succeed())
+	})
+})
+
+var _ = Describe("NIC to GPU topology", func() {
+	It("should remove the affinity label from the nodes which are not selected anymore", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		dcrh.nodeAssignments["other-node"] = nwConfigNamespace + "/other"
+		labelled := func(name string) v1.Node {
+			return v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{utils.NICGPUAffinityLabelKey: topology.AffinityNUMA}}}
+		}
+
+		kubeClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(
+			k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, topology.GetTopologyConfigMapName(nwConfigName)))
+		kubeClient.EXPECT().List(ctx, gomock.Any(), client.HasLabels{utils.NICGPUAffinityLabelKey}).Do(
+			func(_ interface{}, nodes *v1.NodeList, _ ...client.ListOption) {
+				nodes.Items = []v1.Node{labelled("selected-node"), labelled("deselected-node"), labelled("other-node")}
+			})
+		var patched []string
+		kubeClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, node *v1.Node, _ client.Patch, _ ...client.PatchOption) {
+				Expect(node.Labels).ToNot(HaveKey(utils.NICGPUAffinityLabelKey))
+				patched = append(patched, node.Name)
+			}).Times(2)
+
+		Expect(dcrh.finalizeGPUAffinity(ctx, nwConfig, &v1.NodeList{Items: []v1.Node{labelled("selected-node")}})).To(Succeed())
+		Expect(patched).To(Equal([]string{"selected-node", "deselected-node"}))
+	})
+
+	It("should mount the topology and pass the allocation policy to the device plugin", func() {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{
+					DevicePluginImage: "docker.io/rocm/k8s-network-device-plugin:v1.3.0",
+					GPUAffinity: amdv1alpha1.GPUAffinitySpec{
+						Enable:           &enable,
+						AllocationPolicy: topology.AffinityPCIeSwitch,
+					},
+				},
+			},
+		}
+		Expect(dpinternal.ValidateDevicePluginFlags(nwConfig)).To(Succeed())
+		dpOut := dpinternal.GenerateCommonDevicePluginSpec(nwConfig, false)
+		Expect(dpOut.MainContainer.Arguments).To(ContainElement("-gpu_affinity=pcie-switch"))
+		Expect(dpOut.Volumes).To(ContainElement(HaveField("VolumeSource.ConfigMap.LocalObjectReference.Name",
+			topology.GetTopologyConfigMapName(nwConfigName))))
+
+		// the allocation policy requires a device plugin supporting it
+		nwConfig.Spec.DevicePlugin.DevicePluginImage = ""
+		Expect(dpinternal.ValidateDevicePluginFlags(nwConfig)).ToNot(Succeed())
+
+		nwConfig.Spec.DevicePlugin.GPUAffinity.AllocationPolicy = topology.AffinityNone
+		Expect(dpinternal.ValidateDevicePluginFlags(nwConfig)).To(Succeed())
+		Expect(dpinternal.GenerateCommonDevicePluginSpec(nwConfig, false).MainContainer.Arguments).To(BeEmpty())
+	})
+})
+
+var _ = Describe("node labeller label families", func() {
+	nwConfig := &amdv1alpha1.NetworkConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+		Spec: amdv1alpha1.NetworkConfigSpec{
+			DevicePlugin: amdv1alpha1.DevicePluginSpec{
+				NodeLabellerLabels: []string{"ports", "nicModel"},
+			},
+		},
+	}
+
+	It("should pass one flag per enabled label family", func() {
+		nlOut := nlinternal.GenerateCommonNodeLabellerSpec(nwConfig, false)
+		Expect(nlOut.MainContainer.Arguments).To(Equal([]string{"-nic-model", "-ports"}))
+
+		// without labels the node labeller publishes all the families without any flag
+		allFamilies := &amdv1alpha1.NetworkConfig{}
+		Expect(nlinternal.GenerateCommonNodeLabellerSpec(allFamilies, false).MainContainer.Arguments).To(BeEmpty())
+		Expect(nlinternal.GetLabelFamilies(allFamilies)).To(Equal(nlinternal.LabelFamilies))
+	})
+
+	It("should only report the labels of the disabled label families as stale", func() {
+		nodeLabels := map[string]string{
+			"amd.com/nic.model":                 "pollara-400",
+			"beta.amd.com/nic.model":            "pollara-400",
+			"amd.com/nic.port-speed":            "400G",
+			"amd.com/nic.firmware-version":      "1.110.0",
+			"beta.amd.com/nic.firmware-version": "1.110.0",
+			"amd.com/nic.vf-count.0":            "8",
+			"amd.com/nic.unknown":               "value",
+			"amd.com/gpu.vram":                  "192G",
+		}
+		Expect(nlinternal.GetStaleLabels(nwConfig, nodeLabels)).To(ConsistOf(
+			"amd.com/nic.firmware-version",
+			"beta.amd.com/nic.firmware-version",
+			"amd.com/nic.vf-count.0",
+		))
+		Expect(nlinternal.IsNodeLabellerLabel("amd.com/nic.unknown")).To(BeTrue())
+		Expect(nlinternal.IsNodeLabellerLabel("amd.com/gpu.vram")).To(BeFalse())
+	})
+
+	It("should clean up the labels of the disabled families and of the unselected nodes", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		labelledKey := nlinternal.GetLabelledNodeLabel(nwConfig)
+
+		gomock.InOrder(
+			kubeClient.EXPECT().Get(ctx, client.ObjectKey{Name: "unit-test-node"}, gomock.Any()).Do(
+				func(_ interface{}, _ interface{}, node *v1.Node, _ ...client.GetOption) {
+					node.Name = "unit-test-node"
+					node.Labels = map[string]string{
+						"amd.com/nic.model":            "pollara-400",
+						"amd.com/nic.firmware-version": "1.110.0",
+					}
+				},
+			),
+			kubeClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Do(
+				func(_ interface{}, node *v1.Node, _ client.Patch, _ ...client.PatchOption) {
+					Expect(node.Labels).To(Equal(map[string]string{
+						"amd.com/nic.model": "pollara-400",
+						labelledKey:         "",
+					}))
+				},
+			),
+			kubeClient.EXPECT().List(ctx, gomock.Any(), client.HasLabels{labelledKey}).Do(
+				func(_ interface{}, nodes *v1.NodeList, _ ...client.ListOption) {
+					nodes.Items = []v1.Node{
+						{ObjectMeta: metav1.ObjectMeta{Name: "unit-test-node"}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "unselected-node"}},
+					}
+				},
+			),
+			kubeClient.EXPECT().Get(ctx, client.ObjectKey{Name: "unselected-node"}, gomock.Any()).Do(
+				func(_ interface{}, _ interface{}, node *v1.Node, _ ...client.GetOption) {
+					node.Name = "unselected-node"
+					node.Labels = map[string]string{
+						"amd.com/nic.model":      "pollara-400",
+						"beta.amd.com/nic.model": "pollara-400",
+						labelledKey:              "",
+						"kubernetes.io/os":       "linux",
+					}
+				},
+			),
+			kubeClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Do(
+				func(_ interface{}, node *v1.Node, _ client.Patch, _ ...client.PatchOption) {
+					Expect(node.Labels).To(Equal(map[string]string{"kubernetes.io/os": "linux"}))
+				},
+			),
+		)
+
+		Expect(dcrh.cleanupNodeLabellerLabels(ctx, nwConfig, testNodeList)).To(Succeed())
+	})
+
+	It("should requeue when the labelled nodes can't be listed", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+
+		gomock.InOrder(
+			kubeClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "node-labeller")),
+			kubeClient.EXPECT().List(ctx, gomock.Any(), client.HasLabels{nlinternal.GetLabelledNodeLabel(nwConfig)}).
+				Return(k8serrors.NewServiceUnavailable("unavailable")),
+		)
+
+		Expect(dcrh.handleNodeLabeller(ctx, nwConfig, testNodeList, false)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("node labeller spec", func() {
+	enable, disable := true, false
+	secret := &v1.LocalObjectReference{Name: "device-plugin-secret"}
+
+	It("should fall back to the deprecated device plugin fields", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{
+					EnableNodeLabeller:  &enable,
+					NodeLabellerImage:   "docker.io/rocm/k8s-network-node-labeller:v1.1.0",
+					ImageRegistrySecret: secret,
+					NodeLabellerLabels:  []string{"rail"},
+				},
+				Selector: map[string]string{"feature.node.kubernetes.io/amd-nic": "true"},
+			},
+		}
+		Expect(nlinternal.IsNodeLabellerEnabled(nwConfig)).To(BeTrue())
+
+		nlOut := nlinternal.GenerateCommonNodeLabellerSpec(nwConfig, false)
+		Expect(nlOut.MainContainer.Image).To(Equal("docker.io/rocm/k8s-network-node-labeller:v1.1.0"))
+		Expect(nlOut.MainContainer.ImageRegistrySecret).To(Equal(secret))
+		Expect(nlOut.MainContainer.Arguments).To(Equal([]string{"-rail"}))
+		Expect(nlOut.Selector).To(Equal(nwConfig.Spec.Selector))
+	})
+
+	It("should prefer the node labeller spec over the deprecated device plugin fields", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{
+					EnableNodeLabeller:  &enable,
+					NodeLabellerImage:   "docker.io/rocm/k8s-network-node-labeller:v1.1.0",
+					ImageRegistrySecret: secret,
+					UpgradePolicy:       &amdv1alpha1.DaemonSetUpgradeSpec{UpgradeStrategy: "OnDelete"},
+				},
+				NodeLabeller: amdv1alpha1.NodeLabellerSpec{
+					Image:               "docker.io/rocm/k8s-network-node-labeller:v1.2.0",
+					ImageRegistrySecret: &v1.LocalObjectReference{Name: "node-labeller-secret"},
+					Selector:            map[string]string{"node-labeller": "true"},
+					UpgradePolicy:       &amdv1alpha1.DaemonSetUpgradeSpec{UpgradeStrategy: "RollingUpdate", MaxUnavailable: 2},
+					Labels:              []string{"vfCount"},
+				},
+				Selector: map[string]string{"feature.node.kubernetes.io/amd-nic": "true"},
+			},
+		}
+		nlOut := nlinternal.GenerateCommonNodeLabellerSpec(nwConfig, false)
+		Expect(nlOut.MainContainer.Image).To(Equal("docker.io/rocm/k8s-network-node-labeller:v1.2.0"))
+		Expect(nlOut.MainContainer.ImageRegistrySecret.Name).To(Equal("node-labeller-secret"))
+		Expect(nlOut.Selector).To(Equal(map[string]string{"node-labeller": "true"}))
+		Expect(nlOut.UpgradePolicy.UpgradeStrategy).To(Equal("RollingUpdate"))
+		Expect(nlOut.MainContainer.Arguments).To(Equal([]string{"-vf-count"}))
+
+		nwConfig.Spec.NodeLabeller.Enable = &disable
+		Expect(nlinternal.IsNodeLabellerEnabled(nwConfig)).To(BeFalse())
+	})
+
+	It("should reconcile the NetworkConfig for the nodes selected by the node labeller", func() {
+		nwConfig := amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				NodeLabeller: amdv1alpha1.NodeLabellerSpec{
+					Selector: map[string]string{"node-labeller": "true"},
+				},
+				Selector: map[string]string{"feature.node.kubernetes.io/amd-nic": "true"},
+			},
+		}
+		Expect(getNetworkConfigSelectors(&nwConfig)).To(Equal([]map[string]string{
+			{"feature.node.kubernetes.io/amd-nic": "true"},
+			{"node-labeller": "true"},
+		}))
+
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		// the index lookups return the NetworkConfig, the selectors decide
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, list *amdv1alpha1.NetworkConfigList, _ ...client.ListOption) {
+				list.Items = []amdv1alpha1.NetworkConfig{nwConfig}
+			}).AnyTimes()
+
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "labeller-node", Labels: map[string]string{"node-labeller": "true"}}}
+		Expect(dcrh.findNetworkConfigsForNode(ctx, node)).To(Equal([]reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: nwConfigNamespace, Name: nwConfigName}},
+		}))
+		node.Labels = map[string]string{"other": "true"}
+		Expect(dcrh.findNetworkConfigsForNode(ctx, node)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("DRA driver", func() {
+	It("should render the DRA driver and its config", func() {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DRADriver: amdv1alpha1.DRADriverSpec{
+					Enable:     &enable,
+					Attributes: []string{"numaNode", "rail"},
+					Rails: []amdv1alpha1.RailSpec{
+						{Name: "rail0", PFNames: []string{"enp1s0f0"}},
+					},
+				},
+				Selector: map[string]string{"feature.node.kubernetes.io/amd-nic": "true"},
+			},
+		}
+		cm := &v1.ConfigMap{}
+		hash, err := drainternal.SetDRADriverConfigMapAsDesired(cm, nwConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data[drainternal.DRADriverConfigKey]).To(MatchJSON(`{
+			"driverName": "nic.amd.com",
+			"attributes": ["numaNode", "rail"],
+			"rails": [{"name": "rail0", "pfNames": ["enp1s0f0"]}]
+		}`))
+
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: drainternal.GetDRADriverName(nwConfig)}}
+		Expect(drainternal.SetDRADriverAsDesired(ds, nwConfig, hash)).To(Succeed())
+		Expect(ds.Spec.Template.Annotations[drainternal.DRADriverConfigHashAnnotation]).To(Equal(hash))
+		Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(nwConfig.Spec.Selector))
+		Expect(ds.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--driver-name=nic.amd.com"))
+	})
+
+	It("should restrict the DeviceClasses to the AMD NIC DRA driver", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		classes := drainternal.GetDeviceClasses(nwConfig)
+		Expect(classes).To(HaveLen(2))
+
+		dc := &resourcev1beta1.DeviceClass{}
+		drainternal.SetDeviceClassAsDesired(dc, classes[0], nwConfig)
+		Expect(dc.Labels).To(Equal(utils.GetOwnerLabels(nwConfig)))
+		Expect(dc.Spec.Selectors).To(HaveLen(2))
+		Expect(dc.Spec.Selectors[0].CEL.Expression).To(Equal(`device.driver == "nic.amd.com"`))
+		Expect(dc.Spec.Selectors[1].CEL.Expression).To(Equal(`device.attributes["nic.amd.com"].type == "pf"`))
+		Expect(isOwnedByNetworkConfig(dc.Labels, nwConfig)).To(BeTrue())
+		Expect(isOwnedByNetworkConfig(map[string]string{utils.CRNameLabel: nwConfigName}, nwConfig)).To(BeFalse())
+	})
+
+	It("should report the node ready without the device plugin once the DRA driver runs", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		enable, disable := true, false
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{EnableDevicePlugin: &disable},
+				DRADriver:    amdv1alpha1.DRADriverSpec{Enable: &enable},
+			},
+		}
+
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(nil)
+		condition := dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, testNodeList.Items[0].DeepCopy(), nil)
+		Expect(condition.Reason).To(Equal(conditions.DRADriverNotReady))
+
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, pods *v1.PodList, _ ...client.ListOption) {
+				pods.Items = []v1.Pod{{Status: v1.PodStatus{Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}}}}
+			},
+		)
+		condition = dcrh.getNodeNetworkReadyCondition(ctx, nwConfig, testNodeList.Items[0].DeepCopy(), nil)
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+	})
+
+	It("should build the status without the device plugin DaemonSet", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		upgradeMgr := NewMockupgradeMgrAPI(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, upgradeMgr, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		enable, disable := true, false
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{EnableDevicePlugin: &disable},
+				DRADriver:    amdv1alpha1.DRADriverSpec{Enable: &enable},
+			},
+			Status: amdv1alpha1.NetworkConfigStatus{
+				DevicePlugin: amdv1alpha1.DeploymentStatus{DesiredNumber: 1, AvailableNumber: 1},
+			},
+		}
+
+		// only the NodeModulesConfig of the node is fetched, not the deleted device plugin DaemonSet
+		upgradeMgr.EXPECT().GetNodeUpgradeStartTime("unit-test-node").Return("")
+		upgradeMgr.EXPECT().GetNodeBootId("unit-test-node").Return("")
+		upgradeMgr.EXPECT().GetNodeStatus("unit-test-node").Return(amdv1alpha1.UpgradeStateEmpty)
+		kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: "unit-test-node"}, gomock.Any()).
+			Return(k8serrors.NewNotFound(schema.GroupResource{}, "unit-test-node"))
+
+		Expect(dcrh.buildNetworkConfigStatus(ctx, nwConfig, testNodeList)).To(Succeed())
+		Expect(nwConfig.Status.DevicePlugin).To(Equal(amdv1alpha1.DeploymentStatus{}))
+		Expect(meta.IsStatusConditionTrue(nwConfig.Status.Conditions, conditions.ConditionTypeReady)).To(BeTrue())
+	})
+
+	It("should delete the ResourceSlices of the nodes which are not selected anymore", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		dcrh.nodeAssignments["other-config-node"] = "other/config"
+
+		newSlice := func(name, driver, node string) resourcev1beta1.ResourceSlice {
+			return resourcev1beta1.ResourceSlice{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       resourcev1beta1.ResourceSliceSpec{Driver: driver, NodeName: node},
+			}
+		}
+		kubeClient.EXPECT().List(ctx, gomock.Any()).Do(
+			func(_ interface{}, list *resourcev1beta1.ResourceSliceList, _ ...client.ListOption) {
+				list.Items = []resourcev1beta1.ResourceSlice{
+					newSlice("selected", drainternal.DriverName, "unit-test-node"),
+					newSlice("deselected", drainternal.DriverName, "deselected-node"),
+					newSlice("other-config", drainternal.DriverName, "other-config-node"),
+					newSlice("other-driver", "gpu.amd.com", "deselected-node"),
+				}
+			})
+		kubeClient.EXPECT().Delete(ctx, gomock.Any()).Do(
+			func(_ interface{}, slice *resourcev1beta1.ResourceSlice, _ ...client.DeleteOption) {
+				Expect(slice.Name).To(Equal("deselected"))
+			})
+
+		Expect(dcrh.deleteResourceSlices(ctx, nwConfig, map[string]bool{"unit-test-node": true})).To(Succeed())
+	})
+})
+
+var _ = Describe("host config", func() {
+	It("should render the modprobe options, blacklist and sysctls", func() {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				Driver: amdv1alpha1.DriverSpec{Blacklist: &enable},
+				HostConfig: amdv1alpha1.HostConfigSpec{
+					Enable: &enable,
+					ModprobeOptions: []amdv1alpha1.ModprobeOptionsSpec{
+						{Module: "ionic_rdma", Options: []string{"xxx_pfc=1", "yyy=0"}},
+					},
+					Blacklist: []string{"pds_core", "ionic"},
+					Sysctls: map[string]string{
+						"net.ipv4.conf.all.arp_ignore":   "2",
+						"net.ipv4.conf.all.arp_announce": "2",
+					},
+				},
+			},
+		}
+		Expect(hostconfiginternal.GetBlacklist(nwConfig)).To(Equal([]string{"ionic", "pds_core"}))
+
+		cm := &v1.ConfigMap{}
+		hash := hostconfiginternal.SetHostConfigMapAsDesired(cm, nwConfig)
+		Expect(hash).To(Equal(hostconfiginternal.GetHostConfigHash(nwConfig)))
+		Expect(cm.Data["modprobe.conf"]).To(HaveSuffix("options ionic_rdma xxx_pfc=1 yyy=0\nblacklist ionic\nblacklist pds_core\n"))
+		Expect(cm.Data["sysctl.conf"]).To(HaveSuffix("net.ipv4.conf.all.arp_announce = 2\nnet.ipv4.conf.all.arp_ignore = 2\n"))
+
+		// an empty host config removes the files from the nodes
+		nwConfig.Spec.Driver.Blacklist = nil
+		nwConfig.Spec.HostConfig = amdv1alpha1.HostConfigSpec{Enable: &enable}
+		hostconfiginternal.SetHostConfigMapAsDesired(cm, nwConfig)
+		Expect(cm.Data["modprobe.conf"]).To(BeEmpty())
+		Expect(cm.Data["sysctl.conf"]).To(BeEmpty())
+	})
+
+	It("should roll the host config agent on config changes", func() {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				HostConfig: amdv1alpha1.HostConfigSpec{Enable: &enable, Image: "example.com/utils:test"},
+				Selector:   map[string]string{"feature.node.kubernetes.io/amd-nic": "true"},
+			},
+		}
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: hostconfiginternal.GetHostConfigName(nwConfig)}}
+		Expect(hostconfiginternal.SetHostConfigAgentAsDesired(ds, nwConfig, "hash")).To(Succeed())
+		Expect(ds.Spec.Template.Annotations[hostconfiginternal.HostConfigHashAnnotation]).To(Equal("hash"))
+		Expect(ds.Spec.Template.Spec.HostNetwork).To(BeTrue())
+		Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(nwConfig.Spec.Selector))
+		Expect(ds.Spec.Template.Spec.Containers[0].Image).To(Equal("example.com/utils:test"))
+		Expect(ds.Spec.Template.Spec.Volumes[1].ConfigMap.Name).To(Equal(hostconfiginternal.GetHostConfigName(nwConfig)))
+	})
+
+	It("should render the MachineConfig and report the node sync on OpenShift", func() {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				HostConfig: amdv1alpha1.HostConfigSpec{Enable: &enable, Blacklist: []string{"ionic"}},
+			},
+		}
+		mcName := hostconfiginternal.GetMachineConfigName(nwConfig)
+		Expect(mcName).To(Equal(fmt.Sprintf("99-worker-amd-network-%s-%s", nwConfigNamespace, nwConfigName)))
+
+		mc := &unstructured.Unstructured{}
+		Expect(hostconfiginternal.SetMachineConfigAsDesired(mc, nwConfig)).To(Succeed())
+		Expect(mc.GetLabels()).To(HaveKeyWithValue("machineconfiguration.openshift.io/role", "worker"))
+		Expect(isOwnedByNetworkConfig(mc.GetLabels(), nwConfig)).To(BeTrue())
+		files, _, _ := unstructured.NestedSlice(mc.Object, "spec", "config", "storage", "files")
+		Expect(files).To(HaveLen(1))
+		Expect(files[0]).To(HaveKeyWithValue("path", hostconfiginternal.ModprobeConfigPath))
+
+		mcp := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"configuration": map[string]interface{}{
+					"name":   "rendered-worker-1",
+					"source": []interface{}{map[string]interface{}{"name": mcName}},
+				},
+			},
+		}}
+		node := testNodeList.Items[0].DeepCopy()
+		node.Annotations = map[string]string{
+			"machineconfiguration.openshift.io/currentConfig": "rendered-worker-0",
+			"machineconfiguration.openshift.io/state":         "Working",
+		}
+		inSync, _ := hostconfiginternal.GetNodeMachineConfigStatus(node, mcp, mcName)
+		Expect(inSync).To(BeFalse())
+
+		node.Annotations["machineconfiguration.openshift.io/currentConfig"] = "rendered-worker-1"
+		node.Annotations["machineconfiguration.openshift.io/state"] = "Done"
+		inSync, _ = hostconfiginternal.GetNodeMachineConfigStatus(node, mcp, mcName)
+		Expect(inSync).To(BeTrue())
+	})
+
+	It("should report the node not ready until the host config is in sync", func() {
+		dcrh := newNetworkConfigReconcilerHelper(nil, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		enable, disable := true, false
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				DevicePlugin: amdv1alpha1.DevicePluginSpec{EnableDevicePlugin: &disable},
+				HostConfig:   amdv1alpha1.HostConfigSpec{Enable: &enable},
+			},
+		}
+		node := testNodeList.Items[0].DeepCopy()
+		nwConfig.Status.NodeHostConfigStatus = map[string]amdv1alpha1.HostConfigStatus{
+			node.Name: {Message: "host config agent is applying the host config"},
+		}
+		condition := dcrh.getNodeNetworkReadyCondition(context.Background(), nwConfig, node, nil)
+		Expect(condition.Reason).To(Equal(conditions.HostConfigNotInSync))
+
+		nwConfig.Status.NodeHostConfigStatus[node.Name] = amdv1alpha1.HostConfigStatus{InSync: true}
+		condition = dcrh.getNodeNetworkReadyCondition(context.Background(), nwConfig, node, nil)
+		Expect(condition.Status).To(Equal(v1.ConditionTrue))
+	})
+})
+
+var _ = Describe("CNI plugins", func() {
+	It("should install the listed CNI plugins by the install container", func() {
+		enable := true
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName + "-" + secondarynetwork.CNIPluginsName}}
+		sn := secondarynetwork.NewSecondaryNetwork(nil, false)
+		_, err := sn.SetCNIPluginsAsDesired(nwConfigName, ds, &amdv1alpha1.CniPluginsSpec{Enable: &enable}, nil, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ds.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		Expect(ds.Spec.Template.Spec.InitContainers[0].Name).To(Equal(secondarynetwork.CNIPluginsInstallContainer))
+		Expect(ds.Spec.Template.Spec.InitContainers[0].Env).To(ConsistOf(
+			v1.EnvVar{Name: "CNI_PLUGINS", Value: "amd-host-device host-device sbr tuning"},
+			v1.EnvVar{Name: "OVERWRITE_FOREIGN_BINARIES", Value: "false"},
+		))
+
+		_, err = sn.SetCNIPluginsAsDesired(nwConfigName, ds, &amdv1alpha1.CniPluginsSpec{
+			Enable:                   &enable,
+			Plugins:                  []string{"amd-host-device", "rdma"},
+			OverwriteForeignBinaries: &enable,
+		}, nil, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ds.Spec.Template.Spec.InitContainers[0].Env).To(ConsistOf(
+			v1.EnvVar{Name: "CNI_PLUGINS", Value: "amd-host-device rdma"},
+			v1.EnvVar{Name: "OVERWRITE_FOREIGN_BINARIES", Value: "true"},
+		))
+	})
+
+	It("should publish the install status of each node", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, pods *v1.PodList, _ ...client.ListOption) {
+				pods.Items = []v1.Pod{
+					{
+						Spec: v1.PodSpec{NodeName: "node1"},
+						Status: v1.PodStatus{
+							Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+							InitContainerStatuses: []v1.ContainerStatus{{
+								Name:  secondarynetwork.CNIPluginsInstallContainer,
+								State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Message: "amd-host-device=installed sbr=foreign \n"}},
+							}},
+						},
+					},
+					{
+						Spec: v1.PodSpec{NodeName: "node2"},
+						Status: v1.PodStatus{
+							InitContainerStatuses: []v1.ContainerStatus{{
+								Name:                 secondarynetwork.CNIPluginsInstallContainer,
+								LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Message: "amd-host-device=installed rdma=missing \n"}},
+							}},
+						},
+					},
+				}
+			},
+		)
+		status, err := dcrh.getCNIPluginsStatus(ctx, nwConfig, nwConfigName+"-"+secondarynetwork.CNIPluginsName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(map[string]amdv1alpha1.CNIPluginsStatus{
+			"node1": {Ready: true, Plugins: map[string]string{"amd-host-device": "installed", "sbr": "foreign"}},
+			"node2": {Plugins: map[string]string{"amd-host-device": "installed", "rdma": "missing"}},
+		}))
+	})
+})
+
+var _ = Describe("Multus", func() {
+	It("should render the Multus daemon config and DaemonSet", func() {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.SecondaryNetwork.Multus = &amdv1alpha1.MultusSpec{
+			Enable:           &enable,
+			KubeconfigSecret: &v1.LocalObjectReference{Name: "multus-kubeconfig"},
+		}
+
+		cm := &v1.ConfigMap{}
+		hash, err := secondarynetwork.SetMultusConfigMapAsDesired(cm, nwConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hash).ToNot(BeEmpty())
+		Expect(cm.Data["daemon-config.json"]).To(ContainSubstring(`"kubeconfig": "/etc/multus/kubeconfig/kubeconfig"`))
+		Expect(cm.Data["daemon-config.json"]).To(ContainSubstring(`"multusConfigFile": "auto"`))
+
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: secondarynetwork.GetMultusName(nwConfig)}}
+		Expect(secondarynetwork.SetMultusAsDesired(ds, nwConfig, hash)).To(Succeed())
+		Expect(ds.Spec.Template.Annotations).To(HaveKeyWithValue(secondarynetwork.MultusConfigHashAnnotation, hash))
+		Expect(ds.Spec.Template.Spec.HostNetwork).To(BeTrue())
+		Expect(ds.Spec.Template.Spec.InitContainers[0].Command).To(Equal([]string{"/install_multus", "--type", "thick"}))
+		Expect(ds.Spec.Template.Spec.Containers[0].Image).To(ContainSubstring("multus-cni"))
+		Expect(ds.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("Name", "kubeconfig")))
+
+		nwConfig.Spec.SecondaryNetwork.Multus.KubeconfigSecret = nil
+		newHash, err := secondarynetwork.SetMultusConfigMapAsDesired(cm, nwConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newHash).ToNot(Equal(hash))
+	})
+
+	It("should not deploy Multus on OpenShift", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.SecondaryNetwork.Multus = &amdv1alpha1.MultusSpec{Enable: &enable}
+
+		kubeClient.EXPECT().Delete(ctx, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "")).Times(2)
+		Expect(dcrh.handleMultus(ctx, nwConfig, true)).To(Succeed())
+		Expect(nwConfig.Status.Conditions).To(ContainElement(And(
+			HaveField("Type", conditions.ConditionTypeMultusReady),
+			HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", conditions.MultusProvidedByPlatform),
+		)))
+
+		// disabling Multus removes the condition
+		nwConfig.Spec.SecondaryNetwork.Multus = nil
+		kubeClient.EXPECT().Delete(ctx, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "")).Times(2)
+		Expect(dcrh.handleMultus(ctx, nwConfig, false)).To(Succeed())
+		Expect(nwConfig.Status.Conditions).To(BeEmpty())
+	})
+
+	It("should time out the device plugin wait for the Multus CNI config", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		Expect(dpinternal.GetMultusWaitTimeoutSeconds(nwConfig)).To(Equal(int32(dpinternal.DefaultMultusWaitTimeoutSeconds)))
+		command := dpinternal.GenerateCommonDevicePluginSpec(nwConfig, false).InitContainers[0].Command
+		Expect(command[2]).To(ContainSubstring("Multus CNI config not found after 600s"))
+		Expect(command[2]).To(ContainSubstring("/dev/termination-log"))
+
+		timeout := int32(0)
+		nwConfig.Spec.SecondaryNetwork.Multus = &amdv1alpha1.MultusSpec{WaitTimeoutSeconds: &timeout}
+		command = dpinternal.GenerateCommonDevicePluginSpec(nwConfig, false).InitContainers[0].Command
+		Expect(command[2]).To(ContainSubstring("[ 0 -gt 0 ]"))
+	})
+})
+
+var _ = Describe("network attachments", func() {
+	It("should render the CNI chain and the IPAM", func() {
+		mtu := int32(9000)
+		config, err := secondarynetwork.GenerateNetworkAttachmentConfig(&amdv1alpha1.NetworkConfig{}, amdv1alpha1.NetworkAttachmentSpec{
+			Name:    "rdma-net",
+			Plugins: []string{"tuning", "sbr", "rdma"},
+			Tuning:  &amdv1alpha1.NetworkAttachmentTuningSpec{MTU: &mtu},
+			IPAM: amdv1alpha1.NetworkAttachmentIPAMSpec{
+				Mode:    "host-local",
+				Range:   "192.168.10.0/24",
+				Gateway: "192.168.10.1",
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(MatchJSON(`{
+			"cniVersion": "0.3.1",
+			"name": "rdma-net",
+			"plugins": [
+				{"type": "amd-host-device", "ipam": {"type": "host-local", "ranges": [[{"subnet": "192.168.10.0/24", "gateway": "192.168.10.1"}]]}},
+				{"type": "tuning", "mtu": 9000},
+				{"type": "sbr"},
+				{"type": "rdma"}
+			]
+		}`))
+
+		config, err = secondarynetwork.GenerateNetworkAttachmentConfig(&amdv1alpha1.NetworkConfig{}, amdv1alpha1.NetworkAttachmentSpec{
+			Name: "static-net",
+			IPAM: amdv1alpha1.NetworkAttachmentIPAMSpec{Mode: "static"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(MatchJSON(`{
+			"cniVersion": "0.3.1",
+			"name": "static-net",
+			"plugins": [{"type": "amd-host-device", "capabilities": {"ips": true}, "ipam": {"type": "static"}}]
+		}`))
+	})
+
+	It("should label the NetworkAttachmentDefinitions with the NetworkConfig and request the NIC resource", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nad := &netattachdefv1.NetworkAttachmentDefinition{}
+		Expect(secondarynetwork.SetNetworkAttachmentAsDesired(nad, amdv1alpha1.NetworkAttachmentSpec{Name: "nic-net"}, nwConfig)).To(Succeed())
+		Expect(isOwnedByNetworkConfig(nad.Labels, nwConfig)).To(BeTrue())
+		Expect(nad.Annotations).To(HaveKeyWithValue(secondarynetwork.NetworkAttachmentResourceNameAnnotation, "amd.com/nic"))
+
+		Expect(secondarynetwork.SetNetworkAttachmentAsDesired(nad, amdv1alpha1.NetworkAttachmentSpec{Name: "nic-net", ResourceName: "amd.com/vnic"}, nwConfig)).To(Succeed())
+		Expect(nad.Annotations).To(HaveKeyWithValue(secondarynetwork.NetworkAttachmentResourceNameAnnotation, "amd.com/vnic"))
+	})
+
+	It("should delete the NetworkAttachmentDefinitions which are not desired anymore", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, nads *netattachdefv1.NetworkAttachmentDefinitionList, _ ...client.ListOption) {
+				nads.Items = []netattachdefv1.NetworkAttachmentDefinition{
+					{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "nic-net"}},
+					{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "nic-net"}},
+				}
+			},
+		)
+		kubeClient.EXPECT().Delete(ctx, gomock.Any()).Do(
+			func(_ interface{}, nad *netattachdefv1.NetworkAttachmentDefinition, _ ...client.DeleteOption) {
+				Expect(nad.Namespace).To(Equal("team-b"))
+			},
+		).Return(nil)
+		Expect(dcrh.deleteNetworkAttachments(ctx, nwConfig, map[types.NamespacedName]bool{
+			{Namespace: "team-a", Name: "nic-net"}: true,
+		})).To(Succeed())
+	})
+
+	It("should reconcile the NetworkConfigs with network attachments in a created namespace", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+
+		kubeClient.EXPECT().List(ctx, gomock.Any()).Do(
+			func(_ interface{}, list *amdv1alpha1.NetworkConfigList, _ ...client.ListOption) {
+				list.Items = []amdv1alpha1.NetworkConfig{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace},
+						Spec: amdv1alpha1.NetworkConfigSpec{
+							SecondaryNetwork: amdv1alpha1.SecondaryNetworkSpec{
+								NetworkAttachments: []amdv1alpha1.NetworkAttachmentSpec{
+									{Name: "nic-net", Namespaces: []string{"team-a", "team-b"}},
+								},
+							},
+						},
+					},
+					{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: nwConfigNamespace}},
+				}
+			},
+		).Times(2)
+
+		ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}}
+		Expect(dcrh.findNetworkConfigsForNamespace(ctx, ns)).To(Equal([]reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: nwConfigNamespace, Name: nwConfigName}},
+		}))
+		ns.Name = "team-c"
+		Expect(dcrh.findNetworkConfigsForNamespace(ctx, ns)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("cluster IPAM", func() {
+	It("should render the pools of the rails", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: "nwconfig", Namespace: nwConfigNamespace}}
+		nwConfig.Spec.SecondaryNetwork.NetworkAttachments = []amdv1alpha1.NetworkAttachmentSpec{{
+			Name: "rdma-net",
+			IPAM: amdv1alpha1.NetworkAttachmentIPAMSpec{
+				Mode:  "cluster",
+				Range: "10.0.0.0/16",
+				Rails: []amdv1alpha1.NetworkAttachmentRailSpec{
+					{Interface: "benic1p1", Range: "10.1.0.0/16", Gateway: "10.1.0.1"},
+				},
+			},
+		}}
+		Expect(secondarynetwork.UsesClusterIPAM(nwConfig)).To(BeTrue())
+		Expect(secondarynetwork.GetDesiredIPPools(nwConfig)).To(Equal(map[string]amdv1alpha1.NICIPPoolSpec{
+			"nwconfig-rdma-net":          {Range: "10.0.0.0/16"},
+			"nwconfig-rdma-net-benic1p1": {Range: "10.1.0.0/16", Gateway: "10.1.0.1"},
+		}))
+
+		config, err := secondarynetwork.GenerateNetworkAttachmentConfig(nwConfig, nwConfig.Spec.SecondaryNetwork.NetworkAttachments[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(MatchJSON(fmt.Sprintf(`{
+			"cniVersion": "0.3.1",
+			"name": "rdma-net",
+			"plugins": [{"type": "amd-host-device", "clusterIPAM": {
+				"namespace": %q,
+				"kubeconfig": %q,
+				"pools": {"*": "nwconfig-rdma-net", "benic1p1": "nwconfig-rdma-net-benic1p1"}
+			}}]
+		}`, nwConfigNamespace, secondarynetwork.ClusterIPAMKubeconfigPath)))
+	})
+
+	It("should release the leases outside of the range and of the pods which are gone or recreated", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+
+		pool := &amdv1alpha1.NICIPPool{Spec: amdv1alpha1.NICIPPoolSpec{
+			Allocations: map[string]amdv1alpha1.NICIPAllocation{
+				"10.0.0.2":   {PodRef: "team-a/running", PodUID: "running-uid", Node: "node1"},
+				"10.0.0.3":   {PodRef: "team-a/deleted", Node: "node1"},
+				"10.0.0.4":   {Node: "node1", HostReserved: true},
+				"10.0.0.5":   {PodRef: "team-a/recreated", PodUID: "old-uid", Node: "node1"},
+				"10.0.1.200": {PodRef: "team-a/running", Node: "node1"},
+			},
+		}}
+		Expect(secondarynetwork.SetIPPoolAsDesired(pool, amdv1alpha1.NICIPPoolSpec{Range: "10.0.0.0/16", RangeEnd: "10.0.0.255"}, nwConfig)).To(Succeed())
+		Expect(pool.Spec.Allocations).ToNot(HaveKey("10.0.1.200"))
+
+		kubeClient.EXPECT().Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "running"}, gomock.Any()).Do(
+			func(_ interface{}, _ types.NamespacedName, pod *v1.Pod, _ ...client.GetOption) {
+				pod.UID = "running-uid"
+				pod.Spec.NodeName = "node1"
+				pod.Status.Phase = v1.PodRunning
+			},
+		).Return(nil)
+		kubeClient.EXPECT().Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "recreated"}, gomock.Any()).Do(
+			func(_ interface{}, _ types.NamespacedName, pod *v1.Pod, _ ...client.GetOption) {
+				pod.UID = "new-uid"
+				pod.Spec.NodeName = "node1"
+				pod.Status.Phase = v1.PodRunning
+			},
+		).Return(nil)
+		kubeClient.EXPECT().Get(ctx, types.NamespacedName{Namespace: "team-a", Name: "deleted"}, gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "deleted"))
+		kubeClient.EXPECT().Get(ctx, types.NamespacedName{Name: "node1"}, gomock.Any()).Return(nil)
+		staleAllocations := dcrh.getStaleIPPoolAllocations(ctx, pool)
+		Expect(staleAllocations).To(HaveLen(2))
+		Expect(staleAllocations).To(HaveKey("10.0.0.3"))
+		Expect(staleAllocations).To(HaveKey("10.0.0.5"))
+	})
+
+	It("should retry the update of a pool updated concurrently by the CNI plugin", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		poolScheme := runtime.NewScheme()
+		utilruntime.Must(amdv1alpha1.AddToScheme(poolScheme))
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.SecondaryNetwork.NetworkAttachments = []amdv1alpha1.NetworkAttachmentSpec{
+			{Name: "nic-net", Namespaces: []string{"team-a"}, IPAM: amdv1alpha1.NetworkAttachmentIPAMSpec{Mode: "cluster", Range: "10.0.0.0/24"}},
+		}
+		existing := &amdv1alpha1.NICIPPool{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nwConfigNamespace, Name: secondarynetwork.GetIPPoolName(nwConfig, "nic-net", "")},
+			Spec: amdv1alpha1.NICIPPoolSpec{
+				Range: "10.0.0.0/24",
+				Allocations: map[string]amdv1alpha1.NICIPAllocation{
+					"10.0.0.3": {PodRef: "team-a/deleted", Node: "node1"},
+				},
+			},
+		}
+
+		kubeClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+				if pool, ok := obj.(*amdv1alpha1.NICIPPool); ok {
+					existing.DeepCopyInto(pool)
+					return nil
+				}
+				return k8serrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "deleted")
+			}).AnyTimes()
+		kubeClient.EXPECT().Scheme().Return(poolScheme).AnyTimes()
+		var updated *amdv1alpha1.NICIPPool
+		gomock.InOrder(
+			kubeClient.EXPECT().Update(ctx, gomock.Any()).Return(
+				k8serrors.NewConflict(schema.GroupResource{Resource: "nicippools"}, existing.Name, fmt.Errorf("conflict"))),
+			kubeClient.EXPECT().Update(ctx, gomock.Any()).Do(
+				func(_ context.Context, pool *amdv1alpha1.NICIPPool, _ ...client.UpdateOption) {
+					updated = pool.DeepCopy()
+				}).Return(nil),
+		)
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		Expect(dcrh.handleIPPools(ctx, nwConfig)).To(Succeed())
+		Expect(updated).ToNot(BeNil())
+		Expect(updated.Spec.Allocations).ToNot(HaveKey("10.0.0.3"))
+	})
+})
+
+var _ = Describe("metrics exporter options", func() {
+	It("should render the options into the exporter arguments, environment and ports", func() {
+		enable := true
+		disable := false
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.MetricsExporter = amdv1alpha1.MetricsExporterSpec{
+			Enable:      &enable,
+			HostNetwork: &enable,
+			Port:        9500,
+			Config:      amdv1alpha1.MetricsConfig{Name: "exporter-config"},
+			Options: &amdv1alpha1.MetricsExporterOptions{
+				MonitorGPU: &enable,
+				ConfigKey:  "nic.json",
+				LogLevel:   "debug",
+				ExtraArgs:  []string{"-agent-grpc-port=50062"},
+			},
+		}
+		ds := &appsv1.DaemonSet{}
+		svc := &v1.Service{}
+		exporter := metricsexporter.NewMetricsExporter(nil)
+		_, err := exporter.SetMetricsExporterAsDesired(ds, expinternal.GenerateCommonExporterSpec(nwConfig))
+		Expect(err).ToNot(HaveOccurred())
+		container := ds.Spec.Template.Spec.Containers[0]
+		Expect(container.Args).To(Equal([]string{"-monitor-nic=true", "-monitor-gpu=true", "-amd-metrics-config=/etc/metrics/nic.json", "-agent-grpc-port=50062"}))
+		Expect(container.Env).To(ContainElements(
+			v1.EnvVar{Name: "METRICS_EXPORTER_PORT", Value: "9500"},
+			v1.EnvVar{Name: "LOG_LEVEL", Value: "debug"},
+		))
+		Expect(container.Ports[0].ContainerPort).To(Equal(int32(9500)))
+		_, err = exporter.SetMetricsServiceAsDesired(svc, expinternal.GenerateCommonExporterSpec(nwConfig))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(svc.Spec.Ports[0].Port).To(Equal(int32(9500)))
+		Expect(svc.Spec.Ports[0].TargetPort.IntVal).To(Equal(int32(9500)))
+
+		// the exporter moves to the internal port behind kube-rbac-proxy
+		nwConfig.Spec.MetricsExporter.RbacConfig.Enable = &enable
+		nwConfig.Spec.MetricsExporter.Options.InternalPort = 9501
+		_, err = exporter.SetMetricsExporterAsDesired(ds, expinternal.GenerateCommonExporterSpec(nwConfig))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ds.Spec.Template.Spec.Containers[0].Env).To(ContainElement(v1.EnvVar{Name: "METRICS_EXPORTER_PORT", Value: "9501"}))
+		Expect(ds.Spec.Template.Spec.Containers[1].Args).To(ContainElement("--upstream=http://127.0.0.1:9501"))
+		Expect(ds.Spec.Template.Spec.Containers[1].Ports[0].ContainerPort).To(Equal(int32(9500)))
+		Expect(expinternal.GetExporterListenPort(nwConfig)).To(Equal(int32(9501)))
+
+		nwConfig.Spec.MetricsExporter.Options.InternalPort = 0
+		nwConfig.Spec.MetricsExporter.Port = 5001
+		Expect(expinternal.GetExporterListenPort(nwConfig)).To(Equal(int32(5000)))
+		spec := expinternal.GenerateCommonExporterSpec(nwConfig)
+		Expect(spec.DsSpec.MainContainer.Envs[1]).To(Equal(v1.EnvVar{Name: "METRICS_EXPORTER_PORT", Value: "5000"}))
+
+		nwConfig.Spec.MetricsExporter.Options = nil
+		Expect(expinternal.GenerateCommonExporterSpec(nwConfig).DsSpec.MainContainer.Arguments).To(Equal([]string{"-monitor-nic=true", "-monitor-gpu=false"}))
+		nwConfig.Spec.MetricsExporter.Options = &amdv1alpha1.MetricsExporterOptions{MonitorNIC: &disable}
+		Expect(expinternal.ValidateExporterOptions(nwConfig)).To(HaveOccurred())
+	})
+
+	It("should reject options conflicting with the managed flags and ports", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.MetricsExporter.Options = &amdv1alpha1.MetricsExporterOptions{ExtraArgs: []string{"--monitor-gpu=true"}}
+		Expect(expinternal.ValidateExporterOptions(nwConfig)).To(MatchError(ContainSubstring("monitor-gpu")))
+		nwConfig.Spec.MetricsExporter.Options = &amdv1alpha1.MetricsExporterOptions{ConfigKey: "nic.json"}
+		Expect(expinternal.ValidateExporterOptions(nwConfig)).To(MatchError(ContainSubstring("config.name")))
+		nwConfig.Spec.MetricsExporter.Options = &amdv1alpha1.MetricsExporterOptions{InternalPort: 5001}
+		Expect(expinternal.ValidateExporterOptions(nwConfig)).To(HaveOccurred())
+		nwConfig.Spec.MetricsExporter.Options = &amdv1alpha1.MetricsExporterOptions{InternalPort: 5002, ExtraArgs: []string{"-agent-grpc-port=50062"}}
+		Expect(expinternal.ValidateExporterOptions(nwConfig)).To(Succeed())
+	})
+})
+
+var _ = Describe("pod customization", func() {
+	It("should merge the operand pod customization over the common one", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		Expect(utils.GetPodCustomization(nwConfig, nil)).To(Equal(&amdv1alpha1.PodCustomizationSpec{}))
+
+		nwConfig.Spec.CommonConfig.PodCustomization = &amdv1alpha1.PodCustomizationSpec{
+			PriorityClassName: "common-priority",
+			Env:               []v1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}, {Name: "LOG", Value: "info"}},
+			Annotations:       map[string]string{"team": "network"},
+		}
+		merged := utils.GetPodCustomization(nwConfig, &amdv1alpha1.PodCustomizationOverrideSpec{
+			Resources: &v1.ResourceRequirements{Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("128Mi")}},
+			Env:       []v1.EnvVar{{Name: "LOG", Value: "debug"}},
+		})
+		Expect(merged.PriorityClassName).To(Equal("common-priority"))
+		Expect(merged.Resources.Limits).To(HaveKey(v1.ResourceMemory))
+		Expect(merged.Env).To(Equal([]v1.EnvVar{{Name: "HTTP_PROXY", Value: "http://proxy:3128"}, {Name: "LOG", Value: "debug"}}))
+		Expect(merged.Annotations).To(Equal(map[string]string{"team": "network"}))
+		// the common customization is left untouched
+		Expect(nwConfig.Spec.CommonConfig.PodCustomization.Env[1].Value).To(Equal("info"))
+	})
+
+	It("should apply the pod customization to the rendered DaemonSet", func() {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.CommonConfig.PodCustomization = &amdv1alpha1.PodCustomizationSpec{
+			SecurityContext: &v1.PodSecurityContext{SeccompProfile: &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault}},
+			NodeAffinity: &v1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{
+					MatchExpressions: []v1.NodeSelectorRequirement{{Key: "rack", Operator: v1.NodeSelectorOpIn, Values: []string{"r1"}}},
+				}}},
+			},
+		}
+		nwConfig.Spec.SecondaryNetwork.CniPlugins = &amdv1alpha1.CniPluginsSpec{
+			Enable: &enable,
+			PodCustomization: &amdv1alpha1.PodCustomizationOverrideSpec{
+				Resources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("10m")},
+					Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+				},
+				PriorityClassName: "amd-network-critical",
+				Env:               []v1.EnvVar{{Name: "NO_PROXY", Value: ".svc"}},
+				Annotations:       map[string]string{"policy.example.com/exempt": "true"},
+			},
+		}
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName + "-" + secondarynetwork.CNIPluginsName}}
+		sn := secondarynetwork.NewSecondaryNetwork(nil, false)
+		_, err := sn.SetCNIPluginsAsDesired(nwConfigName, ds, nwConfig.Spec.SecondaryNetwork.CniPlugins, nil, false)
+		Expect(err).ToNot(HaveOccurred())
+		// the affinity rendered by the operator
+		ds.Spec.Template.Spec.Affinity = &v1.Affinity{NodeAffinity: &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{
+				{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "kubernetes.io/os", Operator: v1.NodeSelectorOpIn, Values: []string{"linux"}}}},
+				{MatchFields: []v1.NodeSelectorRequirement{{Key: "metadata.name", Operator: v1.NodeSelectorOpIn, Values: []string{"node1"}}}},
+			}},
+		}}
+		utils.ApplyPodCustomization(&ds.Spec.Template.ObjectMeta, &ds.Spec.Template.Spec,
+			utils.GetPodCustomization(nwConfig, nwConfig.Spec.SecondaryNetwork.CniPlugins.PodCustomization))
+
+		podSpec := ds.Spec.Template.Spec
+		Expect(podSpec.PriorityClassName).To(Equal("amd-network-critical"))
+		Expect(podSpec.SecurityContext.SeccompProfile.Type).To(Equal(v1.SeccompProfileTypeRuntimeDefault))
+		Expect(ds.Spec.Template.Annotations).To(HaveKeyWithValue("policy.example.com/exempt", "true"))
+		// a node has to match both the rendered and the custom node affinity
+		rack := v1.NodeSelectorRequirement{Key: "rack", Operator: v1.NodeSelectorOpIn, Values: []string{"r1"}}
+		terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(2))
+		Expect(terms[0].MatchExpressions).To(Equal([]v1.NodeSelectorRequirement{
+			{Key: "kubernetes.io/os", Operator: v1.NodeSelectorOpIn, Values: []string{"linux"}}, rack}))
+		Expect(terms[1].MatchExpressions).To(Equal([]v1.NodeSelectorRequirement{rack}))
+		Expect(terms[1].MatchFields).To(HaveLen(1))
+		// the resources only apply to the main container
+		Expect(podSpec.Containers[0].Resources.Limits.Cpu().String()).To(Equal("100m"))
+		for _, container := range podSpec.InitContainers {
+			Expect(container.Resources.Limits).ToNot(HaveKey(v1.ResourceCPU))
+		}
+		for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+			Expect(container.Env).To(ContainElement(v1.EnvVar{Name: "NO_PROXY", Value: ".svc"}))
+		}
+		// the operator variables are kept
+		Expect(podSpec.InitContainers[0].Env).To(ContainElement(v1.EnvVar{Name: "OVERWRITE_FOREIGN_BINARIES", Value: "false"}))
+
+		// pods bound to a node don't get the node affinity
+		pod := &v1.Pod{Spec: v1.PodSpec{NodeName: "node1", Containers: []v1.Container{{Name: "worker"}}}}
+		utils.ApplyPodCustomization(&pod.ObjectMeta, &pod.Spec, utils.GetPodCustomization(nwConfig, nwConfig.Spec.SecondaryNetwork.CniPlugins.PodCustomization))
+		Expect(pod.Spec.Affinity).To(BeNil())
+		Expect(pod.Spec.PriorityClassName).To(Equal("amd-network-critical"))
+	})
+})
+
+var _ = Describe("Prometheus rules and Grafana dashboard", func() {
+	It("should render the alert rules with the thresholds of the NetworkConfig", func() {
+		enable := true
+		disable := false
+		threshold := int32(50)
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.MetricsExporter.Prometheus = &amdv1alpha1.PrometheusConfig{
+			Rules: &amdv1alpha1.PrometheusRuleConfig{
+				Enable: &enable,
+				Labels: map[string]string{"release": "prometheus"},
+			},
+		}
+		Expect(utils.IsPrometheusRuleEnable(nwConfig)).To(BeTrue())
+
+		rule := &monitoringv1.PrometheusRule{}
+		Expect(expinternal.SetPrometheusRuleAsDesired(rule, nwConfig)).To(Succeed())
+		Expect(rule.Labels).To(HaveKeyWithValue("release", "prometheus"))
+		Expect(rule.Spec.Groups).To(HaveLen(1))
+		alerts := map[string]monitoringv1.Rule{}
+		for _, r := range rule.Spec.Groups[0].Rules {
+			alerts[r.Alert] = r
+		}
+		Expect(alerts).To(HaveLen(5))
+		Expect(alerts["AMDNICLinkDown"].Expr.StrVal).To(Equal(`sum by (hostname, port_name) (rate(nic_port_stats_frames_rx_all{port_name=~".*"}[5m])) == 0`))
+		Expect(alerts["AMDNICRDMAErrors"].Expr.StrVal).To(HaveSuffix("> 10"))
+		Expect(string(*alerts["AMDNICRDMAErrors"].For)).To(Equal("5m"))
+		Expect(alerts["AMDNICMetricsExporterDown"].Expr.StrVal).To(ContainSubstring(`job="nwConfigName-metrics-exporter"`))
+		Expect(alerts["AMDNICDriverUpgradeStuck"].Expr.StrVal).To(ContainSubstring(`pod=~"worker-nwConfigName-.*|amd-network-operator-.*-reboot-worker"`))
+		Expect(alerts["AMDNICDevicePluginUnhealthy"].Expr.StrVal).To(ContainSubstring(`resource=~"amd_com_v?nic.*"`))
+		Expect(alerts["AMDNICDevicePluginUnhealthy"].Expr.StrVal).To(ContainSubstring(`daemonset="nwConfigName-device-plugin"`))
+
+		nwConfig.Spec.MetricsExporter.Prometheus.Rules.LinkDown = &amdv1alpha1.LinkDownAlertConfig{
+			AlertRuleConfig: amdv1alpha1.AlertRuleConfig{Enable: &disable},
+		}
+		nwConfig.Spec.MetricsExporter.Prometheus.Rules.RDMAErrors = &amdv1alpha1.AlertRuleConfig{
+			For:       "10m",
+			Severity:  "critical",
+			Threshold: &threshold,
+		}
+		Expect(expinternal.SetPrometheusRuleAsDesired(rule, nwConfig)).To(Succeed())
+		Expect(rule.Spec.Groups[0].Rules).To(HaveLen(4))
+		rdma := rule.Spec.Groups[0].Rules[0]
+		Expect(rdma.Alert).To(Equal("AMDNICRDMAErrors"))
+		Expect(rdma.Expr.StrVal).To(HaveSuffix("> 50"))
+		Expect(string(*rdma.For)).To(Equal("10m"))
+		Expect(rdma.Labels).To(HaveKeyWithValue("severity", "critical"))
+	})
+
+	It("should provision the Grafana dashboard for the sidecar", func() {
+		enable := true
+		dashboardURL := "https://dashboards.example.com/ainic.json"
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.MetricsExporter.Prometheus = &amdv1alpha1.PrometheusConfig{
+			GrafanaDashboard: &amdv1alpha1.GrafanaDashboardConfig{Enable: &enable, URL: dashboardURL, Folder: "AMD"},
+		}
+		Expect(utils.IsGrafanaDashboardEnable(nwConfig)).To(BeTrue())
+
+		cm := &v1.ConfigMap{}
+		Expect(expinternal.SetGrafanaDashboardAsDesired(cm, nwConfig)).To(Succeed())
+		Expect(cm.Labels).To(HaveKeyWithValue("grafana_dashboard", "1"))
+		Expect(cm.Annotations).To(HaveKeyWithValue("grafana_folder", "AMD"))
+		// the sidecar downloads the dashboard from the URL of the keys with the .url suffix
+		Expect(expinternal.GrafanaDashboardKey).To(HaveSuffix(".json.url"))
+		Expect(cm.Data).To(Equal(map[string]string{expinternal.GrafanaDashboardKey: dashboardURL}))
+
+		nwConfig.Spec.MetricsExporter.Prometheus.GrafanaDashboard.Labels = map[string]string{"dashboards": "amd"}
+		nwConfig.Spec.MetricsExporter.Prometheus.GrafanaDashboard.Folder = ""
+		Expect(expinternal.SetGrafanaDashboardAsDesired(cm, nwConfig)).To(Succeed())
+		Expect(cm.Labels).To(HaveKeyWithValue("dashboards", "amd"))
+		Expect(cm.Labels).ToNot(HaveKey("grafana_dashboard"))
+		Expect(cm.Annotations).ToNot(HaveKey("grafana_folder"))
+	})
+
+	It("should delete the rules and the dashboard when disabled", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+
+		gomock.InOrder(
+			kubeClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&monitoringv1.PrometheusRule{})).Return(
+				&meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "monitoring.coreos.com", Kind: "PrometheusRule"}}),
+			kubeClient.EXPECT().Delete(ctx, gomock.AssignableToTypeOf(&v1.ConfigMap{})).Return(
+				k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, expinternal.GetGrafanaDashboardName(nwConfig))),
+		)
+		Expect(dcrh.handlePrometheusRule(ctx, nwConfig)).To(Succeed())
+		Expect(dcrh.handleGrafanaDashboard(ctx, nwConfig)).To(Succeed())
+	})
+})
+
+var _ = Describe("OTLP push", func() {
+	newOTLPNetworkConfig := func() *amdv1alpha1.NetworkConfig {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.MetricsExporter.Port = 5000
+		nwConfig.Spec.MetricsExporter.OTLP = &amdv1alpha1.OTLPConfig{
+			Enable:             &enable,
+			Endpoint:           "otel-collector.monitoring:4317",
+			Protocol:           "grpc",
+			HeadersSecret:      &v1.LocalObjectReference{Name: "otlp-headers"},
+			Interval:           "30s",
+			ResourceAttributes: map[string]string{"k8s.cluster.name": "cluster-a"},
+			TLS: &amdv1alpha1.OTLPTLSConfig{
+				CASecret:   &v1.LocalObjectReference{Name: "otlp-ca"},
+				CertSecret: &v1.LocalObjectReference{Name: "otlp-client"},
+			},
+		}
+		return nwConfig
+	}
+
+	It("should reconcile the NetworkConfig when an OTLP secret changes", func() {
+		nwConfig := newOTLPNetworkConfig()
+		dcrh := &networkConfigReconcilerHelper{}
+		for _, name := range []string{"otlp-headers", "otlp-ca", "otlp-client"} {
+			Expect(dcrh.hasSecretReference(name, *nwConfig)).To(BeTrue())
+		}
+		Expect(dcrh.hasSecretReference("other", *nwConfig)).To(BeFalse())
+	})
+
+	It("should render the collector sidecar pushing the exporter metrics", func() {
+		nwConfig := newOTLPNetworkConfig()
+		Expect(utils.IsOTLPEnable(nwConfig)).To(BeTrue())
+		Expect(utils.IsMetricsServiceEnable(nwConfig)).To(BeFalse())
+		headerKeys := []string{"Authorization", "X-Scope-OrgID"}
+
+		config, err := expinternal.GenerateOTLPCollectorConfig(nwConfig, headerKeys)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config).To(ContainSubstring("endpoint: otel-collector.monitoring:4317"))
+		Expect(config).To(ContainSubstring("Authorization: ${env:OTLP_HEADER_0}"))
+		Expect(config).To(ContainSubstring("X-Scope-OrgID: ${env:OTLP_HEADER_1}"))
+		Expect(config).To(ContainSubstring("ca_file: /etc/otelcol/tls/ca/ca.crt"))
+		Expect(config).To(ContainSubstring("key_file: /etc/otelcol/tls/client/tls.key"))
+		Expect(config).To(ContainSubstring("key: k8s.cluster.name"))
+		Expect(config).To(ContainSubstring("scrape_interval: 30s"))
+		Expect(config).To(ContainSubstring("127.0.0.1:5000"))
+
+		cm := &v1.ConfigMap{}
+		hash, err := expinternal.SetOTLPConfigMapAsDesired(cm, nwConfig, headerKeys)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cm.Data).To(HaveKeyWithValue(expinternal.OTLPConfigKey, config))
+
+		ds := &appsv1.DaemonSet{}
+		ds.Spec.Template.Spec.Containers = []v1.Container{{Name: "metrics-exporter-container"}}
+		expinternal.SetOTLPCollectorAsDesired(ds, nwConfig, headerKeys, hash)
+		expinternal.SetOTLPCollectorAsDesired(ds, nwConfig, headerKeys, hash)
+		Expect(ds.Spec.Template.Annotations).To(HaveKeyWithValue(expinternal.OTLPConfigHashAnnotation, hash))
+		Expect(ds.Spec.Template.Spec.Containers).To(HaveLen(2))
+		Expect(ds.Spec.Template.Spec.Volumes).To(HaveLen(3))
+		collector := ds.Spec.Template.Spec.Containers[1]
+		Expect(collector.Name).To(Equal(expinternal.OTLPCollectorName))
+		Expect(collector.Env).To(HaveLen(3))
+		Expect(collector.Env[2].Name).To(Equal("OTLP_HEADER_1"))
+		Expect(collector.Env[2].ValueFrom.SecretKeyRef.Name).To(Equal("otlp-headers"))
+		Expect(collector.Env[2].ValueFrom.SecretKeyRef.Key).To(Equal("X-Scope-OrgID"))
+	})
+
+	It("should validate the OTLP config", func() {
+		nwConfig := newOTLPNetworkConfig()
+		Expect(expinternal.ValidateOTLPConfig(nwConfig)).To(Succeed())
+
+		nwConfig.Spec.MetricsExporter.OTLP.Protocol = "http"
+		Expect(expinternal.ValidateOTLPConfig(nwConfig)).ToNot(Succeed())
+		nwConfig.Spec.MetricsExporter.OTLP.Endpoint = "https://otel-collector.monitoring:4318"
+		Expect(expinternal.ValidateOTLPConfig(nwConfig)).To(Succeed())
+
+		enable := true
+		nwConfig.Spec.MetricsExporter.Prometheus = &amdv1alpha1.PrometheusConfig{
+			ServiceMonitor: &amdv1alpha1.ServiceMonitorConfig{Enable: &enable},
+		}
+		Expect(expinternal.ValidateOTLPConfig(nwConfig)).ToNot(Succeed())
+		nwConfig.Spec.MetricsExporter.OTLP.KeepService = &enable
+		Expect(utils.IsMetricsServiceEnable(nwConfig)).To(BeTrue())
+		Expect(expinternal.ValidateOTLPConfig(nwConfig)).To(Succeed())
+
+		nwConfig.Spec.MetricsExporter.OTLP.Endpoint = ""
+		Expect(expinternal.ValidateOTLPConfig(nwConfig)).ToNot(Succeed())
+	})
+
+	It("should read the header names from the headers secret", func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient := mock_client.NewMockClient(ctrl)
+		dcrh := newNetworkConfigReconcilerHelper(kubeClient, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		ctx := context.Background()
+		nwConfig := newOTLPNetworkConfig()
+
+		kubeClient.EXPECT().Get(ctx, client.ObjectKey{Namespace: nwConfigNamespace, Name: "otlp-headers"}, gomock.Any()).Do(
+			func(_ interface{}, _ interface{}, secret *v1.Secret, _ ...client.GetOption) {
+				secret.Data = map[string][]byte{"X-Scope-OrgID": []byte("tenant"), "Authorization": []byte("Bearer token")}
+			},
+		)
+		keys, err := dcrh.getOTLPHeaderKeys(ctx, nwConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keys).To(Equal([]string{"Authorization", "X-Scope-OrgID"}))
+
+		kubeClient.EXPECT().Get(ctx, client.ObjectKey{Namespace: nwConfigNamespace, Name: "otlp-headers"}, gomock.Any()).Return(
+			k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "otlp-headers"))
+		_, err = dcrh.getOTLPHeaderKeys(ctx, nwConfig)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("kube-rbac-proxy auto TLS", func() {
+	newAutoTLSNetworkConfig := func() *amdv1alpha1.NetworkConfig {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.MetricsExporter = amdv1alpha1.MetricsExporterSpec{
+			Enable:      &enable,
+			HostNetwork: &enable,
+			RbacConfig: amdv1alpha1.KubeRbacConfig{
+				Enable:              &enable,
+				StaticAuthorization: &amdv1alpha1.StaticAuthConfig{Enable: true, ClientName: "prometheus"},
+				AutoTLS:             &amdv1alpha1.AutoTLSConfig{Enable: &enable, Provider: "internal", DurationHours: 2160},
+			},
+			Prometheus: &amdv1alpha1.PrometheusConfig{
+				ServiceMonitor: &amdv1alpha1.ServiceMonitorConfig{Enable: &enable},
+			},
+		}
+		return nwConfig
+	}
+	parseCert := func(certPEM []byte) *x509.Certificate {
+		block, _ := pem.Decode(certPEM)
+		Expect(block).ToNot(BeNil())
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).ToNot(HaveOccurred())
+		return cert
+	}
+
+	It("should issue and renew the certificates with the internal CA", func() {
+		nwConfig := newAutoTLSNetworkConfig()
+		now := time.Now()
+		caSecret := &v1.Secret{}
+		Expect(expinternal.SetCASecretAsDesired(caSecret, nwConfig, now)).To(Succeed())
+		Expect(caSecret.Type).To(Equal(v1.SecretTypeTLS))
+		ca := parseCert(caSecret.Data[expinternal.CACertKey])
+		Expect(ca.IsCA).To(BeTrue())
+		roots := x509.NewCertPool()
+		roots.AddCert(ca)
+
+		serving := &v1.Secret{}
+		Expect(expinternal.SetServingTLSSecretAsDesired(serving, caSecret, nwConfig, now)).To(Succeed())
+		servingCert := parseCert(serving.Data[v1.TLSCertKey])
+		_, err := servingCert.Verify(x509.VerifyOptions{
+			Roots:       roots,
+			DNSName:     "nwConfigName-metrics-exporter.nwConfigNamespace.svc",
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			CurrentTime: now,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		clientSecret := &v1.Secret{}
+		Expect(expinternal.SetClientTLSSecretAsDesired(clientSecret, caSecret, nwConfig, now)).To(Succeed())
+		clientCert := parseCert(clientSecret.Data[v1.TLSCertKey])
+		Expect(clientCert.Subject.CommonName).To(Equal("prometheus"))
+		Expect(clientCert.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}))
+
+		// the certificates are kept until a third of their validity is left
+		caData := caSecret.Data[v1.TLSCertKey]
+		servingData := serving.Data[v1.TLSCertKey]
+		later := now.Add(30 * 24 * time.Hour)
+		Expect(expinternal.SetCASecretAsDesired(caSecret, nwConfig, later)).To(Succeed())
+		Expect(expinternal.SetServingTLSSecretAsDesired(serving, caSecret, nwConfig, later)).To(Succeed())
+		Expect(caSecret.Data[v1.TLSCertKey]).To(Equal(caData))
+		Expect(serving.Data[v1.TLSCertKey]).To(Equal(servingData))
+
+		later = now.Add(61 * 24 * time.Hour)
+		Expect(expinternal.SetServingTLSSecretAsDesired(serving, caSecret, nwConfig, later)).To(Succeed())
+		Expect(serving.Data[v1.TLSCertKey]).ToNot(Equal(servingData))
+		Expect(parseCert(serving.Data[v1.TLSCertKey]).NotAfter.After(later.Add(89 * 24 * time.Hour))).To(BeTrue())
+
+		cm := &v1.ConfigMap{}
+		hash := expinternal.SetClientCAConfigMapAsDesired(cm, nwConfig, caSecret.Data[expinternal.CACertKey])
+		Expect(hash).ToNot(BeEmpty())
+		Expect(cm.Data).To(HaveKeyWithValue(expinternal.CACertKey, string(caData)))
+	})
+
+	It("should wire the issued certificates into kube-rbac-proxy and the ServiceMonitor", func() {
+		nwConfig := newAutoTLSNetworkConfig()
+		Expect(expinternal.ValidateAutoTLSConfig(nwConfig)).To(Succeed())
+
+		mxOut := expinternal.GenerateCommonExporterSpec(nwConfig)
+		Expect(mxOut.RbacConfig.Secret.Name).To(Equal("nwConfigName-metrics-exporter-tls"))
+		Expect(mxOut.RbacConfig.ClientCAConfigMap.Name).To(Equal("nwConfigName-metrics-exporter-client-ca"))
+		tlsConfig := mxOut.Prometheus.ServiceMonitor.TLSConfig
+		Expect(tlsConfig.CA.Secret.Name).To(Equal("nwConfigName-metrics-exporter-tls"))
+		Expect(tlsConfig.CA.Secret.Key).To(Equal(expinternal.CACertKey))
+		Expect(tlsConfig.Cert.Secret.Name).To(Equal("nwConfigName-metrics-exporter-client-tls"))
+		Expect(tlsConfig.KeySecret.Key).To(Equal(v1.TLSPrivateKeyKey))
+		Expect(*tlsConfig.ServerName).To(Equal("nwConfigName-metrics-exporter.nwConfigNamespace.svc"))
+
+		// the fields set by the user are kept
+		nwConfig.Spec.MetricsExporter.Prometheus.ServiceMonitor.TLSConfig = &monitoringv1.TLSConfig{
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{
+				CA: monitoringv1.SecretOrConfigMap{ConfigMap: &v1.ConfigMapKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "custom-ca"}, Key: "ca.crt"}},
+			},
+		}
+		tlsConfig = expinternal.GenerateCommonExporterSpec(nwConfig).Prometheus.ServiceMonitor.TLSConfig
+		Expect(tlsConfig.CA.ConfigMap.Name).To(Equal("custom-ca"))
+		Expect(tlsConfig.CA.Secret).To(BeNil())
+		Expect(tlsConfig.Cert.Secret.Name).To(Equal("nwConfigName-metrics-exporter-client-tls"))
+
+		nwConfig.Spec.MetricsExporter.RbacConfig.Secret = &v1.LocalObjectReference{Name: "rbac-tls"}
+		Expect(expinternal.ValidateAutoTLSConfig(nwConfig)).ToNot(Succeed())
+		nwConfig.Spec.MetricsExporter.RbacConfig.Secret = nil
+		nwConfig.Spec.MetricsExporter.RbacConfig.AutoTLS.IssuerRef = &amdv1alpha1.CertManagerIssuerRef{Name: "ca-issuer"}
+		Expect(expinternal.ValidateAutoTLSConfig(nwConfig)).ToNot(Succeed())
+		nwConfig.Spec.MetricsExporter.RbacConfig.AutoTLS.Provider = "cert-manager"
+		Expect(expinternal.ValidateAutoTLSConfig(nwConfig)).To(Succeed())
+	})
+
+	It("should render the cert-manager Certificates", func() {
+		nwConfig := newAutoTLSNetworkConfig()
+		nwConfig.Spec.MetricsExporter.RbacConfig.AutoTLS.Provider = "cert-manager"
+		Expect(expinternal.IsCertManagerProvider(nwConfig)).To(BeTrue())
+
+		cert := &unstructured.Unstructured{}
+		Expect(expinternal.SetServingCertificateAsDesired(cert, nwConfig)).To(Succeed())
+		secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+		Expect(secretName).To(Equal("nwConfigName-metrics-exporter-tls"))
+		dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+		Expect(dnsNames).To(ContainElement("nwConfigName-metrics-exporter.nwConfigNamespace.svc"))
+		renewBefore, _, _ := unstructured.NestedString(cert.Object, "spec", "renewBefore")
+		Expect(renewBefore).To(Equal("720h0m0s"))
+		issuer, _, _ := unstructured.NestedStringMap(cert.Object, "spec", "issuerRef")
+		Expect(issuer).To(Equal(map[string]string{"name": "nwConfigName-metrics-exporter-ca", "kind": "Issuer", "group": "cert-manager.io"}))
+
+		nwConfig.Spec.MetricsExporter.RbacConfig.AutoTLS.IssuerRef = &amdv1alpha1.CertManagerIssuerRef{Name: "corp-ca", Kind: "ClusterIssuer"}
+		Expect(expinternal.IsOwnCAIssuer(nwConfig)).To(BeFalse())
+		Expect(expinternal.SetClientCertificateAsDesired(cert, nwConfig)).To(Succeed())
+		commonName, _, _ := unstructured.NestedString(cert.Object, "spec", "commonName")
+		Expect(commonName).To(Equal("prometheus"))
+		issuer, _, _ = unstructured.NestedStringMap(cert.Object, "spec", "issuerRef")
+		Expect(issuer).To(Equal(map[string]string{"name": "corp-ca", "kind": "ClusterIssuer", "group": "cert-manager.io"}))
+	})
+})
+
+var _ = Describe("node remediation", func() {
+	var (
+		kubeClient *mock_client.MockClient
+		upgradeMgr *MockupgradeMgrAPI
+		dcrh       *networkConfigReconcilerHelper
+	)
+
+	BeforeEach(func() {
+		ctrl := gomock.NewController(GinkgoT())
+		kubeClient = mock_client.NewMockClient(ctrl)
+		upgradeMgr = NewMockupgradeMgrAPI(ctrl)
+		upgradeMgr.EXPECT().GetNodeStatus(gomock.Any()).Return(amdv1alpha1.UpgradeStateComplete).AnyTimes()
+		dcrh = newNetworkConfigReconcilerHelper(kubeClient, nil, nil, upgradeMgr, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+	})
+
+	ctx := context.Background()
+	recordName := nwConfigName + "-unit-test-node"
+	remediationScheme := runtime.NewScheme()
+	utilruntime.Must(amdv1alpha1.AddToScheme(remediationScheme))
+
+	newRemediationNetworkConfig := func() *amdv1alpha1.NetworkConfig {
+		enable := true
+		nwConfig := &amdv1alpha1.NetworkConfig{ObjectMeta: metav1.ObjectMeta{Name: nwConfigName, Namespace: nwConfigNamespace}}
+		nwConfig.Spec.Remediation = amdv1alpha1.RemediationSpec{
+			Enable:                    &enable,
+			UnhealthyNICThreshold:     2,
+			UnhealthyDurationSeconds:  0,
+			TaintEffect:               v1.TaintEffectNoSchedule,
+			Cordon:                    &enable,
+			MaxConcurrentRemediations: 1,
+		}
+		return nwConfig
+	}
+	newNodes := func(capacity, allocatable int64) *v1.NodeList {
+		nodes := testNodeList.DeepCopy()
+		nodes.Items[0].Status.Capacity = v1.ResourceList{"amd.com/nic": *resource.NewQuantity(capacity, resource.DecimalSI)}
+		nodes.Items[0].Status.Allocatable = v1.ResourceList{"amd.com/nic": *resource.NewQuantity(allocatable, resource.DecimalSI)}
+		return nodes
+	}
+	expectRecords := func(own []amdv1alpha1.NodeRemediation, all []amdv1alpha1.NodeRemediation) {
+		kubeClient.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, list *amdv1alpha1.NodeRemediationList, _ ...client.ListOption) {
+				list.Items = own
+			})
+		kubeClient.EXPECT().List(ctx, gomock.Any()).Do(
+			func(_ interface{}, list *amdv1alpha1.NodeRemediationList, _ ...client.ListOption) {
+				list.Items = all
+			})
+	}
+
+	It("should leave the nodes being upgraded alone", func() {
+		nwConfig := newRemediationNetworkConfig()
+		expectRecords(nil, nil)
+
+		// the upgrade taint is left on the node until the upgrade is done
+		nodes := newNodes(4, 2)
+		nodes.Items[0].Spec.Taints = []v1.Taint{{Key: driverUpgradeTaintKey, Value: "true", Effect: v1.TaintEffectNoSchedule}}
+		res, err := dcrh.handleNodeRemediation(ctx, nwConfig, nodes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(remediationCheckInterval))
+	})
+
+	It("should let the device plugin and the exporter tolerate the NIC unhealthy taint", func() {
+		hostNetwork := true
+		nwConfig := newRemediationNetworkConfig()
+		nwConfig.Spec.DevicePlugin.DevicePluginTolerations = []v1.Toleration{{Key: "example", Operator: v1.TolerationOpExists}}
+		nwConfig.Spec.MetricsExporter.HostNetwork = &hostNetwork
+		toleration := v1.Toleration{Key: utils.NICUnhealthyTaintKey, Operator: v1.TolerationOpExists}
+
+		dpSpec := dpinternal.GenerateCommonDevicePluginSpec(nwConfig, false)
+		Expect(dpSpec.Tolerations).To(Equal([]v1.Toleration{{Key: "example", Operator: v1.TolerationOpExists}, toleration}))
+		Expect(nwConfig.Spec.DevicePlugin.DevicePluginTolerations).To(HaveLen(1))
+		Expect(expinternal.GenerateCommonExporterSpec(nwConfig).DsSpec.Tolerations).To(Equal([]v1.Toleration{toleration}))
+	})
+
+	It("should taint and cordon the node once enough NICs are unhealthy", func() {
+		nwConfig := newRemediationNetworkConfig()
+		expectRecords(nil, nil)
+
+		var patched *v1.Node
+		var created *amdv1alpha1.NodeRemediation
+		kubeClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, node *v1.Node, _ client.Patch, _ ...client.PatchOption) {
+				patched = node.DeepCopy()
+			})
+		kubeClient.EXPECT().Get(ctx, types.NamespacedName{Namespace: nwConfigNamespace, Name: recordName}, gomock.Any()).
+			Return(k8serrors.NewNotFound(schema.GroupResource{}, recordName))
+		kubeClient.EXPECT().Scheme().Return(remediationScheme)
+		kubeClient.EXPECT().Create(ctx, gomock.Any()).Do(
+			func(_ interface{}, record *amdv1alpha1.NodeRemediation, _ ...client.CreateOption) {
+				created = record.DeepCopy()
+			})
+
+		res, err := dcrh.handleNodeRemediation(ctx, nwConfig, newNodes(4, 2))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeZero())
+		Expect(patched.Spec.Unschedulable).To(BeTrue())
+		Expect(patched.Spec.Taints).To(ContainElement(v1.Taint{Key: utils.NICUnhealthyTaintKey, Value: "true", Effect: v1.TaintEffectNoSchedule}))
+		Expect(created.Spec.NodeName).To(Equal("unit-test-node"))
+		Expect(created.Labels).To(HaveKeyWithValue(utils.CRNameLabel, nwConfigName))
+		Expect(created.Status.Phase).To(Equal(amdv1alpha1.NodeRemediationRemediating))
+		Expect(created.Status.UnhealthyNICs).To(Equal(int32(2)))
+		Expect(created.Status.Tainted).To(BeTrue())
+		Expect(created.Status.Cordoned).To(BeTrue())
+	})
+
+	It("should keep the node pending while the duration or the cluster-wide limit is not met", func() {
+		nwConfig := newRemediationNetworkConfig()
+		nwConfig.Spec.Remediation.UnhealthyDurationSeconds = 300
+		expectRecords(nil, nil)
+		var created *amdv1alpha1.NodeRemediation
+		kubeClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, recordName))
+		kubeClient.EXPECT().Scheme().Return(remediationScheme)
+		kubeClient.EXPECT().Create(ctx, gomock.Any()).Do(
+			func(_ interface{}, record *amdv1alpha1.NodeRemediation, _ ...client.CreateOption) {
+				created = record.DeepCopy()
+			})
+
+		res, err := dcrh.handleNodeRemediation(ctx, nwConfig, newNodes(4, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeNumerically("~", 300*time.Second, time.Second))
+		Expect(created.Status.Phase).To(Equal(amdv1alpha1.NodeRemediationPending))
+		Expect(created.Status.UnhealthySince).ToNot(BeNil())
+
+		// the duration elapsed but another node of the cluster is being remediated
+		nwConfig.Spec.Remediation.UnhealthyDurationSeconds = 0
+		pending := amdv1alpha1.NodeRemediation{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nwConfigNamespace, Name: recordName},
+			Spec:       amdv1alpha1.NodeRemediationSpec{NodeName: "unit-test-node"},
+			Status:     *created.Status.DeepCopy(),
+		}
+		other := amdv1alpha1.NodeRemediation{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "other-node"},
+			Status:     amdv1alpha1.NodeRemediationStatus{Phase: amdv1alpha1.NodeRemediationRemediating},
+		}
+		expectRecords([]amdv1alpha1.NodeRemediation{pending}, []amdv1alpha1.NodeRemediation{pending, other})
+		var updated *amdv1alpha1.NodeRemediation
+		kubeClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, _ interface{}, record *amdv1alpha1.NodeRemediation, _ ...client.GetOption) {
+				pending.DeepCopyInto(record)
+			})
+		kubeClient.EXPECT().Scheme().Return(remediationScheme)
+		kubeClient.EXPECT().Update(ctx, gomock.Any()).Do(
+			func(_ interface{}, record *amdv1alpha1.NodeRemediation, _ ...client.UpdateOption) {
+				updated = record.DeepCopy()
+			})
+
+		res, err = dcrh.handleNodeRemediation(ctx, nwConfig, newNodes(4, 1))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(remediationCheckInterval))
+		Expect(updated.Status.Phase).To(Equal(amdv1alpha1.NodeRemediationPending))
+		Expect(updated.Status.Message).To(ContainSubstring("1 nodes are remediated across the cluster"))
+	})
+
+	It("should reverse the remediation once the NICs recover", func() {
+		nwConfig := newRemediationNetworkConfig()
+		remediating := amdv1alpha1.NodeRemediation{
+			ObjectMeta: metav1.ObjectMeta{Namespace: nwConfigNamespace, Name: recordName},
+			Spec:       amdv1alpha1.NodeRemediationSpec{NodeName: "unit-test-node"},
+			Status: amdv1alpha1.NodeRemediationStatus{
+				Phase:    amdv1alpha1.NodeRemediationRemediating,
+				Tainted:  true,
+				Cordoned: true,
+			},
+		}
+		expectRecords([]amdv1alpha1.NodeRemediation{remediating}, []amdv1alpha1.NodeRemediation{remediating})
+
+		var patched *v1.Node
+		var updated *amdv1alpha1.NodeRemediation
+		kubeClient.EXPECT().Get(ctx, client.ObjectKey{Name: "unit-test-node"}, gomock.Any()).Do(
+			func(_ interface{}, _ interface{}, node *v1.Node, _ ...client.GetOption) {
+				node.Name = "unit-test-node"
+				node.Spec.Unschedulable = true
+				node.Spec.Taints = []v1.Taint{
+					{Key: "other", Effect: v1.TaintEffectNoSchedule},
+					{Key: utils.NICUnhealthyTaintKey, Value: "true", Effect: v1.TaintEffectNoSchedule},
+				}
+			})
+		kubeClient.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Do(
+			func(_ interface{}, node *v1.Node, _ client.Patch, _ ...client.PatchOption) {
+				patched = node.DeepCopy()
+			})
+		kubeClient.EXPECT().Get(ctx, types.NamespacedName{Namespace: nwConfigNamespace, Name: recordName}, gomock.Any()).Do(
+			func(_ interface{}, _ interface{}, record *amdv1alpha1.NodeRemediation, _ ...client.GetOption) {
+				remediating.DeepCopyInto(record)
+			})
+		kubeClient.EXPECT().Scheme().Return(remediationScheme)
+		kubeClient.EXPECT().Update(ctx, gomock.Any()).Do(
+			func(_ interface{}, record *amdv1alpha1.NodeRemediation, _ ...client.UpdateOption) {
+				updated = record.DeepCopy()
+			})
+
+		_, err := dcrh.handleNodeRemediation(ctx, nwConfig, newNodes(4, 4))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patched.Spec.Unschedulable).To(BeFalse())
+		Expect(patched.Spec.Taints).To(Equal([]v1.Taint{{Key: "other", Effect: v1.TaintEffectNoSchedule}}))
+		Expect(updated.Status.Phase).To(Equal(amdv1alpha1.NodeRemediationResolved))
+		Expect(updated.Status.ResolvedTime).ToNot(BeNil())
+		Expect(updated.Status.Tainted).To(BeFalse())
+		Expect(updated.Status.Cordoned).To(BeFalse())
+	})
+
+	It("should skip the DaemonSet, static and unmanaged pods when draining", func() {
+		isController := true
+		pods := []v1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "daemonset", OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds", Controller: &isController}}}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "static", Annotations: map[string]string{v1.MirrorPodAnnotationKey: "mirror"}}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "unmanaged"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "completed", OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "job", Controller: &isController}}},
+				Status: v1.PodStatus{Phase: v1.PodSucceeded}},
+		}
+		kubeClient.EXPECT().List(ctx, gomock.Any(), client.MatchingFields{podNodeNameIndexKey: "unit-test-node"}).Do(
+			func(_ interface{}, list *v1.PodList, _ ...client.ListOption) {
+				list.Items = pods
+			})
+
+		pending, err := dcrh.drainNode(ctx, "unit-test-node", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pending).To(BeZero())
+	})
+})
+
+var _ = Describe("kmod signature verification", func() {
+	It("should report the nodes whose verification failed to start", func() {
+		dcrh := newNetworkConfigReconcilerHelper(nil, nil, nil, nil, nil, nil, nil, nil).(*networkConfigReconcilerHelper)
+		nsn := types.NamespacedName{Namespace: nwConfigNamespace, Name: nwConfigName}
+		node := testNodeList.Items[0].DeepCopy()
+		image := "registry/amdainic_kmod:ubuntu-22.04-6.8.0-40-generic-1.117.1-a-42"
+		Expect(dcrh.getKmodSignatureStatus(node, nsn, image)).To(Equal(kmodSignaturePending))
+
+		dcrh.kmodSignatureErrors[nsn.String()] = map[string]string{node.Name: "pods is forbidden"}
+		Expect(dcrh.getKmodSignatureStatus(node, nsn, image)).To(Equal("Failed: failed to start the verification: pods is forbidden"))
+
+		// the errors are dropped with the NetworkConfig
+		dcrh.updateNodeAssignments(nsn.String(), nil, true)
+		Expect(dcrh.getKmodSignatureStatus(node, nsn, image)).To(Equal(kmodSignaturePending))
+	})
+})
+
 var _ = Describe("setFinalizer", func() {
 	var (
 		kubeClient *mock_client.MockClient
@@ -438,8 +2415,9 @@ var _ = Describe("handleBuildConfigMap", func() {
 			},
 		}
 		gomock.InOrder(
+			kmmHelper.EXPECT().GetOSProfiles(ctx, nwConfig).Return(kmmmodule.DefaultOSProfiles()),
 			kubeClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "whatever")),
-			kmmHelper.EXPECT().SetBuildConfigMapAsDesired(newBuildCM, nwConfig).Return(nil),
+			kmmHelper.EXPECT().SetBuildConfigMapAsDesired(newBuildCM, nwConfig, gomock.Any(), "22.04").Return(nil),
 			kubeClient.EXPECT().Create(ctx, gomock.Any()).Return(nil),
 		)
 
@@ -455,13 +2433,14 @@ var _ = Describe("handleBuildConfigMap", func() {
 			},
 		}
 		gomock.InOrder(
+			kmmHelper.EXPECT().GetOSProfiles(ctx, nwConfig).Return(kmmmodule.DefaultOSProfiles()),
 			kubeClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Do(
 				func(_ interface{}, _ interface{}, buildCM *v1.ConfigMap, _ ...client.GetOption) {
 					buildCM.Name = kmmmodule.GetCMName("ubuntu-22.04", nwConfig)
 					buildCM.Namespace = nwConfig.Namespace
 				},
 			),
-			kmmHelper.EXPECT().SetBuildConfigMapAsDesired(existingBuildCM, nwConfig).Return(nil),
+			kmmHelper.EXPECT().SetBuildConfigMapAsDesired(existingBuildCM, nwConfig, gomock.Any(), "22.04").Return(nil),
 		)
 
 		err := dcrh.handleBuildConfigMap(ctx, nwConfig, testNodeList)
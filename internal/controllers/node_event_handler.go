@@ -33,34 +33,168 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"maps"
+	"strings"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	dpinternal "github.com/ROCm/network-operator/internal/deviceplugin"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-type NodeKernelVersionPredicate struct {
+// NodePredicate filters the node events that could affect the NetworkConfigs
+type NodePredicate struct {
 	predicate.Funcs
 }
 
-func (NodeKernelVersionPredicate) Create(e event.CreateEvent) bool {
+func (NodePredicate) Create(e event.CreateEvent) bool {
 	return true
 }
 
-func (NodeKernelVersionPredicate) Update(e event.UpdateEvent) bool {
+func (NodePredicate) Update(e event.UpdateEvent) bool {
 	oldNode, okOld := e.ObjectOld.(*v1.Node)
 	newNode, okNew := e.ObjectNew.(*v1.Node)
 	if !okOld || !okNew {
 		return false
 	}
+	// whether the label changes are relevant to any NetworkConfig selector
+	// is decided by the NodeEventHandler
+	return !maps.Equal(oldNode.Labels, newNode.Labels) || nodeStateChanged(oldNode, newNode)
+}
 
-	oldKernelVersion := oldNode.Status.NodeInfo.KernelVersion
-	newKernelVersion := newNode.Status.NodeInfo.KernelVersion
+func (NodePredicate) Delete(e event.DeleteEvent) bool {
+	return true
+}
 
-	// if kernel version changed
-	// reconcile the networkconfig to update kernel mapping of KMM CR
-	return oldKernelVersion != newKernelVersion
+func (NodePredicate) Generic(e event.GenericEvent) bool {
+	return false
 }
 
-func (NodeKernelVersionPredicate) Delete(e event.DeleteEvent) bool {
-	return true
+// nodeStateChanged returns true if the node changed in a way that requires reconciling its NetworkConfigs
+//  1. kernel version or OS image changed: the KMM kernel mappings and build configs need to be updated
+//  2. Ready condition transitioned: the node status and operands need to be refreshed
+//  3. boot ID changed: the node rebooted, upgrade and kmod signature states need to be refreshed
+//  4. extended resources changed: the AMDNetworkReady node condition and the NIC remediation need to be refreshed,
+//     any resource is compared as the NIC resource names depend on the resource pools of each NetworkConfig
+func nodeStateChanged(oldNode, newNode *v1.Node) bool {
+	return oldNode.Status.NodeInfo.KernelVersion != newNode.Status.NodeInfo.KernelVersion ||
+		oldNode.Status.NodeInfo.OSImage != newNode.Status.NodeInfo.OSImage ||
+		oldNode.Status.NodeInfo.BootID != newNode.Status.NodeInfo.BootID ||
+		getNodeReadyStatus(oldNode) != getNodeReadyStatus(newNode) ||
+		extendedResourcesChanged(oldNode.Status.Capacity, newNode.Status.Capacity) ||
+		extendedResourcesChanged(oldNode.Status.Allocatable, newNode.Status.Allocatable)
+}
+
+// extendedResourcesChanged returns true if an extended resource was added, removed or changed its quantity
+func extendedResourcesChanged(oldResources, newResources v1.ResourceList) bool {
+	isExtended := func(name v1.ResourceName) bool { return strings.Contains(string(name), "/") }
+	for name, quantity := range newResources {
+		if oldQuantity, ok := oldResources[name]; isExtended(name) && (!ok || !oldQuantity.Equal(quantity)) {
+			return true
+		}
+	}
+	for name := range oldResources {
+		if _, ok := newResources[name]; isExtended(name) && !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getNICResourceCount returns the capacity and allocatable count of the NIC resources advertised by the device plugin
+// of the NetworkConfig, as named by its resource pools and prefix
+func getNICResourceCount(nwConfig *amdv1alpha1.NetworkConfig, node *v1.Node) (capacity, allocatable int64) {
+	for _, name := range dpinternal.GetResourceNames(nwConfig) {
+		if quantity, ok := node.Status.Capacity[name]; ok {
+			capacity += quantity.Value()
+		}
+		if quantity, ok := node.Status.Allocatable[name]; ok {
+			allocatable += quantity.Value()
+		}
+	}
+	return capacity, allocatable
+}
+
+func getNodeReadyStatus(node *v1.Node) v1.ConditionStatus {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status
+		}
+	}
+	return v1.ConditionUnknown
+}
+
+// NodeEventHandler enqueues only the NetworkConfigs whose selector is affected by the node event
+type NodeEventHandler struct {
+	findNetworkConfigsForNode func(ctx context.Context, node *v1.Node) []reconcile.Request
+}
+
+func newNodeEventHandler(findNetworkConfigsForNode func(ctx context.Context, node *v1.Node) []reconcile.Request) *NodeEventHandler {
+	return &NodeEventHandler{
+		findNetworkConfigsForNode: findNetworkConfigsForNode,
+	}
+}
+
+// Create handle node create event
+func (h *NodeEventHandler) Create(ctx context.Context, evt event.TypedCreateEvent[client.Object], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueueForNode(ctx, evt.Object, q)
+}
+
+// Delete handle node delete event
+func (h *NodeEventHandler) Delete(ctx context.Context, evt event.TypedDeleteEvent[client.Object], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.enqueueForNode(ctx, evt.Object, q)
+}
+
+// Generic handle node generic event
+func (h *NodeEventHandler) Generic(ctx context.Context, evt event.TypedGenericEvent[client.Object], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	// Handle generic event if needed
+}
+
+// Update handle node update event
+func (h *NodeEventHandler) Update(ctx context.Context, evt event.TypedUpdateEvent[client.Object], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	oldNode, okOld := evt.ObjectOld.(*v1.Node)
+	newNode, okNew := evt.ObjectNew.(*v1.Node)
+	if !okOld || !okNew {
+		return
+	}
+	oldReqs := h.findNetworkConfigsForNode(ctx, oldNode)
+	newReqs := h.findNetworkConfigsForNode(ctx, newNode)
+	if nodeStateChanged(oldNode, newNode) {
+		// reconcile all the NetworkConfigs selecting the node before or after the change
+		for _, req := range append(oldReqs, newReqs...) {
+			q.Add(req)
+		}
+		return
+	}
+	// only the labels changed, reconcile the NetworkConfigs which started or stopped selecting the node
+	oldSet := map[types.NamespacedName]bool{}
+	for _, req := range oldReqs {
+		oldSet[req.NamespacedName] = true
+	}
+	for _, req := range newReqs {
+		if oldSet[req.NamespacedName] {
+			delete(oldSet, req.NamespacedName)
+			continue
+		}
+		q.Add(req)
+	}
+	for nsn := range oldSet {
+		q.Add(reconcile.Request{NamespacedName: nsn})
+	}
+}
+
+func (h *NodeEventHandler) enqueueForNode(ctx context.Context, obj client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	node, ok := obj.(*v1.Node)
+	if !ok {
+		return
+	}
+	for _, req := range h.findNetworkConfigsForNode(ctx, node) {
+		q.Add(req)
+	}
 }
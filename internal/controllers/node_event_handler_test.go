@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Copyright (c) Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("NodePredicate", func() {
+	newNode := func(labels map[string]string, ready v1.ConditionStatus) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: labels},
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{
+					KernelVersion: "6.8.0-40-generic",
+					OSImage:       "Ubuntu 22.04.3 LTS",
+					BootID:        "boot-1",
+				},
+				Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: ready}},
+			},
+		}
+	}
+
+	It("should filter irrelevant node updates", func() {
+		oldNode := newNode(map[string]string{"a": "1"}, v1.ConditionTrue)
+		newNode := oldNode.DeepCopy()
+		newNode.Status.Allocatable = v1.ResourceList{}
+		Expect(NodePredicate{}.Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode})).To(BeFalse())
+	})
+
+	It("should pass label, OS image, boot ID and Ready changes", func() {
+		oldNode := newNode(map[string]string{"a": "1"}, v1.ConditionTrue)
+
+		labelChanged := oldNode.DeepCopy()
+		labelChanged.Labels["b"] = "2"
+		osImageChanged := oldNode.DeepCopy()
+		osImageChanged.Status.NodeInfo.OSImage = "Ubuntu 24.04.1 LTS"
+		bootIDChanged := oldNode.DeepCopy()
+		bootIDChanged.Status.NodeInfo.BootID = "boot-2"
+		notReady := newNode(map[string]string{"a": "1"}, v1.ConditionFalse)
+
+		for _, node := range []*v1.Node{labelChanged, osImageChanged, bootIDChanged, notReady} {
+			Expect(NodePredicate{}.Update(event.UpdateEvent{ObjectOld: oldNode, ObjectNew: node})).To(BeTrue())
+		}
+	})
+})
+
+var _ = Describe("NodeEventHandler", func() {
+	ctx := context.Background()
+	selectors := map[string]map[string]string{
+		"nic":  {"feature.node.kubernetes.io/amd-nic": "true"},
+		"zone": {"zone": "a"},
+	}
+	// stub the selector index lookup with the plain selector matching
+	h := newNodeEventHandler(func(_ context.Context, node *v1.Node) []reconcile.Request {
+		reqs := []reconcile.Request{}
+		for name, selector := range selectors {
+			if k8slabels.SelectorFromSet(selector).Matches(k8slabels.Set(node.Labels)) {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: name}})
+			}
+		}
+		return reqs
+	})
+
+	drain := func(q workqueue.TypedRateLimitingInterface[reconcile.Request]) []string {
+		names := []string{}
+		for q.Len() > 0 {
+			req, _ := q.Get()
+			names = append(names, req.Name)
+			q.Done(req)
+		}
+		return names
+	}
+
+	It("should only enqueue the NetworkConfigs whose selection changed on label updates", func() {
+		q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer q.ShutDown()
+		oldNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{"zone": "a"}}}
+		newNode := oldNode.DeepCopy()
+		newNode.Labels["feature.node.kubernetes.io/amd-nic"] = "true"
+
+		h.Update(ctx, event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode}, q)
+		Expect(drain(q)).To(ConsistOf("nic"))
+	})
+
+	It("should enqueue all the selecting NetworkConfigs on node state changes", func() {
+		q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer q.ShutDown()
+		oldNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: map[string]string{
+			"zone":                               "a",
+			"feature.node.kubernetes.io/amd-nic": "true",
+		}}}
+		newNode := oldNode.DeepCopy()
+		newNode.Status.NodeInfo.BootID = "boot-2"
+
+		h.Update(ctx, event.UpdateEvent{ObjectOld: oldNode, ObjectNew: newNode}, q)
+		Expect(drain(q)).To(ConsistOf("nic", "zone"))
+	})
+})
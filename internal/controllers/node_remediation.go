@@ -0,0 +1,407 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// remediationCheckInterval is the interval the pending remediations and node drains are evaluated at
+	remediationCheckInterval = time.Minute
+)
+
+// getNodeRemediationName returns the name of the NodeRemediation of the node
+func getNodeRemediationName(nwConfig *amdv1alpha1.NetworkConfig, nodeName string) string {
+	return nwConfig.Name + "-" + nodeName
+}
+
+// getUnhealthyNICCount returns the number of NIC resources the device plugin withdrew from the allocatable resources of the node
+func getUnhealthyNICCount(nwConfig *amdv1alpha1.NetworkConfig, node *v1.Node) int32 {
+	capacity, allocatable := getNICResourceCount(nwConfig, node)
+	if allocatable >= capacity {
+		return 0
+	}
+	return int32(capacity - allocatable)
+}
+
+// handleNodeRemediation remediates the selected nodes whose NICs stayed unhealthy for the configured duration
+// the node is tainted, cordoned and drained if configured, and the steps are reversed once the NICs recover
+// the progress of each node is recorded in a NodeRemediation, the cluster-wide limit counts the nodes being remediated
+// across all the NetworkConfigs
+func (dcrh *networkConfigReconcilerHelper) handleNodeRemediation(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) (ctrl.Result, error) {
+	res := ctrl.Result{}
+	if !utils.IsRemediationEnable(nwConfig) {
+		return res, dcrh.finalizeNodeRemediation(ctx, nwConfig)
+	}
+
+	records, err := dcrh.listNodeRemediations(ctx, nwConfig)
+	if err != nil {
+		return res, err
+	}
+
+	allRecords := amdv1alpha1.NodeRemediationList{}
+	if err := dcrh.client.List(ctx, &allRecords); err != nil {
+		return res, fmt.Errorf("failed to list NodeRemediations: %v", err)
+	}
+	remediating := 0
+	for _, record := range allRecords.Items {
+		if record.Status.Phase == amdv1alpha1.NodeRemediationRemediating {
+			remediating++
+		}
+	}
+
+	selected := map[string]bool{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		selected[node.Name] = true
+		// the driver upgrade cordons, drains and reloads the driver of the node, leave it alone until it's done
+		if dcrh.isNodeUpgrading(node) {
+			res.RequeueAfter = remediationCheckInterval
+			continue
+		}
+		var record *amdv1alpha1.NodeRemediation
+		if r, ok := records[getNodeRemediationName(nwConfig, node.Name)]; ok {
+			record = &r
+		}
+		requeueAfter, err := dcrh.reconcileNodeRemediation(ctx, nwConfig, node, record, &remediating)
+		if err != nil {
+			return res, err
+		}
+		if requeueAfter > 0 && (res.RequeueAfter == 0 || requeueAfter < res.RequeueAfter) {
+			res.RequeueAfter = requeueAfter
+		}
+	}
+
+	// close the remediation of the nodes which are not selected anymore
+	for _, record := range records {
+		if selected[record.Spec.NodeName] {
+			continue
+		}
+		if err := dcrh.closeNodeRemediation(ctx, &record); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// isNodeUpgrading returns true if the driver upgrade of the node is in progress or left the node cordoned
+func (dcrh *networkConfigReconcilerHelper) isNodeUpgrading(node *v1.Node) bool {
+	switch dcrh.upgradeMgrHandler.GetNodeStatus(node.Name) {
+	case amdv1alpha1.UpgradeStateStarted,
+		amdv1alpha1.UpgradeStateInstallInProgress,
+		amdv1alpha1.UpgradeStateInProgress,
+		amdv1alpha1.UpgradeStateRebootInProgress:
+		return true
+	}
+	return slices.ContainsFunc(node.Spec.Taints, func(taint v1.Taint) bool {
+		return taint.Key == driverUpgradeTaintKey
+	})
+}
+
+// listNodeRemediations returns the NodeRemediations of the NetworkConfig keyed by name
+func (dcrh *networkConfigReconcilerHelper) listNodeRemediations(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) (map[string]amdv1alpha1.NodeRemediation, error) {
+	list := amdv1alpha1.NodeRemediationList{}
+	if err := dcrh.client.List(ctx, &list, client.InNamespace(nwConfig.Namespace), client.MatchingLabels{utils.CRNameLabel: nwConfig.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list NodeRemediations: %v", err)
+	}
+	records := map[string]amdv1alpha1.NodeRemediation{}
+	for _, record := range list.Items {
+		records[record.Name] = record
+	}
+	return records, nil
+}
+
+// reconcileNodeRemediation moves the NodeRemediation of the node through its phases and returns when it needs to be evaluated again
+func (dcrh *networkConfigReconcilerHelper) reconcileNodeRemediation(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, node *v1.Node, record *amdv1alpha1.NodeRemediation, remediating *int) (time.Duration, error) {
+	logger := log.FromContext(ctx)
+	spec := nwConfig.Spec.Remediation
+	threshold := max(spec.UnhealthyNICThreshold, 1)
+	maxRemediations := max(int(spec.MaxConcurrentRemediations), 1)
+	unhealthy := getUnhealthyNICCount(nwConfig, node)
+	now := metav1.Now()
+
+	if unhealthy < threshold {
+		if record == nil {
+			return 0, nil
+		}
+		switch record.Status.Phase {
+		case amdv1alpha1.NodeRemediationPending:
+			logger.Info("NICs recovered before the node was remediated", "node", node.Name)
+			if err := dcrh.client.Delete(ctx, record); err != nil && !k8serrors.IsNotFound(err) {
+				return 0, fmt.Errorf("failed to delete NodeRemediation %s: %v", record.Name, err)
+			}
+		case amdv1alpha1.NodeRemediationRemediating:
+			logger.Info("NICs recovered, reversing the remediation", "node", node.Name)
+			status := record.Status.DeepCopy()
+			if err := dcrh.restoreNode(ctx, node.Name, status); err != nil {
+				return 0, err
+			}
+			status.Phase = amdv1alpha1.NodeRemediationResolved
+			status.UnhealthyNICs = unhealthy
+			status.ResolvedTime = &now
+			status.Message = "NICs recovered"
+			if err := dcrh.saveNodeRemediation(ctx, nwConfig, node.Name, status); err != nil {
+				return 0, err
+			}
+			*remediating--
+		}
+		return 0, nil
+	}
+
+	status := &amdv1alpha1.NodeRemediationStatus{}
+	if record != nil && record.Status.Phase != amdv1alpha1.NodeRemediationResolved {
+		status = record.Status.DeepCopy()
+	}
+	if status.Phase == "" {
+		logger.Info("NICs unhealthy, opening a remediation", "node", node.Name, "unhealthy", unhealthy)
+		status.Phase = amdv1alpha1.NodeRemediationPending
+		status.UnhealthySince = &now
+	}
+	status.UnhealthyNICs = unhealthy
+
+	var requeueAfter time.Duration
+	if status.Phase == amdv1alpha1.NodeRemediationPending {
+		remaining := time.Duration(spec.UnhealthyDurationSeconds)*time.Second - now.Sub(status.UnhealthySince.Time)
+		switch {
+		case remaining > 0:
+			status.Message = fmt.Sprintf("%v NICs are unhealthy, the node is remediated if they stay unhealthy for %vs", unhealthy, spec.UnhealthyDurationSeconds)
+			requeueAfter = remaining
+		case *remediating >= maxRemediations:
+			status.Message = fmt.Sprintf("%v NICs are unhealthy, waiting as %v nodes are remediated across the cluster", unhealthy, *remediating)
+			requeueAfter = remediationCheckInterval
+		default:
+			logger.Info("remediating node", "node", node.Name, "unhealthy", unhealthy)
+			status.Phase = amdv1alpha1.NodeRemediationRemediating
+			status.StartTime = &now
+			*remediating++
+		}
+	}
+
+	if status.Phase == amdv1alpha1.NodeRemediationRemediating {
+		var err error
+		if requeueAfter, err = dcrh.remediateNode(ctx, nwConfig, node, status); err != nil {
+			return 0, err
+		}
+	}
+
+	return requeueAfter, dcrh.saveNodeRemediation(ctx, nwConfig, node.Name, status)
+}
+
+// saveNodeRemediation creates or updates the NodeRemediation of the node with the given status
+func (dcrh *networkConfigReconcilerHelper) saveNodeRemediation(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodeName string, status *amdv1alpha1.NodeRemediationStatus) error {
+	record := &amdv1alpha1.NodeRemediation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: nwConfig.Namespace,
+			Name:      getNodeRemediationName(nwConfig, nodeName),
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, dcrh.client, record, func() error {
+		if record.Labels == nil {
+			record.Labels = map[string]string{}
+		}
+		record.Labels[utils.CRNameLabel] = nwConfig.Name
+		record.Spec.NodeName = nodeName
+		status.DeepCopyInto(&record.Status)
+		return controllerutil.SetControllerReference(nwConfig, record, dcrh.client.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save NodeRemediation %s: %v", record.Name, err)
+	}
+	return nil
+}
+
+// remediateNode taints the node, cordons and drains it if configured, the drain is retried until it completes or times out
+func (dcrh *networkConfigReconcilerHelper) remediateNode(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, node *v1.Node, status *amdv1alpha1.NodeRemediationStatus) (time.Duration, error) {
+	spec := nwConfig.Spec.Remediation
+	drainNode := spec.Drain != nil && *spec.Drain
+	cordonNode := drainNode || (spec.Cordon != nil && *spec.Cordon)
+
+	effect := spec.TaintEffect
+	if effect == "" {
+		effect = v1.TaintEffectNoSchedule
+	}
+	nodeCopy := node.DeepCopy()
+	taints := []v1.Taint{}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key != utils.NICUnhealthyTaintKey {
+			taints = append(taints, taint)
+		}
+	}
+	node.Spec.Taints = append(taints, v1.Taint{
+		Key:    utils.NICUnhealthyTaintKey,
+		Value:  "true",
+		Effect: effect,
+	})
+	if cordonNode && !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+		status.Cordoned = true
+	}
+	if !equality.Semantic.DeepEqual(nodeCopy.Spec, node.Spec) {
+		if err := dcrh.client.Patch(ctx, node, client.MergeFrom(nodeCopy)); err != nil {
+			return 0, fmt.Errorf("failed to remediate node %v: %v", node.Name, err)
+		}
+	}
+	status.Tainted = true
+	status.Message = fmt.Sprintf("%v NICs are unhealthy, the node is tainted with %v", status.UnhealthyNICs, utils.NICUnhealthyTaintKey)
+
+	if !drainNode || status.Drained {
+		return 0, nil
+	}
+	policy := spec.NodeDrainPolicy
+	if policy != nil && policy.TimeoutSeconds > 0 && status.StartTime != nil &&
+		time.Since(status.StartTime.Time) > time.Duration(policy.TimeoutSeconds)*time.Second {
+		status.Message = fmt.Sprintf("%v NICs are unhealthy, the node drain timed out after %vs", status.UnhealthyNICs, policy.TimeoutSeconds)
+		return 0, nil
+	}
+	pending, err := dcrh.drainNode(ctx, node.Name, policy)
+	if pending == 0 && err == nil {
+		status.Drained = true
+		status.Message = fmt.Sprintf("%v NICs are unhealthy, the node is drained", status.UnhealthyNICs)
+		return 0, nil
+	}
+	status.Message = fmt.Sprintf("%v NICs are unhealthy, draining the node, %v pods left", status.UnhealthyNICs, pending)
+	if err != nil {
+		status.Message += fmt.Sprintf(": %v", err)
+	}
+	return remediationCheckInterval, nil
+}
+
+// drainNode evicts the pods of the node except the DaemonSet and static pods, the pods which are not managed by a controller
+// are only evicted with force, it returns the number of pods which are not gone yet
+func (dcrh *networkConfigReconcilerHelper) drainNode(ctx context.Context, nodeName string, policy *amdv1alpha1.DrainSpec) (int, error) {
+	pods := v1.PodList{}
+	if err := dcrh.client.List(ctx, &pods, client.MatchingFields{podNodeNameIndexKey: nodeName}); err != nil {
+		return 0, fmt.Errorf("failed to list pods of node %v: %v", nodeName, err)
+	}
+
+	force := policy != nil && policy.Force != nil && *policy.Force
+	pending := 0
+	var errs []error
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if _, ok := pod.Annotations[v1.MirrorPodAnnotationKey]; ok {
+			continue
+		}
+		owner := metav1.GetControllerOf(&pod)
+		if (owner != nil && owner.Kind == "DaemonSet") || (owner == nil && !force) {
+			continue
+		}
+		pending++
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name},
+		}
+		if policy != nil && policy.GracePeriodSeconds >= 0 {
+			gracePeriod := int64(policy.GracePeriodSeconds)
+			eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod}
+		}
+		if err := dcrh.client.SubResource("eviction").Create(ctx, &pod, eviction); err != nil {
+			if k8serrors.IsNotFound(err) {
+				pending--
+				continue
+			}
+			errs = append(errs, fmt.Errorf("failed to evict pod %v/%v: %v", pod.Namespace, pod.Name, err))
+		}
+	}
+	return pending, errors.Join(errs...)
+}
+
+// restoreNode reverses the remediation steps applied on the node, the drained pods are rescheduled by their controllers
+func (dcrh *networkConfigReconcilerHelper) restoreNode(ctx context.Context, nodeName string, status *amdv1alpha1.NodeRemediationStatus) error {
+	node := &v1.Node{}
+	if err := dcrh.client.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if k8serrors.IsNotFound(err) {
+			status.Tainted, status.Cordoned = false, false
+			return nil
+		}
+		return fmt.Errorf("failed to get node %v: %v", nodeName, err)
+	}
+	nodeCopy := node.DeepCopy()
+	taints := []v1.Taint{}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key != utils.NICUnhealthyTaintKey {
+			taints = append(taints, taint)
+		}
+	}
+	if len(taints) != len(node.Spec.Taints) {
+		node.Spec.Taints = taints
+	}
+	if status.Cordoned {
+		node.Spec.Unschedulable = false
+	}
+	if !equality.Semantic.DeepEqual(nodeCopy.Spec, node.Spec) {
+		if err := dcrh.client.Patch(ctx, node, client.MergeFrom(nodeCopy)); err != nil {
+			return fmt.Errorf("failed to reverse the remediation of node %v: %v", nodeName, err)
+		}
+	}
+	status.Tainted, status.Cordoned = false, false
+	return nil
+}
+
+// closeNodeRemediation reverses the remediation steps applied on the node and deletes its NodeRemediation
+func (dcrh *networkConfigReconcilerHelper) closeNodeRemediation(ctx context.Context, record *amdv1alpha1.NodeRemediation) error {
+	logger := log.FromContext(ctx)
+	if record.Status.Tainted || record.Status.Cordoned {
+		if err := dcrh.restoreNode(ctx, record.Spec.NodeName, record.Status.DeepCopy()); err != nil {
+			return err
+		}
+	}
+	logger.Info("deleting NodeRemediation", "namespace", record.Namespace, "name", record.Name)
+	if err := dcrh.client.Delete(ctx, record); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete NodeRemediation %s: %v", record.Name, err)
+	}
+	return nil
+}
+
+// finalizeNodeRemediation closes all the NodeRemediations of the NetworkConfig
+func (dcrh *networkConfigReconcilerHelper) finalizeNodeRemediation(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) error {
+	records := amdv1alpha1.NodeRemediationList{}
+	if err := dcrh.client.List(ctx, &records, client.InNamespace(nwConfig.Namespace), client.MatchingLabels{utils.CRNameLabel: nwConfig.Name}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list NodeRemediations: %v", err)
+	}
+	for _, record := range records.Items {
+		if err := dcrh.closeNodeRemediation(ctx, &record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
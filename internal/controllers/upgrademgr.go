@@ -44,6 +44,7 @@ import (
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
 
 	utils "github.com/ROCm/network-operator/internal"
+	"github.com/ROCm/network-operator/internal/kmmmodule"
 	"github.com/ROCm/network-operator/internal/workermgr"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -69,6 +70,7 @@ const (
 	defaultSAName              = "amd-network-operator-utils-container"
 	driverUpgradeStateLabelKey = "operator.amd.com/network-driver-upgrade-state"
 	upgradeRequiredLabelValue  = "upgrade-required"
+	driverUpgradeTaintKey      = "amd-network-driver-upgrade"
 )
 
 var (
@@ -216,7 +218,19 @@ func (n *upgradeMgr) HandleUpgrade(ctx context.Context, networkConfig *amdv1alph
 		}
 
 		// 6. Handle Completed nodes
-		if n.helper.isNodeReady(ctx, &nodeList.Items[i], networkConfig) {
+		ready, err := n.helper.isNodeReady(ctx, &nodeList.Items[i], networkConfig)
+		if err != nil {
+			if networkConfig.Status.NodeModuleStatus[nodeList.Items[i].Name].Reason != "" {
+				// the unsupported nodes skipped by the driver rollout have nothing to upgrade
+				upgradeDone++
+				continue
+			}
+			// the driver version can't be resolved, retry later instead of acting on the node
+			log.FromContext(ctx).Error(err, fmt.Sprintf("Node: %v. Failed to get the driver version", nodeList.Items[i].Name))
+			res = ctrl.Result{Requeue: true, RequeueAfter: time.Second * 20}
+			continue
+		}
+		if ready {
 			n.helper.clearUpgradeStartTime(nodeList.Items[i].Name)
 			upgradeDone++
 			continue
@@ -316,7 +330,7 @@ type upgradeMgrHelperAPI interface {
 	handleInitStatus(ctx context.Context, node *v1.Node, networkConfig *amdv1alpha1.NetworkConfig)
 
 	// Handle node state transitions
-	isNodeReady(ctx context.Context, node *v1.Node, networkConfig *amdv1alpha1.NetworkConfig) bool
+	isNodeReady(ctx context.Context, node *v1.Node, networkConfig *amdv1alpha1.NetworkConfig) (bool, error)
 	isNodeNmcStatusMissing(ctx context.Context, node *v1.Node, networkConfig *amdv1alpha1.NetworkConfig) bool
 	isNodeNew(ctx context.Context, node *v1.Node, networkConfig *amdv1alpha1.NetworkConfig) bool
 	isNodeStateUpgradeStarted(node *v1.Node) bool
@@ -534,13 +548,25 @@ func (h *upgradeMgrHelper) isNodeNmcStatusMissing(ctx context.Context, node *v1.
 	return false
 }
 
+// getDriverVersion returns the driver version from NetworkConfig or the default driver version of the node OS profile
+func (h *upgradeMgrHelper) getDriverVersion(ctx context.Context, node v1.Node, networkConfig *amdv1alpha1.NetworkConfig) (string, error) {
+	profiles, err := kmmmodule.LoadOSProfiles(ctx, h.client, networkConfig)
+	if err != nil {
+		return "", err
+	}
+	return profiles.GetDriverVersion(node, *networkConfig)
+}
+
 // Handle Driver installation for ready nodes.
-func (h *upgradeMgrHelper) isNodeReady(ctx context.Context, node *v1.Node, networkConfig *amdv1alpha1.NetworkConfig) bool {
+func (h *upgradeMgrHelper) isNodeReady(ctx context.Context, node *v1.Node, networkConfig *amdv1alpha1.NetworkConfig) (bool, error) {
 
 	// Move the node state to complete if the driver install is done
 	if nodeStatus, ok := networkConfig.Status.NodeModuleStatus[node.Name]; ok {
 		// If driver install is done but CR version not specified, get default version
-		driverVersion, _ := utils.GetDriverVersion(*node, *networkConfig)
+		driverVersion, err := h.getDriverVersion(ctx, *node, networkConfig)
+		if err != nil {
+			return false, err
+		}
 
 		if strings.HasSuffix(nodeStatus.ContainerImage, driverVersion) {
 
@@ -548,7 +574,7 @@ func (h *upgradeMgrHelper) isNodeReady(ctx context.Context, node *v1.Node, netwo
 
 			// Return if the node is already taken care
 			if currentState == amdv1alpha1.UpgradeStateComplete || currentState == amdv1alpha1.UpgradeStateInstallComplete {
-				return true
+				return true, nil
 			}
 
 			// load back amdgpu
@@ -558,7 +584,7 @@ func (h *upgradeMgrHelper) isNodeReady(ctx context.Context, node *v1.Node, netwo
 					log.FromContext(ctx).Error(err, fmt.Sprintf("Node: %v. Failed to do AMDGPU driver load with error: %v", node.Name, err))
 					// Move to failure state if loading amdgpu fails
 					h.setNodeStatus(ctx, node.Name, amdv1alpha1.UpgradeStateFailed)
-					return false
+					return false, nil
 				}
 			}
 
@@ -567,7 +593,7 @@ func (h *upgradeMgrHelper) isNodeReady(ctx context.Context, node *v1.Node, netwo
 				log.FromContext(ctx).Error(err, fmt.Sprintf("Node: %v. Failed to remove worker pod node label with error: %v", node.Name, err))
 				// Move to failure state if removing worker pod node label fails
 				h.setNodeStatus(ctx, node.Name, amdv1alpha1.UpgradeStateFailed)
-				return false
+				return false, nil
 			}
 
 			// Uncordon the node
@@ -576,7 +602,7 @@ func (h *upgradeMgrHelper) isNodeReady(ctx context.Context, node *v1.Node, netwo
 			if err := h.cordonOrUncordonNode(ctx, networkConfig, node, false); err != nil {
 				// Move to failure state if uncordon fails
 				h.setNodeStatus(ctx, node.Name, amdv1alpha1.UpgradeStateUncordonFailed)
-				return false
+				return false, nil
 			}
 
 			// Set InstallComplete/UpgradeComplete
@@ -586,11 +612,11 @@ func (h *upgradeMgrHelper) isNodeReady(ctx context.Context, node *v1.Node, netwo
 				h.setNodeStatus(ctx, node.Name, amdv1alpha1.UpgradeStateComplete)
 			}
 
-			return true
+			return true, nil
 		}
 	}
 
-	return false
+	return false, nil
 }
 
 // Handle Driver installation for reboot pending nodes (new).
@@ -853,13 +879,8 @@ func (h *upgradeMgrHelper) deleteOrDrainPods(ctx context.Context, networkConfig
 }
 
 func (h *upgradeMgrHelper) getPodsToDrainOrDelete(ctx context.Context, networkConfig *amdv1alpha1.NetworkConfig, node *v1.Node) (newPods []v1.Pod, err error) {
-
-	options := metav1.ListOptions{
-		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": node.Name}).String(),
-	}
-	pods, err := h.k8sInterface.CoreV1().Pods(metav1.NamespaceAll).List(ctx, options)
-
-	if err != nil {
+	pods := v1.PodList{}
+	if err := h.client.List(ctx, &pods, client.MatchingFields{podNodeNameIndexKey: node.Name}); err != nil {
 		return nil, err
 	}
 
@@ -1233,7 +1254,7 @@ func (h *upgradeMgrHelper) addOrRemoveTaintToNode(ctx context.Context, networkCo
 
 	logger := log.FromContext(ctx)
 	upgradeTaint := v1.Taint{
-		Key:    "amd-network-driver-upgrade",
+		Key:    driverUpgradeTaintKey,
 		Value:  "true",
 		Effect: v1.TaintEffectNoSchedule,
 	}
@@ -1301,7 +1322,7 @@ func (h *upgradeMgrHelper) updateModuleVersionOnNode(ctx context.Context, networ
 			return err
 		}
 		nodeObjCopy := nodeObj.DeepCopy()
-		driverVersion, err := utils.GetDriverVersion(*node, *networkConfig)
+		driverVersion, err := h.getDriverVersion(ctx, *node, networkConfig)
 		if err == nil {
 			nodeObj.Labels[fmt.Sprintf("kmm.node.kubernetes.io/version-module.%s.%s", networkConfig.Namespace, networkConfig.Name)] = driverVersion
 		}
@@ -1679,7 +1700,7 @@ func (h *upgradeMgrHelper) getRebootPod(nodeName string, nc *amdv1alpha1.Network
 			},
 			Tolerations: []v1.Toleration{
 				{
-					Key:      "amd-network-driver-upgrade",
+					Key:      driverUpgradeTaintKey,
 					Value:    "true",
 					Operator: v1.TolerationOpEqual,
 					Effect:   v1.TaintEffectNoSchedule,
@@ -1691,6 +1712,8 @@ func (h *upgradeMgrHelper) getRebootPod(nodeName string, nc *amdv1alpha1.Network
 	if nc.Spec.CommonConfig.UtilsContainer.ImagePullPolicy != "" {
 		rebootPod.Spec.Containers[0].ImagePullPolicy = v1.PullPolicy(nc.Spec.CommonConfig.UtilsContainer.ImagePullPolicy)
 	}
+	utils.ApplyPodCustomization(&rebootPod.ObjectMeta, &rebootPod.Spec,
+		utils.GetPodCustomization(nc, nc.Spec.CommonConfig.UtilsContainer.PodCustomization))
 
 	return rebootPod
 }
@@ -129,11 +129,11 @@ func (h *PodEventHandler) Update(
 
 	// if the pod is workerMgr pod, do proper handling based on pod state
 	if action, ok := pod.Labels[utils.WorkerActionLabelKey]; ok {
-		h.handleWorkerMgrPodEvt(ctx, logger, pod, action)
+		h.handleWorkerMgrPodEvt(ctx, logger, pod, action, q)
 	}
 }
 
-func (h *PodEventHandler) handleWorkerMgrPodEvt(ctx context.Context, logger logr.Logger, pod *v1.Pod, action string) {
+func (h *PodEventHandler) handleWorkerMgrPodEvt(ctx context.Context, logger logr.Logger, pod *v1.Pod, action string, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
 	foundNetworkConfigOwner := false
 	var nsn types.NamespacedName
 	for _, owner := range pod.OwnerReferences {
@@ -149,6 +149,14 @@ func (h *PodEventHandler) handleWorkerMgrPodEvt(ctx context.Context, logger logr
 		logger.Info(fmt.Sprintf("cannot find NetworkConfig owner for worker pod %+v", pod.GetObjectMeta()))
 		return
 	}
+	switch action {
+	case utils.VerifyKmodSignatureAction:
+		h.handleKmodSignatureVerifierPodEvt(ctx, logger, pod, nsn, q)
+		return
+	case utils.DiscoverTopologyAction:
+		h.handleTopologyDiscoveryPodEvt(ctx, logger, pod, nsn, q)
+		return
+	}
 	switch pod.Status.Phase {
 	case v1.PodSucceeded:
 		// if the worker pod already succeed
@@ -194,3 +202,44 @@ func hasExpectedPodLabel(obj metav1.Object) bool {
 	_, isWorkerMgrPod := labels[utils.WorkerActionLabelKey]
 	return isKMMBuilder || isWorkerMgrPod
 }
+
+func (h *PodEventHandler) handleKmodSignatureVerifierPodEvt(ctx context.Context, logger logr.Logger, pod *v1.Pod, nsn types.NamespacedName, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	switch pod.Status.Phase {
+	case v1.PodSucceeded, v1.PodFailed:
+		// save the verification result on the node no matter the verification passed or not
+		h.workerMgr.SetKmodSignatureResult(ctx, logger, nsn, pod)
+		logger.Info(fmt.Sprintf("remove kmod signature verifier pod %v after its completion", pod.Name))
+		err := h.client.Delete(ctx, pod)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("failed to delete completed kmod signature verifier pod %v", pod.Name))
+		}
+		// reconcile the NetworkConfig to reflect the verification result in status
+		q.Add(reconcile.Request{NamespacedName: nsn})
+	case v1.PodUnknown:
+		logger.Info(fmt.Sprintf("remove kmod signature verifier pod %v due to its %v status", pod.Name, pod.Status.Phase))
+		err := h.client.Delete(ctx, pod)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("failed to delete stale kmod signature verifier pod %v", pod.Name))
+		}
+	}
+}
+
+func (h *PodEventHandler) handleTopologyDiscoveryPodEvt(ctx context.Context, logger logr.Logger, pod *v1.Pod, nsn types.NamespacedName, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	switch pod.Status.Phase {
+	case v1.PodSucceeded, v1.PodFailed:
+		h.workerMgr.SetTopologyResult(ctx, logger, nsn, pod)
+		logger.Info(fmt.Sprintf("remove topology discovery pod %v after its completion", pod.Name))
+		err := h.client.Delete(ctx, pod)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("failed to delete completed topology discovery pod %v", pod.Name))
+		}
+		// reconcile the NetworkConfig in case the topology couldn't be published
+		q.Add(reconcile.Request{NamespacedName: nsn})
+	case v1.PodUnknown:
+		logger.Info(fmt.Sprintf("remove topology discovery pod %v due to its %v status", pod.Name, pod.Status.Phase))
+		err := h.client.Delete(ctx, pod)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("failed to delete stale topology discovery pod %v", pod.Name))
+		}
+	}
+}
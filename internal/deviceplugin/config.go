@@ -0,0 +1,153 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceplugininternal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DevicePluginConfigKey is the key of the device plugin config within the ConfigMap
+	DevicePluginConfigKey = "config.json"
+	// DevicePluginConfigHashAnnotation is set on the device plugin pod template to roll the pods when the config changes
+	DevicePluginConfigHashAnnotation = "network.operator.amd.com/device-plugin-config-hash"
+
+	defaultResourcePrefix = "amd.com"
+	amdVendorID           = "1dd8"
+)
+
+// devicePluginConfig is the config.json consumed by the device plugin
+type devicePluginConfig struct {
+	ResourceList []resourceConfig `json:"resourceList"`
+}
+
+type resourceConfig struct {
+	ResourceName              string            `json:"resourceName"`
+	ResourcePrefix            string            `json:"resourcePrefix"`
+	EnableExporterHealthCheck bool              `json:"enableExporterHealthCheck"`
+	Selectors                 resourceSelectors `json:"selectors"`
+	ExcludeTopology           bool              `json:"excludeTopology"`
+}
+
+type resourceSelectors struct {
+	Vendors          []string `json:"vendors,omitempty"`
+	Devices          []string `json:"devices,omitempty"`
+	SubsystemDevices []string `json:"subsystemDevices,omitempty"`
+	Drivers          []string `json:"drivers,omitempty"`
+	PFNames          []string `json:"pfNames,omitempty"`
+	IsRdma           bool     `json:"isRdma,omitempty"`
+}
+
+// defaultResourcePools are the AMD NIC PF and VF pools advertised when the NetworkConfig doesn't specify any
+func defaultResourcePools() []amdv1alpha1.ResourcePoolSpec {
+	return []amdv1alpha1.ResourcePoolSpec{
+		{
+			Name: "nic",
+			Selectors: amdv1alpha1.ResourcePoolSelectors{
+				Vendors: []string{amdVendorID},
+				Devices: []string{"1002"},
+				Drivers: []string{"ionic"},
+				IsRdma:  true,
+			},
+		},
+		{
+			Name: "vnic",
+			Selectors: amdv1alpha1.ResourcePoolSelectors{
+				Vendors: []string{amdVendorID},
+				Devices: []string{"1003"},
+				Drivers: []string{"ionic"},
+				IsRdma:  true,
+			},
+		},
+	}
+}
+
+// GetDevicePluginConfigMapName returns the name of the device plugin ConfigMap rendered for the NetworkConfig
+func GetDevicePluginConfigMapName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s-config", nwConfig.Name, DevicePluginName)
+}
+
+// getResourcePools returns the resource pools of the NetworkConfig, or the default ones if it doesn't specify any
+func getResourcePools(nwConfig *amdv1alpha1.NetworkConfig) []amdv1alpha1.ResourcePoolSpec {
+	if len(nwConfig.Spec.DevicePlugin.ResourcePools) == 0 {
+		return defaultResourcePools()
+	}
+	return nwConfig.Spec.DevicePlugin.ResourcePools
+}
+
+// getPoolResourcePrefix returns the resource prefix of the pool, falling back to the device plugin resource prefix
+func getPoolResourcePrefix(nwConfig *amdv1alpha1.NetworkConfig, pool amdv1alpha1.ResourcePoolSpec) string {
+	if pool.Prefix != "" {
+		return pool.Prefix
+	}
+	return GetResourcePrefix(nwConfig)
+}
+
+// GetResourceNames returns the names of the extended resources advertised by the device plugin for the NetworkConfig
+func GetResourceNames(nwConfig *amdv1alpha1.NetworkConfig) []v1.ResourceName {
+	names := []v1.ResourceName{}
+	for _, pool := range getResourcePools(nwConfig) {
+		names = append(names, v1.ResourceName(getPoolResourcePrefix(nwConfig, pool)+"/"+pool.Name))
+	}
+	return names
+}
+
+// GenerateDevicePluginConfig renders the device plugin config.json from the NetworkConfig resource pools
+func GenerateDevicePluginConfig(nwConfig *amdv1alpha1.NetworkConfig) (string, error) {
+	config := devicePluginConfig{ResourceList: []resourceConfig{}}
+	for _, pool := range getResourcePools(nwConfig) {
+		config.ResourceList = append(config.ResourceList, resourceConfig{
+			ResourceName:              pool.Name,
+			ResourcePrefix:            getPoolResourcePrefix(nwConfig, pool),
+			EnableExporterHealthCheck: pool.EnableExporterHealthCheck == nil || *pool.EnableExporterHealthCheck,
+			Selectors: resourceSelectors{
+				Vendors:          pool.Selectors.Vendors,
+				Devices:          pool.Selectors.Devices,
+				SubsystemDevices: pool.Selectors.SubsystemDevices,
+				Drivers:          pool.Selectors.Drivers,
+				PFNames:          pool.Selectors.PFNames,
+				IsRdma:           pool.Selectors.IsRdma,
+			},
+			ExcludeTopology: pool.ExcludeTopology,
+		})
+	}
+	configBytes, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal device plugin config: %v", err)
+	}
+	return string(configBytes), nil
+}
+
+// SetDevicePluginConfigMapAsDesired renders the device plugin config into the ConfigMap
+// and returns the config hash to be set on the device plugin pod template
+func SetDevicePluginConfigMapAsDesired(cm *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig) (string, error) {
+	config, err := GenerateDevicePluginConfig(nwConfig)
+	if err != nil {
+		return "", err
+	}
+	cm.Data = map[string]string{
+		DevicePluginConfigKey: config,
+	}
+	hash := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -26,14 +26,17 @@ import (
 	protos "github.com/ROCm/common-infra-operator/pkg/protos"
 	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
 	utils "github.com/ROCm/network-operator/internal"
+	"github.com/ROCm/network-operator/internal/topology"
 )
 
 const (
-	defaultInitContainerImage    = "busybox:1.36"
-	defaultDevicePluginImage     = "docker.io/rocm/k8s-network-device-plugin:v1.2.0"
-	defaultDevicePluginConfigMap = "amd-network-operator-device-plugin-config"
-	devicePluginSAName           = "amd-network-operator-device-plugin"
-	DevicePluginName             = "device-plugin"
+	defaultInitContainerImage = "busybox:1.36"
+	defaultDevicePluginImage  = "docker.io/rocm/k8s-network-device-plugin:v1.2.0"
+	devicePluginSAName        = "amd-network-operator-device-plugin"
+	DevicePluginName          = "device-plugin"
+	topologyMountPath         = "/etc/amd-network/topology"
+	// DefaultMultusWaitTimeoutSeconds is how long the init container waits for the Multus CNI config by default
+	DefaultMultusWaitTimeoutSeconds = 600
 )
 
 // buildMultusCheckCommand returns a shell command that checks if Multus config exists.
@@ -45,6 +48,32 @@ func buildMultusCheckCommand(isOpenShift bool) string {
 	return "( ! ls /host/etc/cni/net.d/*multus*.conf >/dev/null 2>&1 && ! ls /host/etc/cni/net.d/*multus*.conflist >/dev/null 2>&1 )"
 }
 
+// GetMultusWaitTimeoutSeconds returns how long the device plugin init container waits for the Multus CNI config, 0 waits forever
+func GetMultusWaitTimeoutSeconds(nwConfig *amdv1alpha1.NetworkConfig) int32 {
+	multus := nwConfig.Spec.SecondaryNetwork.Multus
+	if multus != nil && multus.WaitTimeoutSeconds != nil {
+		return *multus.WaitTimeoutSeconds
+	}
+	return DefaultMultusWaitTimeoutSeconds
+}
+
+// buildMultusWaitCommand returns a shell script that waits for the Multus CNI config,
+// on timeout the reason is written to the termination log and the init container fails so that the pod reports it
+func buildMultusWaitCommand(isOpenShift bool, timeoutSeconds int32) string {
+	return fmt.Sprintf(`waited=0
+			while %s; do
+				if [ %d -gt 0 ] && [ $waited -ge %d ]; then
+					msg="Multus CNI config not found after %ds, install Multus or set spec.secondaryNetwork.multus.enable to let the operator deploy it"
+					echo "$msg"
+					echo "$msg" > /dev/termination-log
+					exit 1
+				fi
+				echo "Waiting for Multus CNI config to be present"
+				sleep 2
+				waited=$((waited+2))
+			done`, buildMultusCheckCommand(isOpenShift), timeoutSeconds, timeoutSeconds, timeoutSeconds)
+}
+
 func GenerateCommonDevicePluginSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenShift bool) *protos.DevicePluginSpec {
 	var dpOut protos.DevicePluginSpec
 	specIn := &nwConfig.Spec.DevicePlugin
@@ -54,7 +83,7 @@ func GenerateCommonDevicePluginSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenS
 	dpOut.Namespace = nwConfig.Namespace
 	dpOut.Enable = nwConfig.Spec.Driver.Enable
 	dpOut.ServiceAccountName = devicePluginSAName
-	dpOut.Tolerations = specIn.DevicePluginTolerations
+	dpOut.Tolerations = utils.WithNICUnhealthyToleration(specIn.DevicePluginTolerations)
 	dpOut.UpgradePolicy = (*protos.DaemonSetUpgradeSpec)(specIn.UpgradePolicy.DeepCopy())
 	dpOut.Selector = nwConfig.Spec.Selector
 	dpOut.Labels = map[string]string{
@@ -87,27 +116,21 @@ func GenerateCommonDevicePluginSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenS
 		},
 	}
 
-	multusCheck := buildMultusCheckCommand(isOpenShift)
+	multusWait := buildMultusWaitCommand(isOpenShift, GetMultusWaitTimeoutSeconds(nwConfig))
 
 	if !simEnabled {
 		initContainer.Command = []string{
 			"sh", "-c",
 			fmt.Sprintf(`while [ ! -d /sys/class/infiniband ] ||
 				[ ! -d /sys/class/infiniband_verbs ] ||
-				[ ! -d /sys/module/ionic/drivers ] ||
-				%s; do
-					echo "Waiting for AMD ionic driver and Multus CNI config to be ready"
+				[ ! -d /sys/module/ionic/drivers ]; do
+					echo "Waiting for AMD ionic driver to be ready"
 					sleep 2
-			done`, multusCheck),
+			done
+			%s`, multusWait),
 		}
 	} else {
-		initContainer.Command = []string{
-			"sh", "-c",
-			fmt.Sprintf(`while %s; do
-				echo "Waiting for Multus CNI config to be present"
-				sleep 2
-			done`, multusCheck),
-		}
+		initContainer.Command = []string{"sh", "-c", multusWait}
 	}
 
 	dpOut.InitContainers = []protos.InitContainerSpec{initContainer}
@@ -119,12 +142,9 @@ func GenerateCommonDevicePluginSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenS
 	dpOut.MainContainer.IsPrivileged = true
 	dpOut.MainContainer.IsHostNetwork = true
 	dpOut.MainContainer.Command = []string{}
-
-	var commandArgs string
-	for key, val := range specIn.DevicePluginArguments {
-		commandArgs += " -" + key + "=" + val
-	}
-	//dpOut.MainContainer.Command = []string{"sh", "-c", commandArgs}
+	// keep the image entrypoint and only pass the flags as arguments
+	dpOut.MainContainer.Arguments = GetDevicePluginArgs(nwConfig)
+	kubeletSocketDir := getKubeletSocketDir(nwConfig)
 
 	hostPathDirectory := v1.HostPathDirectory
 	hostPathDirectoryOrCreate := v1.HostPathDirectoryOrCreate
@@ -141,7 +161,7 @@ func GenerateCommonDevicePluginSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenS
 	dpOut.MainContainer.VolumeMounts = []v1.VolumeMount{
 		{
 			Name:      "kubelet-device-plugins",
-			MountPath: "/var/lib/kubelet/device-plugins",
+			MountPath: kubeletSocketDir,
 			ReadOnly:  false,
 		},
 		{
@@ -184,7 +204,7 @@ func GenerateCommonDevicePluginSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenS
 			Name: "kubelet-device-plugins",
 			VolumeSource: v1.VolumeSource{
 				HostPath: &v1.HostPathVolumeSource{
-					Path: "/var/lib/kubelet/device-plugins",
+					Path: kubeletSocketDir,
 					Type: &hostPathDirectory,
 				},
 			},
@@ -221,12 +241,7 @@ func GenerateCommonDevicePluginSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenS
 			VolumeSource: v1.VolumeSource{
 				ConfigMap: &v1.ConfigMapVolumeSource{
 					LocalObjectReference: v1.LocalObjectReference{
-						Name: func() string {
-							if name := os.Getenv("DEVICE_PLUGIN_CONFIG_MAP_NAME"); name != "" {
-								return name
-							}
-							return defaultDevicePluginConfigMap
-						}(),
+						Name: GetDevicePluginConfigMapName(nwConfig),
 					},
 				},
 			},
@@ -270,5 +285,23 @@ func GenerateCommonDevicePluginSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenS
 			},
 		})
 	}
+	if IsGPUAffinityEnabled(nwConfig) {
+		// the device plugin reads the topology of its node from the <node name> key
+		dpOut.MainContainer.VolumeMounts = append(dpOut.MainContainer.VolumeMounts, v1.VolumeMount{
+			Name:      "nic-gpu-topology",
+			MountPath: topologyMountPath,
+			ReadOnly:  true,
+		})
+		dpOut.Volumes = append(dpOut.Volumes, v1.Volume{
+			Name: "nic-gpu-topology",
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: topology.GetTopologyConfigMapName(nwConfig.Name),
+					},
+				},
+			},
+		})
+	}
 	return &dpOut
 }
@@ -0,0 +1,226 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceplugininternal
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+	"github.com/ROCm/network-operator/internal/topology"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	defaultKubeletSocketPath = "/var/lib/kubelet/device-plugins/kubelet.sock"
+)
+
+// devicePluginFlag describes a flag supported by the device plugin image
+type devicePluginFlag struct {
+	// first device plugin release supporting the flag
+	minVersion [3]int
+	validate   func(value string) error
+}
+
+var imageVersionRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)`)
+
+// supportedDevicePluginFlags are the flags accepted by the device plugin images
+var supportedDevicePluginFlags = map[string]devicePluginFlag{
+	utils.ResourceNamingStrategyFlag: {
+		minVersion: [3]int{1, 0, 0},
+		validate:   oneOf(utils.SingleStrategy, utils.MixedStrategy),
+	},
+	utils.LogLevelFlag: {
+		minVersion: [3]int{1, 2, 0},
+		validate:   oneOf("debug", "info", "warn", "error"),
+	},
+	utils.ResourcePrefixFlag: {
+		minVersion: [3]int{1, 2, 0},
+		validate: func(value string) error {
+			if errs := validation.IsDNS1123Subdomain(value); len(errs) > 0 {
+				return fmt.Errorf("%v", errs)
+			}
+			return nil
+		},
+	},
+	utils.HealthCheckIntervalFlag: {
+		minVersion: [3]int{1, 2, 0},
+		validate: func(value string) error {
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return fmt.Errorf("must be a positive number of seconds")
+			}
+			return nil
+		},
+	},
+	utils.GPUAffinityFlag: {
+		minVersion: [3]int{1, 3, 0},
+		validate:   oneOf(topology.AffinityNUMA, topology.AffinityPCIeSwitch),
+	},
+	utils.KubeletSocketFlag: {
+		minVersion: [3]int{1, 2, 0},
+		validate: func(value string) error {
+			if !path.IsAbs(value) || !strings.HasSuffix(value, ".sock") {
+				return fmt.Errorf("must be an absolute path to a .sock file")
+			}
+			return nil
+		},
+	},
+}
+
+func oneOf(values ...string) func(value string) error {
+	return func(value string) error {
+		if !slices.Contains(values, value) {
+			return fmt.Errorf("supported values: %v", values)
+		}
+		return nil
+	}
+}
+
+// GetDevicePluginFlags merges the typed device plugin flags with the legacy device plugin arguments,
+// the typed flags take precedence
+func GetDevicePluginFlags(nwConfig *amdv1alpha1.NetworkConfig) map[string]string {
+	flags := map[string]string{}
+	for key, val := range nwConfig.Spec.DevicePlugin.DevicePluginArguments {
+		flags[key] = val
+	}
+	typed := nwConfig.Spec.DevicePlugin.DevicePluginFlags
+	if typed.ResourceNamingStrategy != "" {
+		flags[utils.ResourceNamingStrategyFlag] = typed.ResourceNamingStrategy
+	}
+	if typed.LogLevel != "" {
+		flags[utils.LogLevelFlag] = typed.LogLevel
+	}
+	if typed.ResourcePrefix != "" {
+		flags[utils.ResourcePrefixFlag] = typed.ResourcePrefix
+	}
+	if typed.HealthCheckIntervalSeconds > 0 {
+		flags[utils.HealthCheckIntervalFlag] = strconv.Itoa(int(typed.HealthCheckIntervalSeconds))
+	}
+	if typed.KubeletSocketPath != "" {
+		flags[utils.KubeletSocketFlag] = typed.KubeletSocketPath
+	}
+	if IsGPUAffinityEnabled(nwConfig) {
+		if policy := nwConfig.Spec.DevicePlugin.GPUAffinity.AllocationPolicy; policy != "" && policy != topology.AffinityNone {
+			flags[utils.GPUAffinityFlag] = policy
+		}
+	}
+	return flags
+}
+
+// IsGPUAffinityEnabled returns true if the NIC to GPU topology is discovered for the NetworkConfig nodes
+func IsGPUAffinityEnabled(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	return nwConfig.Spec.DevicePlugin.GPUAffinity.Enable != nil && *nwConfig.Spec.DevicePlugin.GPUAffinity.Enable
+}
+
+// GetDevicePluginArgs renders the device plugin flags as container arguments, sorted to keep the DaemonSet stable
+func GetDevicePluginArgs(nwConfig *amdv1alpha1.NetworkConfig) []string {
+	flags := GetDevicePluginFlags(nwConfig)
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	args := []string{}
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf("-%s=%s", key, flags[key]))
+	}
+	return args
+}
+
+// GetResourcePrefix returns the resource prefix of the resource pools which don't specify a prefix
+func GetResourcePrefix(nwConfig *amdv1alpha1.NetworkConfig) string {
+	if prefix, ok := GetDevicePluginFlags(nwConfig)[utils.ResourcePrefixFlag]; ok && prefix != "" {
+		return prefix
+	}
+	return defaultResourcePrefix
+}
+
+// getKubeletSocketDir returns the host directory of the kubelet device plugin socket
+func getKubeletSocketDir(nwConfig *amdv1alpha1.NetworkConfig) string {
+	socketPath, ok := GetDevicePluginFlags(nwConfig)[utils.KubeletSocketFlag]
+	if !ok || socketPath == "" {
+		socketPath = defaultKubeletSocketPath
+	}
+	return path.Dir(socketPath)
+}
+
+// ValidateDevicePluginFlags validates the device plugin flags against the flags supported by the device plugin image
+func ValidateDevicePluginFlags(nwConfig *amdv1alpha1.NetworkConfig) error {
+	image := nwConfig.Spec.DevicePlugin.DevicePluginImage
+	if image == "" {
+		image = defaultDevicePluginImage
+	}
+	imageVersion, versioned := getImageVersion(image)
+
+	flags := GetDevicePluginFlags(nwConfig)
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		flag, ok := supportedDevicePluginFlags[key]
+		if !ok {
+			return fmt.Errorf("Invalid flag: %s", key)
+		}
+		if err := flag.validate(flags[key]); err != nil {
+			return fmt.Errorf("Invalid flag value: %s=%s: %v", key, flags[key], err)
+		}
+		// images without a release tag, e.g. latest or digests, are assumed to support all the flags
+		if versioned && compareVersions(imageVersion, flag.minVersion) < 0 {
+			return fmt.Errorf("flag %s is not supported by device plugin image %s, requires v%d.%d.%d or later",
+				key, image, flag.minVersion[0], flag.minVersion[1], flag.minVersion[2])
+		}
+	}
+	return nil
+}
+
+// getImageVersion parses the release version from the image tag
+func getImageVersion(image string) ([3]int, bool) {
+	var version [3]int
+	if strings.Contains(image, "@") {
+		return version, false
+	}
+	idx := strings.LastIndex(image, ":")
+	if idx < 0 || strings.Contains(image[idx:], "/") {
+		return version, false
+	}
+	match := imageVersionRegex.FindStringSubmatch(image[idx+1:])
+	if match == nil {
+		return version, false
+	}
+	for i := range version {
+		version[i], _ = strconv.Atoi(match[i+1])
+	}
+	return version, true
+}
+
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return 0
+}
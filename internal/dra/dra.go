@@ -0,0 +1,327 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drainternal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+)
+
+const (
+	// DriverName is the name of the DRA driver in the ResourceSlices and the device attributes domain
+	DriverName = "nic.amd.com"
+	// DRADriverNameSuffix is the suffix of the DRA driver DaemonSet name
+	DRADriverNameSuffix = "dra-driver"
+	// DRADriverConfigKey is the key of the DRA driver config within the ConfigMap
+	DRADriverConfigKey = "config.json"
+	// DRADriverConfigHashAnnotation is set on the DRA driver pod template to roll the pods when the config changes
+	DRADriverConfigHashAnnotation = "network.operator.amd.com/dra-driver-config-hash"
+
+	defaultDRADriverImage = "docker.io/rocm/k8s-network-dra-driver:v1.0.0"
+	draDriverSAName       = "amd-network-operator-dra-driver"
+	draDriverConfigPath   = "/etc/amd-network/dra"
+	kubeletPluginsDir     = "/var/lib/kubelet/plugins"
+	kubeletRegistryDir    = "/var/lib/kubelet/plugins_registry"
+	cdiDir                = "/var/run/cdi"
+)
+
+// SupportedAttributes are the device attributes the DRA driver can publish besides the function type
+var SupportedAttributes = []string{"pciAddress", "pfName", "numaNode", "linkSpeed", "firmwareVersion", "rail"}
+
+// draDriverConfig is the config.json consumed by the DRA driver
+type draDriverConfig struct {
+	DriverName string          `json:"driverName"`
+	Attributes []string        `json:"attributes"`
+	Rails      []draDriverRail `json:"rails"`
+}
+
+type draDriverRail struct {
+	Name    string   `json:"name"`
+	PFNames []string `json:"pfNames"`
+}
+
+// IsDRADriverEnabled returns true if the DRA driver is deployed for the NetworkConfig
+func IsDRADriverEnabled(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	return nwConfig.Spec.DRADriver.Enable != nil && *nwConfig.Spec.DRADriver.Enable
+}
+
+// GetDRADriverName returns the name of the DRA driver DaemonSet of the NetworkConfig
+func GetDRADriverName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s", nwConfig.Name, DRADriverNameSuffix)
+}
+
+// GetDRADriverConfigMapName returns the name of the DRA driver ConfigMap rendered for the NetworkConfig
+func GetDRADriverConfigMapName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-config", GetDRADriverName(nwConfig))
+}
+
+// GetDeviceClasses returns the DeviceClasses managed for the NetworkConfig
+func GetDeviceClasses(nwConfig *amdv1alpha1.NetworkConfig) []amdv1alpha1.DeviceClassSpec {
+	if len(nwConfig.Spec.DRADriver.DeviceClasses) > 0 {
+		return nwConfig.Spec.DRADriver.DeviceClasses
+	}
+	return []amdv1alpha1.DeviceClassSpec{
+		{
+			Name:      "nic.amd.com",
+			Selectors: []string{fmt.Sprintf(`device.attributes["%s"].type == "pf"`, DriverName)},
+		},
+		{
+			Name:      "vnic.amd.com",
+			Selectors: []string{fmt.Sprintf(`device.attributes["%s"].type == "vf"`, DriverName)},
+		},
+	}
+}
+
+// SetDeviceClassAsDesired renders the DeviceClass, the devices are always restricted to the AMD NIC DRA driver
+func SetDeviceClassAsDesired(dc *resourcev1beta1.DeviceClass, classSpec amdv1alpha1.DeviceClassSpec, nwConfig *amdv1alpha1.NetworkConfig) {
+	if dc.Labels == nil {
+		dc.Labels = map[string]string{}
+	}
+	for key, val := range utils.GetOwnerLabels(nwConfig) {
+		dc.Labels[key] = val
+	}
+	selectors := []resourcev1beta1.DeviceSelector{
+		{
+			CEL: &resourcev1beta1.CELDeviceSelector{Expression: fmt.Sprintf(`device.driver == "%s"`, DriverName)},
+		},
+	}
+	for _, expression := range classSpec.Selectors {
+		selectors = append(selectors, resourcev1beta1.DeviceSelector{
+			CEL: &resourcev1beta1.CELDeviceSelector{Expression: expression},
+		})
+	}
+	dc.Spec.Selectors = selectors
+}
+
+// GenerateDRADriverConfig renders the DRA driver config.json from the NetworkConfig
+func GenerateDRADriverConfig(nwConfig *amdv1alpha1.NetworkConfig) (string, error) {
+	spec := nwConfig.Spec.DRADriver
+	config := draDriverConfig{
+		DriverName: DriverName,
+		Attributes: SupportedAttributes,
+		Rails:      []draDriverRail{},
+	}
+	if len(spec.Attributes) > 0 {
+		config.Attributes = spec.Attributes
+	}
+	for _, rail := range spec.Rails {
+		config.Rails = append(config.Rails, draDriverRail{Name: rail.Name, PFNames: rail.PFNames})
+	}
+	configBytes, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal DRA driver config: %v", err)
+	}
+	return string(configBytes), nil
+}
+
+// SetDRADriverConfigMapAsDesired renders the DRA driver config into the ConfigMap
+// and returns the config hash to be set on the DRA driver pod template
+func SetDRADriverConfigMapAsDesired(cm *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig) (string, error) {
+	config, err := GenerateDRADriverConfig(nwConfig)
+	if err != nil {
+		return "", err
+	}
+	cm.Data = map[string]string{
+		DRADriverConfigKey: config,
+	}
+	hash := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// SetDRADriverAsDesired renders the DRA kubelet plugin DaemonSet
+func SetDRADriverAsDesired(ds *appsv1.DaemonSet, nwConfig *amdv1alpha1.NetworkConfig, configHash string) error {
+	if ds == nil {
+		return fmt.Errorf("daemon set is not initialized, zero pointer")
+	}
+	spec := nwConfig.Spec.DRADriver
+
+	image := defaultDRADriverImage
+	if spec.Image != "" {
+		image = spec.Image
+	}
+
+	matchLabels := map[string]string{
+		"daemonset-name":         ds.Name,
+		"app.kubernetes.io/name": DRADriverNameSuffix,
+		utils.CRNameLabel:        nwConfig.Name,
+	}
+
+	nodeSelector := map[string]string{}
+	for key, val := range nwConfig.Spec.Selector {
+		nodeSelector[key] = val
+	}
+
+	hostPathDirectory := v1.HostPathDirectory
+	hostPathDirectoryOrCreate := v1.HostPathDirectoryOrCreate
+	volumes := []v1.Volume{
+		{
+			Name: "kubelet-plugins",
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: kubeletPluginsDir,
+					Type: &hostPathDirectoryOrCreate,
+				},
+			},
+		},
+		{
+			Name: "kubelet-plugins-registry",
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: kubeletRegistryDir,
+					Type: &hostPathDirectory,
+				},
+			},
+		},
+		{
+			Name: "cdi",
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: cdiDir,
+					Type: &hostPathDirectoryOrCreate,
+				},
+			},
+		},
+		{
+			Name: "sys",
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{
+					Path: "/sys",
+					Type: &hostPathDirectory,
+				},
+			},
+		},
+		{
+			Name: "config",
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: GetDRADriverConfigMapName(nwConfig),
+					},
+				},
+			},
+		},
+	}
+	volumeMounts := []v1.VolumeMount{
+		{
+			Name:             "kubelet-plugins",
+			MountPath:        kubeletPluginsDir,
+			MountPropagation: ptr.To(v1.MountPropagationBidirectional),
+		},
+		{
+			Name:      "kubelet-plugins-registry",
+			MountPath: kubeletRegistryDir,
+		},
+		{
+			Name:      "cdi",
+			MountPath: cdiDir,
+		},
+		{
+			Name:      "sys",
+			MountPath: "/sys",
+		},
+		{
+			Name:      "config",
+			MountPath: draDriverConfigPath,
+			ReadOnly:  true,
+		},
+	}
+
+	container := v1.Container{
+		Name:  DRADriverNameSuffix,
+		Image: image,
+		Args: []string{
+			"--driver-name=" + DriverName,
+			"--node-name=$(NODE_NAME)",
+			fmt.Sprintf("--config-file=%s/%s", draDriverConfigPath, DRADriverConfigKey),
+			"--kubelet-plugins-directory=" + kubeletPluginsDir,
+			"--kubelet-registrar-directory=" + kubeletRegistryDir,
+			"--cdi-root=" + cdiDir,
+		},
+		Env: []v1.EnvVar{
+			{
+				Name: "NODE_NAME",
+				ValueFrom: &v1.EnvVarSource{
+					FieldRef: &v1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+				},
+			},
+		},
+		SecurityContext: &v1.SecurityContext{Privileged: ptr.To(true)},
+		VolumeMounts:    volumeMounts,
+	}
+	if spec.ImagePullPolicy != "" {
+		container.ImagePullPolicy = v1.PullPolicy(spec.ImagePullPolicy)
+	}
+
+	imagePullSecrets := []v1.LocalObjectReference{}
+	if spec.ImageRegistrySecret != nil {
+		imagePullSecrets = append(imagePullSecrets, *spec.ImageRegistrySecret)
+	}
+
+	ds.Spec = appsv1.DaemonSetSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: matchLabels},
+		Template: v1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: matchLabels,
+				Annotations: map[string]string{
+					// roll the DRA driver pods when the config changes, the DRA driver only reads it on start
+					DRADriverConfigHashAnnotation: configHash,
+				},
+			},
+			Spec: v1.PodSpec{
+				Containers:         []v1.Container{container},
+				ImagePullSecrets:   imagePullSecrets,
+				PriorityClassName:  "system-node-critical",
+				NodeSelector:       nodeSelector,
+				ServiceAccountName: draDriverSAName,
+				Volumes:            volumes,
+			},
+		},
+	}
+	if spec.UpgradePolicy != nil {
+		up := spec.UpgradePolicy
+		upgradeStrategy := appsv1.RollingUpdateDaemonSetStrategyType
+		if up.UpgradeStrategy == "OnDelete" {
+			upgradeStrategy = appsv1.OnDeleteDaemonSetStrategyType
+		}
+		ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+			Type: upgradeStrategy,
+		}
+		if upgradeStrategy == appsv1.RollingUpdateDaemonSetStrategyType {
+			ds.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+				MaxUnavailable: &intstr.IntOrString{IntVal: int32(up.MaxUnavailable)},
+			}
+		}
+	}
+	if len(spec.Tolerations) > 0 {
+		ds.Spec.Template.Spec.Tolerations = spec.Tolerations
+	} else {
+		ds.Spec.Template.Spec.Tolerations = nil
+	}
+	return nil
+}
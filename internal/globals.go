@@ -19,4 +19,6 @@ package utils
 const (
 	// CRNameLabel is the label key used to denote the name of the custom resource
 	CRNameLabel = "amd.com/network-operator-cr-name"
+	// CRNamespaceLabel is the label key used to denote the namespace of the custom resource on cluster scoped objects
+	CRNamespaceLabel = "amd.com/network-operator-cr-namespace"
 )
@@ -0,0 +1,337 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hostconfiginternal
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+)
+
+const (
+	// HostConfigNameSuffix is the suffix of the host config agent DaemonSet and ConfigMap names
+	HostConfigNameSuffix = "host-config"
+	// HostConfigHashAnnotation is set on the host config agent pod template to roll the pods when the host config changes
+	HostConfigHashAnnotation = "network.operator.amd.com/host-config-hash"
+	// ModprobeConfigPath is the modprobe config written on the nodes
+	ModprobeConfigPath = "/etc/modprobe.d/amd-network-operator.conf"
+	// SysctlConfigPath is the sysctl config written on the nodes
+	SysctlConfigPath = "/etc/sysctl.d/99-amd-network-operator.conf"
+
+	modprobeConfigKey        = "modprobe.conf"
+	sysctlConfigKey          = "sysctl.conf"
+	hostConfigSAName         = "amd-network-operator-host-config"
+	hostConfigMountPath      = "/host-config"
+	syncedFile               = "/tmp/host-config-synced"
+	defaultMachineConfigPool = "worker"
+	ignitionVersion          = "3.2.0"
+	// machineConfigRoleLabel selects the MachineConfigPool rendering the MachineConfig
+	machineConfigRoleLabel = "machineconfiguration.openshift.io/role"
+	configHeader           = "# managed by the AMD Network Operator, do not edit\n"
+)
+
+var (
+	//go:embed scripts/hostConfigScript.sh
+	hostConfigScript string
+
+	// MachineConfigGVK is the OpenShift MachineConfig kind, used as unstructured as the OpenShift API isn't vendored
+	MachineConfigGVK = schema.GroupVersionKind{Group: "machineconfiguration.openshift.io", Version: "v1", Kind: "MachineConfig"}
+	// MachineConfigPoolGVK is the OpenShift MachineConfigPool kind
+	MachineConfigPoolGVK = schema.GroupVersionKind{Group: "machineconfiguration.openshift.io", Version: "v1", Kind: "MachineConfigPool"}
+)
+
+// IsHostConfigEnabled returns true if the host config is enabled on the NetworkConfig
+func IsHostConfigEnabled(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	return nwConfig.Spec.HostConfig.Enable != nil && *nwConfig.Spec.HostConfig.Enable
+}
+
+// GetHostConfigName returns the name of the host config agent DaemonSet and ConfigMap
+func GetHostConfigName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s", nwConfig.Name, HostConfigNameSuffix)
+}
+
+// GetMachineConfigPool returns the MachineConfigPool the host config is rendered for on OpenShift
+func GetMachineConfigPool(nwConfig *amdv1alpha1.NetworkConfig) string {
+	if nwConfig.Spec.HostConfig.MachineConfigPool != "" {
+		return nwConfig.Spec.HostConfig.MachineConfigPool
+	}
+	return defaultMachineConfigPool
+}
+
+// GetMachineConfigName returns the name of the MachineConfig rendered on OpenShift
+// the 99 prefix orders it after the MachineConfigs of the cluster
+func GetMachineConfigName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("99-%s-amd-network-%s-%s", GetMachineConfigPool(nwConfig), nwConfig.Namespace, nwConfig.Name)
+}
+
+// GetBlacklist returns the blacklisted kernel modules, including the ionic inbox driver when driver.blacklist is set
+func GetBlacklist(nwConfig *amdv1alpha1.NetworkConfig) []string {
+	blacklist := slices.Clone(nwConfig.Spec.HostConfig.Blacklist)
+	if nwConfig.Spec.Driver.Blacklist != nil && *nwConfig.Spec.Driver.Blacklist {
+		blacklist = append(blacklist, "ionic")
+	}
+	sort.Strings(blacklist)
+	return slices.Compact(blacklist)
+}
+
+// GenerateModprobeConfig renders the modprobe options and blacklist, empty when there is nothing to configure
+func GenerateModprobeConfig(nwConfig *amdv1alpha1.NetworkConfig) string {
+	lines := []string{}
+	for _, opts := range nwConfig.Spec.HostConfig.ModprobeOptions {
+		if len(opts.Options) == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("options %s %s", opts.Module, strings.Join(opts.Options, " ")))
+	}
+	for _, module := range GetBlacklist(nwConfig) {
+		lines = append(lines, "blacklist "+module)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return configHeader + strings.Join(lines, "\n") + "\n"
+}
+
+// GenerateSysctlConfig renders the sysctls sorted by key, empty when there is nothing to configure
+func GenerateSysctlConfig(nwConfig *amdv1alpha1.NetworkConfig) string {
+	sysctls := nwConfig.Spec.HostConfig.Sysctls
+	if len(sysctls) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(sysctls))
+	for key := range sysctls {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	lines := []string{}
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s = %s", key, sysctls[key]))
+	}
+	return configHeader + strings.Join(lines, "\n") + "\n"
+}
+
+// GetHostConfigHash returns the hash of the rendered host config
+func GetHostConfigHash(nwConfig *amdv1alpha1.NetworkConfig) string {
+	hash := sha256.Sum256([]byte(GenerateModprobeConfig(nwConfig) + GenerateSysctlConfig(nwConfig)))
+	return hex.EncodeToString(hash[:])
+}
+
+// SetHostConfigMapAsDesired renders the host config files into the ConfigMap mounted by the host config agent
+// and returns the config hash to be set on the agent pod template
+func SetHostConfigMapAsDesired(cm *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig) string {
+	cm.Data = map[string]string{
+		modprobeConfigKey: GenerateModprobeConfig(nwConfig),
+		sysctlConfigKey:   GenerateSysctlConfig(nwConfig),
+	}
+	return GetHostConfigHash(nwConfig)
+}
+
+// SetHostConfigAgentAsDesired renders the host config agent DaemonSet writing the host config files on the nodes
+func SetHostConfigAgentAsDesired(ds *appsv1.DaemonSet, nwConfig *amdv1alpha1.NetworkConfig, configHash string) error {
+	if ds == nil {
+		return fmt.Errorf("daemon set is not initialized, zero pointer")
+	}
+	spec := nwConfig.Spec.HostConfig
+
+	image := utils.DefaultUtilsImage
+	if spec.Image != "" {
+		image = spec.Image
+	} else if nwConfig.Spec.CommonConfig.UtilsContainer.Image != "" {
+		image = nwConfig.Spec.CommonConfig.UtilsContainer.Image
+	}
+
+	matchLabels := map[string]string{
+		"daemonset-name":         ds.Name,
+		"app.kubernetes.io/name": HostConfigNameSuffix,
+		utils.CRNameLabel:        nwConfig.Name,
+	}
+
+	nodeSelector := map[string]string{}
+	for key, val := range nwConfig.Spec.Selector {
+		nodeSelector[key] = val
+	}
+
+	hostPathDirectory := v1.HostPathDirectory
+	container := v1.Container{
+		Name:            HostConfigNameSuffix,
+		Image:           image,
+		Command:         []string{"/bin/bash", "-c", hostConfigScript},
+		SecurityContext: &v1.SecurityContext{Privileged: ptr.To(true)},
+		// the agent is ready once the host config is applied, the readiness reports the node in sync
+		ReadinessProbe: &v1.Probe{
+			ProbeHandler: v1.ProbeHandler{
+				Exec: &v1.ExecAction{Command: []string{"test", "-f", syncedFile}},
+			},
+			PeriodSeconds: 5,
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      "host-etc",
+				MountPath: "/host/etc",
+			},
+			{
+				Name:      "config",
+				MountPath: hostConfigMountPath,
+				ReadOnly:  true,
+			},
+		},
+	}
+	if spec.ImagePullPolicy != "" {
+		container.ImagePullPolicy = v1.PullPolicy(spec.ImagePullPolicy)
+	}
+
+	imagePullSecrets := []v1.LocalObjectReference{}
+	if spec.ImageRegistrySecret != nil {
+		imagePullSecrets = append(imagePullSecrets, *spec.ImageRegistrySecret)
+	}
+
+	ds.Spec = appsv1.DaemonSetSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: matchLabels},
+		Template: v1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: matchLabels,
+				Annotations: map[string]string{
+					// roll the agent pods when the host config changes, each pod applies it once on start
+					HostConfigHashAnnotation: configHash,
+				},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{container},
+				// net.* sysctls are namespaced, they only apply to the host in the host network namespace
+				HostNetwork:        true,
+				ImagePullSecrets:   imagePullSecrets,
+				PriorityClassName:  "system-node-critical",
+				NodeSelector:       nodeSelector,
+				ServiceAccountName: hostConfigSAName,
+				Tolerations:        spec.Tolerations,
+				Volumes: []v1.Volume{
+					{
+						Name: "host-etc",
+						VolumeSource: v1.VolumeSource{
+							HostPath: &v1.HostPathVolumeSource{
+								Path: "/etc",
+								Type: &hostPathDirectory,
+							},
+						},
+					},
+					{
+						Name: "config",
+						VolumeSource: v1.VolumeSource{
+							ConfigMap: &v1.ConfigMapVolumeSource{
+								LocalObjectReference: v1.LocalObjectReference{
+									Name: GetHostConfigName(nwConfig),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+			Type: appsv1.RollingUpdateDaemonSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+				// the agent doesn't serve anything, all the nodes can be updated at once
+				MaxUnavailable: ptr.To(intstr.FromString("100%")),
+			},
+		},
+	}
+	return nil
+}
+
+// SetMachineConfigAsDesired renders the host config files into the MachineConfig on OpenShift
+func SetMachineConfigAsDesired(mc *unstructured.Unstructured, nwConfig *amdv1alpha1.NetworkConfig) error {
+	mc.SetGroupVersionKind(MachineConfigGVK)
+	labels := mc.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[machineConfigRoleLabel] = GetMachineConfigPool(nwConfig)
+	labels[utils.CRNameLabel] = nwConfig.Name
+	labels[utils.CRNamespaceLabel] = nwConfig.Namespace
+	mc.SetLabels(labels)
+
+	files := []interface{}{}
+	for path, content := range map[string]string{
+		ModprobeConfigPath: GenerateModprobeConfig(nwConfig),
+		SysctlConfigPath:   GenerateSysctlConfig(nwConfig),
+	} {
+		if content == "" {
+			continue
+		}
+		files = append(files, map[string]interface{}{
+			"path":      path,
+			"mode":      int64(0644),
+			"overwrite": true,
+			"contents": map[string]interface{}{
+				"source": "data:," + url.PathEscape(content),
+			},
+		})
+	}
+	// keep the file order stable to avoid rendering a new MachineConfig on every reconcile
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].(map[string]interface{})["path"].(string) < files[j].(map[string]interface{})["path"].(string)
+	})
+
+	return unstructured.SetNestedField(mc.Object, map[string]interface{}{
+		"config": map[string]interface{}{
+			"ignition": map[string]interface{}{
+				"version": ignitionVersion,
+			},
+			"storage": map[string]interface{}{
+				"files": files,
+			},
+		},
+	}, "spec")
+}
+
+// GetNodeMachineConfigStatus checks if the node runs the config rendered by its MachineConfigPool including the MachineConfig
+func GetNodeMachineConfigStatus(node *v1.Node, mcp *unstructured.Unstructured, mcName string) (bool, string) {
+	renderedConfig, _, _ := unstructured.NestedString(mcp.Object, "status", "configuration", "name")
+	sources, _, _ := unstructured.NestedSlice(mcp.Object, "status", "configuration", "source")
+	rendered := false
+	for _, source := range sources {
+		if ref, ok := source.(map[string]interface{}); ok && ref["name"] == mcName {
+			rendered = true
+			break
+		}
+	}
+	if !rendered {
+		return false, fmt.Sprintf("MachineConfig %s is not rendered by MachineConfigPool %s yet", mcName, mcp.GetName())
+	}
+	annotations := node.GetAnnotations()
+	if annotations["machineconfiguration.openshift.io/currentConfig"] != renderedConfig ||
+		annotations["machineconfiguration.openshift.io/state"] != "Done" {
+		return false, fmt.Sprintf("node is updating to %s", renderedConfig)
+	}
+	return true, ""
+}
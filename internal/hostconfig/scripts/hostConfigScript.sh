@@ -0,0 +1,32 @@
+#!/bin/bash
+# applies the host config rendered by the AMD Network Operator
+# the pod turns ready once the node is in sync with the host config
+set -e
+
+# writes src to dst, or removes dst when src is empty
+apply_file() {
+  local src=$1
+  local dst=$2
+  if [ -s "$src" ]; then
+    cp "$src" "$dst.tmp"
+    mv "$dst.tmp" "$dst"
+    echo "updated $dst"
+  elif [ -f "$dst" ]; then
+    rm -f "$dst"
+    echo "removed $dst"
+  fi
+}
+
+rm -f /tmp/host-config-synced
+apply_file /host-config/modprobe.conf /host/etc/modprobe.d/amd-network-operator.conf
+apply_file /host-config/sysctl.conf /host/etc/sysctl.d/99-amd-network-operator.conf
+if [ -s /host-config/sysctl.conf ]; then
+  # the pod runs in the host network namespace, net.* sysctls apply to the host
+  sysctl -p /host-config/sysctl.conf
+fi
+touch /tmp/host-config-synced
+echo "host config applied"
+
+while true; do
+  sleep 3600
+done
@@ -39,19 +39,15 @@ import (
 	"fmt"
 	"os"
 	"regexp"
-	"sort"
 	"strings"
 
 	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
 	utils "github.com/ROCm/network-operator/internal"
 	kmmv1beta1 "github.com/rh-ecosystem-edge/kernel-module-management/api/v1beta1"
-	"golang.org/x/exp/maps"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -71,6 +67,8 @@ const (
 	defaultInitContainerImage   = "busybox:1.36"
 	defaultSourceImageRepo      = "docker.io/rocm/amdainic-driver"
 	nfdOSReleaseLabelKey        = "feature.node.kubernetes.io/system-os_release.VERSION_ID"
+	buildSecretsMountPath       = "/run/secrets/"
+	caBundlePathsBuildArg       = "CA_BUNDLE_PATHS"
 )
 
 var (
@@ -80,6 +78,8 @@ var (
 	dockerfileTemplateCoreOSFromSrcImage string
 	//go:embed dockerfiles/DockerfileTemplate.rpm.ionic.coreos
 	dockerfileTemplateCoreOSFromRPM string
+	//go:embed dockerfiles/DockerfileTemplate.rhel
+	dockerfileTemplateRHEL string
 	//go:embed devdockerfiles/devdockerfile.txt
 	dockerfileDevTemplateUbuntu string
 )
@@ -87,8 +87,10 @@ var (
 //go:generate mockgen -source=kmmmodule.go -package=kmmmodule -destination=mock_kmmmodule.go KMMModuleAPI
 type KMMModuleAPI interface {
 	SetNodeVersionLabelAsDesired(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
-	SetBuildConfigMapAsDesired(buildCM *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig) error
+	SetBuildConfigMapAsDesired(buildCM *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig, profile *OSProfile, osVersion string) error
 	SetKMMModuleAsDesired(ctx context.Context, mod *kmmv1beta1.Module, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error
+	GetUnsupportedNodes(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) map[string]string
+	GetOSProfiles(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) (*OSProfiles, error)
 }
 
 type kmmModule struct {
@@ -111,6 +113,10 @@ func (km *kmmModule) SetNodeVersionLabelAsDesired(ctx context.Context, nwConfig
 	// KMM operator will watch on the version label and manage the kmod upgrade
 	labelKey, labelVal := GetVersionLabelKV(nwConfig)
 	logger := log.FromContext(ctx)
+	profiles, err := km.GetOSProfiles(ctx, nwConfig)
+	if err != nil {
+		return err
+	}
 	for _, node := range nodes.Items {
 		if _, ok := node.Labels[labelKey]; ok {
 			// version label was already put on the node object
@@ -120,7 +126,7 @@ func (km *kmmModule) SetNodeVersionLabelAsDesired(ctx context.Context, nwConfig
 			continue
 		}
 		if labelVal == "" {
-			defaultVersion, err := utils.GetDefaultDriversVersion(node)
+			defaultVersion, err := profiles.GetDefaultDriversVersion(node)
 			if err != nil {
 				logger.Error(err, fmt.Sprintf("failed to get default version for node %+v err %+v", node.GetName(), err))
 			}
@@ -145,29 +151,21 @@ func (km *kmmModule) SetNodeVersionLabelAsDesired(ctx context.Context, nwConfig
 	return nil
 }
 
-func (km *kmmModule) SetBuildConfigMapAsDesired(buildCM *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig) error {
+func (km *kmmModule) SetBuildConfigMapAsDesired(buildCM *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig, profile *OSProfile, osVersion string) error {
 	if buildCM.Data == nil {
 		buildCM.Data = make(map[string]string)
 	}
-	if km.isOpenShift {
-		buildCM.Data["dockerfile"] = dockerfileTemplateCoreOSFromRPM
-		if nwConfig.Spec.Driver.UseSourceImage != nil && *nwConfig.Spec.Driver.UseSourceImage {
-			buildCM.Data["dockerfile"] = dockerfileTemplateCoreOSFromSrcImage
-		}
-	} else {
-		dockerfile, err := resolveDockerfile(buildCM.Name, nwConfig)
-		if err != nil {
-			return err
-		}
-		buildCM.Data["dockerfile"] = dockerfile
+	dockerfile, err := resolveDockerfile(profile, osVersion, nwConfig)
+	if err != nil {
+		return err
 	}
+	buildCM.Data["dockerfile"] = dockerfile
 	return controllerutil.SetControllerReference(nwConfig, buildCM, km.scheme)
 }
 
-var driverLabels = map[string]string{
-	"20.04": "focal",
-	"22.04": "jammy",
-	"24.04": "noble",
+// GetOSProfiles returns the built-in and user defined OS profiles for the given NetworkConfig
+func (km *kmmModule) GetOSProfiles(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig) (*OSProfiles, error) {
+	return LoadOSProfiles(ctx, km.client, nwConfig)
 }
 
 func parseRHELHelper(regExp *regexp.Regexp, osImage string) string {
@@ -204,55 +202,16 @@ func parseRHELVersion(labels map[string]string, osImage string) string {
 	return ""
 }
 
-func resolveDockerfile(cmName string, nwConfig *amdv1alpha1.NetworkConfig) (string, error) {
-	splits := strings.SplitN(cmName, "-", 4)
-	osDistro := splits[0]
-	version := splits[1]
-	var dockerfileTemplate string
-	switch osDistro {
-	case "ubuntu":
-		dockerfileTemplate = dockerfileTemplateUbuntu
-		driverLabel, present := driverLabels[version]
-		if !present {
-			return "", fmt.Errorf("invalid ubuntu version, expected to be one of %v", maps.Keys(driverLabels))
-		}
-		dockerfileTemplate = strings.Replace(dockerfileTemplate, "$$DRIVER_LABEL", driverLabel, -1)
-
-		// trigger to pull the internal ROCM dev build
-		if internalArtifactoryURL, ok := os.LookupEnv("INTERNAL_ARTIFACTORY"); ok &&
-			strings.Contains(strings.ToLower(nwConfig.Spec.Driver.AMDNetworkInstallerRepoURL), internalArtifactoryURL) {
-			dockerfileTemplate = dockerfileDevTemplateUbuntu
-			devBuildinfo := strings.Split(nwConfig.Spec.Driver.AMDNetworkInstallerRepoURL, " ")
-			if len(devBuildinfo) < 4 {
-				return "", fmt.Errorf("please provide internal build info, required 4 items: artifactory URL, installer deb file name, amdionic build number and rocm build tag, got: %+v", nwConfig.Spec.Driver.AMDNetworkInstallerRepoURL)
-			}
-			nwConfig.Spec.Driver.AMDNetworkInstallerRepoURL = devBuildinfo[0]
-			dockerfileTemplate = strings.Replace(dockerfileTemplate, "$$DEV_DEB", devBuildinfo[1], -1)
-			dockerfileTemplate = strings.Replace(dockerfileTemplate, "$$AMDNetwork_BUILD", devBuildinfo[2], -1)
-			dockerfileTemplate = strings.Replace(dockerfileTemplate, "$$ROCM_BUILD", devBuildinfo[3], -1)
+func resolveDockerfile(profile *OSProfile, osVersion string, nwConfig *amdv1alpha1.NetworkConfig) (string, error) {
+	// build with the internal dev installer package
+	if nwConfig.Spec.Driver.DevBuild != nil {
+		if profile.Name != "ubuntu" {
+			return "", fmt.Errorf("driver dev build is only supported on ubuntu, got: %s", profile.OSName(osVersion))
 		}
-	case "coreos":
-		dockerfileTemplate = dockerfileTemplateCoreOSFromRPM
-		if nwConfig.Spec.Driver.UseSourceImage != nil && *nwConfig.Spec.Driver.UseSourceImage {
-			dockerfileTemplate = dockerfileTemplateCoreOSFromSrcImage
-		}
-	// FIX ME
-	// add the RHEL back when it is fully supported
-	/*case "rhel":
-	dockerfileTemplate = dockerfileTemplateRHEL
-	versionSplits := strings.Split(version, ".")
-	dockerfileTemplate = strings.Replace(dockerfileTemplate, "$$MAJOR_VERSION", versionSplits[0], -1)
-	if nwConfig.Spec.RedhatSubscriptionUsername == "" || nwConfig.Spec.RedhatSubscriptionPassword == "" {
-		return "", fmt.Errorf("Redhat subscription RedhatSubscriptionUsername and RedhatSubscriptionPassword required")
-	}
-	dockerfileTemplate = strings.Replace(dockerfileTemplate, "$$REDHAT_SUBSCRIPTION_USERNAME", nwConfig.Spec.RedhatSubscriptionUsername, -1)
-	dockerfileTemplate = strings.Replace(dockerfileTemplate, "$$REDHAT_SUBSCRIPTION_PASSWORD", nwConfig.Spec.RedhatSubscriptionPassword, -1)
-	*/
-	default:
-		return "", fmt.Errorf("not supported OS: %s", osDistro)
-	}
-	resolvedDockerfile := strings.Replace(dockerfileTemplate, "$$VERSION", version, -1)
-	return resolvedDockerfile, nil
+		return strings.Replace(dockerfileDevTemplateUbuntu, "$$VERSION", osVersion, -1), nil
+	}
+	useSourceImage := nwConfig.Spec.Driver.UseSourceImage != nil && *nwConfig.Spec.Driver.UseSourceImage
+	return profile.ResolveDockerfile(osVersion, useSourceImage), nil
 }
 
 func (km *kmmModule) SetKMMModuleAsDesired(ctx context.Context, mod *kmmv1beta1.Module, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) error {
@@ -267,7 +226,11 @@ func (km *kmmModule) setKMMModuleLoader(ctx context.Context, mod *kmmv1beta1.Mod
 	kmlog := log.FromContext(ctx)
 	kmlog.Info(fmt.Sprintf("isOpenshift %+v", km.isOpenShift))
 
-	kernelMappings, driversVersion, err := getKernelMappings(nwConfig, km.isOpenShift, nodes)
+	profiles, err := km.GetOSProfiles(ctx, nwConfig)
+	if err != nil {
+		return err
+	}
+	kernelMappings, driversVersion, err := getKernelMappings(nwConfig, km.isOpenShift, nodes, profiles)
 	if err != nil {
 		return err
 	}
@@ -325,7 +288,7 @@ func (km *kmmModule) setKMMModuleLoader(ctx context.Context, mod *kmmv1beta1.Mod
 	return nil
 }
 
-func getKernelMappings(nwConfig *amdv1alpha1.NetworkConfig, isOpenshift bool, nodes *v1.NodeList) ([]kmmv1beta1.KernelMapping, string, error) {
+func getKernelMappings(nwConfig *amdv1alpha1.NetworkConfig, isOpenshift bool, nodes *v1.NodeList, profiles *OSProfiles) ([]kmmv1beta1.KernelMapping, string, error) {
 
 	inTreeModuleToRemove := ""
 	if isOpenshift {
@@ -333,34 +296,103 @@ func getKernelMappings(nwConfig *amdv1alpha1.NetworkConfig, isOpenshift bool, no
 	}
 
 	if nodes == nil || len(nodes.Items) == 0 {
-		return nil, "", fmt.Errorf("No nodes found for the label selector %s", MapToLabelSelector(nwConfig.Spec.Selector))
+		return nil, "", fmt.Errorf("No nodes found for the label selector %s", k8slabels.SelectorFromSet(nwConfig.Spec.Selector))
 	}
 	kernelMappings := []kmmv1beta1.KernelMapping{}
-	kmSet := map[string]bool{}
+	// KMM picks the first mapping matching the kernel, the nodes sharing a kernel mapping must load the same image
+	kmSet := map[string]kmmv1beta1.KernelMapping{}
+	kmNodes := map[string]string{}
 	var driversVersion string
+	skipUnsupported := SkipUnsupportedNodes(nwConfig)
 	for _, node := range nodes.Items {
-		km, ver, err := getKM(nwConfig, node, inTreeModuleToRemove, isOpenshift)
+		km, ver, err := getKM(nwConfig, node, inTreeModuleToRemove, isOpenshift, profiles)
 		if err != nil {
+			if skipUnsupported {
+				// unsupported nodes are reported in the NetworkConfig status
+				// don't block the driver rollout on the rest of the nodes
+				continue
+			}
 			return nil, driversVersion, fmt.Errorf("error constructing a kernel mapping for node: %s, err: %v", node.Name, err)
 		}
-		if kmSet[km.Literal] {
+		key := km.Literal
+		if km.Regexp != "" {
+			key = km.Regexp
+		}
+		if existing, ok := kmSet[key]; ok {
+			if existing.ContainerImage != km.ContainerImage {
+				return nil, driversVersion, fmt.Errorf("kernel mapping %s matches node %s loading %s and node %s loading %s, use a kernel mapping regexp per OS",
+					key, kmNodes[key], existing.ContainerImage, node.Name, km.ContainerImage)
+			}
 			continue
 		}
 		kernelMappings = append(kernelMappings, km)
-		kmSet[km.Literal] = true
+		kmSet[key] = km
+		kmNodes[key] = node.Name
 		driversVersion = ver
 	}
+	if len(kernelMappings) == 0 {
+		return nil, driversVersion, fmt.Errorf("no supported nodes found for the label selector %s", k8slabels.SelectorFromSet(nwConfig.Spec.Selector))
+	}
 	return kernelMappings, driversVersion, nil
 }
 
-func getKM(nwConfig *amdv1alpha1.NetworkConfig, node v1.Node, inTreeModuleToRemove string, isOpenShift bool) (kmmv1beta1.KernelMapping, string, error) {
+// GetUnsupportedNodes returns the reason per node name for the selected nodes that cannot get a kernel mapping
+func (km *kmmModule) GetUnsupportedNodes(ctx context.Context, nwConfig *amdv1alpha1.NetworkConfig, nodes *v1.NodeList) map[string]string {
+	unsupported := map[string]string{}
+	if nodes == nil {
+		return unsupported
+	}
+	profiles, err := km.GetOSProfiles(ctx, nwConfig)
+	if err != nil {
+		for _, node := range nodes.Items {
+			unsupported[node.Name] = err.Error()
+		}
+		return unsupported
+	}
+	return getUnsupportedNodes(nwConfig, km.isOpenShift, nodes, profiles)
+}
+
+func getUnsupportedNodes(nwConfig *amdv1alpha1.NetworkConfig, isOpenShift bool, nodes *v1.NodeList, profiles *OSProfiles) map[string]string {
+	unsupported := map[string]string{}
+	if nodes == nil {
+		return unsupported
+	}
+	for _, node := range nodes.Items {
+		if _, _, err := getKM(nwConfig, node, "", isOpenShift, profiles); err != nil {
+			unsupported[node.Name] = err.Error()
+		}
+	}
+	return unsupported
+}
+
+// SkipUnsupportedNodes returns true if unsupported nodes should be left out of the driver rollout instead of failing it
+func SkipUnsupportedNodes(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	return nwConfig.Spec.Driver.UnsupportedNodePolicy == amdv1alpha1.UnsupportedNodePolicySkip
+}
+
+// getKernelRegexp returns the first user provided kernel regexp matching the given kernel version
+func getKernelRegexp(nwConfig *amdv1alpha1.NetworkConfig, kernelVersion string) (string, error) {
+	for _, mapping := range nwConfig.Spec.Driver.KernelMappings {
+		re, err := regexp.Compile(mapping.Regexp)
+		if err != nil {
+			return "", fmt.Errorf("invalid kernel mapping regexp %s: %v", mapping.Regexp, err)
+		}
+		if re.MatchString(kernelVersion) {
+			return mapping.Regexp, nil
+		}
+	}
+	return "", nil
+}
+
+func getKM(nwConfig *amdv1alpha1.NetworkConfig, node v1.Node, inTreeModuleToRemove string, isOpenShift bool, profiles *OSProfiles) (kmmv1beta1.KernelMapping, string, error) {
 	driversVersion := nwConfig.Spec.Driver.Version
 	driversImage := nwConfig.Spec.Driver.Image
 	var err error
-	osName, err := GetOSName(node, nwConfig)
+	profile, osVersion, err := profiles.Match(node)
 	if err != nil {
 		return kmmv1beta1.KernelMapping{}, "", err
 	}
+	osName := profile.OSName(osVersion)
 
 	rhelVersion := ""
 	sourceImageRepo := defaultSourceImageRepo
@@ -368,48 +400,36 @@ func getKM(nwConfig *amdv1alpha1.NetworkConfig, node v1.Node, inTreeModuleToRemo
 		sourceImageRepo = nwConfig.Spec.Driver.ImageBuild.SourceImageRepo
 	}
 
-	// Set platform-specific default base image registry
+	// Set OS-specific default base image registry
 	// Note: CRD has +kubebuilder:default=docker.io, so field is never empty
-	// Override with platform-appropriate default if user used the CRD default
+	// Override with the OS profile default if user used the CRD default
 	baseImageRegistry := nwConfig.Spec.Driver.ImageBuild.BaseImageRegistry
-	if baseImageRegistry == "" || baseImageRegistry == "docker.io" {
-		// Use platform-appropriate defaults
-		if isOpenShift {
-			baseImageRegistry = "registry.access.redhat.com"
-		} else {
-			// Determine OS to set appropriate default
-			osImageStr := strings.ToLower(node.Status.NodeInfo.OSImage)
-			if strings.Contains(osImageStr, "ubuntu") {
-				baseImageRegistry = "docker.io"
-			} else if strings.Contains(osImageStr, "red hat") || strings.Contains(osImageStr, "rhel") {
-				baseImageRegistry = "registry.access.redhat.com"
-			} else {
-				baseImageRegistry = "docker.io" // fallback default
-			}
+	if baseImageRegistry == "" || baseImageRegistry == defaultDockerIORegistry {
+		switch {
+		case isOpenShift:
+			baseImageRegistry = defaultRedHatRegistry
+		case profile.BaseImageRegistry != "":
+			baseImageRegistry = profile.BaseImageRegistry
+		default:
+			baseImageRegistry = defaultDockerIORegistry
 		}
 	}
 
-	if isOpenShift {
-		if driversVersion == "" {
-			driversVersion = defaultOcDriversVersion
+	if driversVersion == "" {
+		driversVersion, err = profiles.GetDefaultDriversVersion(node)
+		if err != nil {
+			return kmmv1beta1.KernelMapping{}, "", err
 		}
+	}
+	if isOpenShift {
 		if driversImage == "" {
 			driversImage = defaultOcDriversImageTemplate
 		}
-		driversImage = addNodeInfoSuffixToImageTag(driversImage, osName, driversVersion)
 		rhelVersion = parseRHELVersion(node.Labels, node.Status.NodeInfo.OSImage)
-	} else {
-		if driversVersion == "" {
-			driversVersion, err = utils.GetDefaultDriversVersion(node)
-			if err != nil {
-				return kmmv1beta1.KernelMapping{}, "", err
-			}
-		}
-		if driversImage == "" {
-			driversImage = defaultDriversImageTemplate
-		}
-		driversImage = addNodeInfoSuffixToImageTag(driversImage, osName, driversVersion)
+	} else if driversImage == "" {
+		driversImage = defaultDriversImageTemplate
 	}
+	driversImage = addNodeInfoSuffixToImageTag(driversImage, osName, driversVersion)
 
 	repoURL := defaultInstallerRepoURL
 	if nwConfig.Spec.Driver.AMDNetworkInstallerRepoURL != "" {
@@ -434,17 +454,22 @@ func getKM(nwConfig *amdv1alpha1.NetworkConfig, node v1.Node, inTreeModuleToRemo
 		kmmSign = &kmmv1beta1.Sign{
 			KeySecret:   nwConfig.Spec.Driver.ImageSign.KeySecret,
 			CertSecret:  nwConfig.Spec.Driver.ImageSign.CertSecret,
-			FilesToSign: getKmodsToSign(isOpenShift, node.Status.NodeInfo.KernelVersion),
+			FilesToSign: profile.GetKmodsToSign(node.Status.NodeInfo.KernelVersion),
 		}
 		if registryTLS != nil {
 			kmmSign.UnsignedImageRegistryTLS = *registryTLS
 		}
 	}
 
+	dockerfileCM := &v1.LocalObjectReference{
+		Name: GetCMName(osName, nwConfig),
+	}
+	if userDockerfileCM := GetUserDockerfileConfigMap(nwConfig, profile.Name, osName); userDockerfileCM != nil {
+		dockerfileCM = userDockerfileCM
+	}
+
 	kmmBuild := &kmmv1beta1.Build{
-		DockerfileConfigMap: &v1.LocalObjectReference{
-			Name: GetCMName(osName, nwConfig),
-		},
+		DockerfileConfigMap: dockerfileCM,
 		BuildArgs: []kmmv1beta1.BuildArg{
 			{
 				Name:  "DRIVERS_VERSION",
@@ -498,6 +523,45 @@ func getKM(nwConfig *amdv1alpha1.NetworkConfig, node v1.Node, inTreeModuleToRemo
 		}
 	}
 
+	if devBuild := nwConfig.Spec.Driver.DevBuild; devBuild != nil {
+		kmmBuild.BuildArgs = append(kmmBuild.BuildArgs,
+			kmmv1beta1.BuildArg{
+				Name:  "INSTALLER_PACKAGE_URL",
+				Value: devBuild.InstallerPackageURL,
+			},
+			kmmv1beta1.BuildArg{
+				Name:  "IONIC_BUILD",
+				Value: devBuild.IonicBuild,
+			},
+			kmmv1beta1.BuildArg{
+				Name:  "ROCM_BUILD",
+				Value: devBuild.ROCmBuild,
+			},
+		)
+	}
+
+	// KMM mounts the build secrets at /run/secrets/<secret name> during the build
+	caBundlePaths := []string{}
+	for _, caBundle := range nwConfig.Spec.Driver.ImageBuild.CABundleSecrets {
+		caBundlePaths = append(caBundlePaths, buildSecretsMountPath+caBundle.Name)
+		kmmBuild.Secrets = append(kmmBuild.Secrets, caBundle)
+	}
+	if len(caBundlePaths) > 0 {
+		kmmBuild.BuildArgs = append(kmmBuild.BuildArgs,
+			kmmv1beta1.BuildArg{
+				Name:  caBundlePathsBuildArg,
+				Value: strings.Join(caBundlePaths, " "),
+			})
+	}
+	kmmBuild.Secrets = append(kmmBuild.Secrets, nwConfig.Spec.Driver.ImageBuild.BuildSecrets...)
+	for _, arg := range nwConfig.Spec.Driver.ImageBuild.BuildArgs {
+		kmmBuild.BuildArgs = append(kmmBuild.BuildArgs,
+			kmmv1beta1.BuildArg{
+				Name:  arg.Name,
+				Value: arg.Value,
+			})
+	}
+
 	km := kmmv1beta1.KernelMapping{
 		Literal:        node.Status.NodeInfo.KernelVersion,
 		ContainerImage: driversImage,
@@ -505,6 +569,17 @@ func getKM(nwConfig *amdv1alpha1.NetworkConfig, node v1.Node, inTreeModuleToRemo
 		Sign:           kmmSign,
 		RegistryTLS:    registryTLS,
 	}
+	// the files to sign are specific to the node kernel, keep the literal mapping for signed images
+	if kmmSign == nil {
+		kernelRegexp, err := getKernelRegexp(nwConfig, node.Status.NodeInfo.KernelVersion)
+		if err != nil {
+			return kmmv1beta1.KernelMapping{}, "", err
+		}
+		if kernelRegexp != "" {
+			km.Literal = ""
+			km.Regexp = kernelRegexp
+		}
+	}
 	if inTreeModuleToRemove != "" {
 		km.InTreeModulesToRemove = []string{inTreeModuleToRemove}
 	}
@@ -525,87 +600,27 @@ func GetCMName(osName string, nwCfg *amdv1alpha1.NetworkConfig) string {
 	return osName + "-" + nwCfg.Name + "-" + nwCfg.Namespace
 }
 
-func GetOSName(node v1.Node, nwCfg *amdv1alpha1.NetworkConfig) (string, error) {
-	osImageStr := strings.ToLower(node.Status.NodeInfo.OSImage)
-
-	// sort the key of cmNameMappers
-	// make sure in the given OS string, coreos was checked before all other types of RHEL string
-	keys := make([]string, 0, len(cmNameMappers))
-	for key := range cmNameMappers {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
-	for _, os := range keys {
-		if strings.Contains(osImageStr, os) {
-			return cmNameMappers[os](osImageStr), nil
+// GetUserDockerfileConfigMap returns the user supplied Dockerfile ConfigMap for the given OS if any
+// an exact <distro>-<version> match takes precedence over a <distro> match
+func GetUserDockerfileConfigMap(nwCfg *amdv1alpha1.NetworkConfig, distro, osName string) *v1.LocalObjectReference {
+	var distroMatch *v1.LocalObjectReference
+	for _, dockerfile := range nwCfg.Spec.Driver.ImageBuild.Dockerfiles {
+		switch dockerfile.OS {
+		case osName:
+			return dockerfile.ConfigMap.DeepCopy()
+		case distro:
+			if distroMatch == nil {
+				distroMatch = dockerfile.ConfigMap.DeepCopy()
+			}
 		}
 	}
-
-	return "", fmt.Errorf("OS: %s not supported. Should be one of %v", osImageStr, maps.Keys(cmNameMappers))
-}
-
-var cmNameMappers = map[string]func(fullImageStr string) string{
-	"ubuntu":  ubuntuCMNameMapper,
-	"coreos":  rhelCoreOSNameMapper,
-	"rhel":    rhelCMNameMapper,
-	"red hat": rhelCMNameMapper,
-	"redhat":  rhelCMNameMapper,
-}
-
-func rhelCMNameMapper(osImageStr string) string {
-	// Check if the input contains "Red Hat Enterprise Linux"
-	// Use regex to find the release version
-	re := regexp.MustCompile(`(\d+\.\d+)`)
-	matches := re.FindStringSubmatch(osImageStr)
-	if len(matches) > 1 {
-		return fmt.Sprintf("%s-%s", "rhel", matches[1])
-	}
-	return "rhel-" + osImageStr
-}
-
-func rhelCoreOSNameMapper(osImageStr string) string {
-	// Check if the input contains "Red Hat Enterprise Linux"
-	// Use regex to find the release version
-	re := regexp.MustCompile(`(\d+\.\d+)`)
-	matches := re.FindStringSubmatch(osImageStr)
-	if len(matches) > 1 {
-		return fmt.Sprintf("%s-%s", "coreos", matches[1])
-	}
-	return "coreos-" + osImageStr
-}
-
-func ubuntuCMNameMapper(osImageStr string) string {
-	splits := strings.Split(osImageStr, " ")
-	os := splits[0]
-	version := splits[1]
-	versionSplits := strings.Split(version, ".")
-	trimmedVersion := strings.Join(versionSplits[:2], ".")
-	return fmt.Sprintf("%s-%s", os, trimmedVersion)
-}
-
-func GetK8SNodes(ls string) (*v1.NodeList, error) {
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		return nil, err
-	}
-	// creates the clientset
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return nil, err
-	}
-	options := metav1.ListOptions{
-		LabelSelector: ls,
-	}
-	return clientset.CoreV1().Nodes().List(context.TODO(), options)
+	return distroMatch
 }
 
-func MapToLabelSelector(selector map[string]string) string {
-	selectorSlice := make([]string, 0)
-	for k, v := range selector {
-		selectorSlice = append(selectorSlice, fmt.Sprintf("%s=%s", k, v))
-	}
-	return strings.Join(selectorSlice, ",")
+// GetReservedBuildArgs returns the build args managed by the operator which cannot be overridden by users
+func GetReservedBuildArgs() []string {
+	return []string{"DRIVERS_VERSION", "REPO_URL", "BASE_IMAGE_REGISTRY", "SOURCE_IMAGE_REPO", "RHEL_VERSION", caBundlePathsBuildArg,
+		"INSTALLER_PACKAGE_URL", "IONIC_BUILD", "ROCM_BUILD"}
 }
 
 func GetVersionLabelKV(nwConfig *amdv1alpha1.NetworkConfig) (string, string) {
@@ -621,16 +636,3 @@ func getNodeSelector(nwConfig *amdv1alpha1.NetworkConfig) map[string]string {
 	ns[utils.NodeFeatureLabelAmdNic] = "true"
 	return ns
 }
-
-func getKmodsToSign(isOpenShift bool, kernelVersion string) []string {
-	if isOpenShift {
-		return []string{} // TODO add support for signing in OpenShift
-	}
-	return []string{
-		"/opt/lib/modules/" + kernelVersion + "/updates/dkms/ib_peer_mem.ko",
-		"/opt/lib/modules/" + kernelVersion + "/updates/dkms/ionic.ko",
-		"/opt/lib/modules/" + kernelVersion + "/updates/dkms/ionic_rdma.ko",
-		"/opt/lib/modules/" + kernelVersion + "/kernel/drivers/infiniband/core/ib_core.ko",
-		"/opt/lib/modules/" + kernelVersion + "/kernel/drivers/infiniband/core/ib_uverbs.ko",
-	}
-}
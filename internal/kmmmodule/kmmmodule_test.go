@@ -75,6 +75,12 @@ var (
 	}
 )
 
+func defaultOSProfiles() *OSProfiles {
+	profiles, err := DefaultOSProfiles()
+	Expect(err).To(BeNil())
+	return profiles
+}
+
 var _ = Describe("BaseImageRegistry and BaseImageRegistryTLS", func() {
 	It("should pass BASE_IMAGE_REGISTRY build arg with default value", func() {
 		node := testNodeList.Items[0]
@@ -90,7 +96,7 @@ var _ = Describe("BaseImageRegistry and BaseImageRegistryTLS", func() {
 			},
 		}
 
-		km, _, err := getKM(nwConfig, node, "", false)
+		km, _, err := getKM(nwConfig, node, "", false, defaultOSProfiles())
 
 		Expect(err).To(BeNil())
 		Expect(km.Build).NotTo(BeNil())
@@ -124,7 +130,7 @@ var _ = Describe("BaseImageRegistry and BaseImageRegistryTLS", func() {
 			},
 		}
 
-		km, _, err := getKM(nwConfig, node, "", false)
+		km, _, err := getKM(nwConfig, node, "", false, defaultOSProfiles())
 
 		Expect(err).To(BeNil())
 		Expect(km.Build).NotTo(BeNil())
@@ -164,7 +170,7 @@ var _ = Describe("BaseImageRegistry and BaseImageRegistryTLS", func() {
 			},
 		}
 
-		km, _, err := getKM(nwConfig, node, "", false)
+		km, _, err := getKM(nwConfig, node, "", false, defaultOSProfiles())
 
 		Expect(err).To(BeNil())
 		Expect(km.Build).NotTo(BeNil())
@@ -190,7 +196,7 @@ var _ = Describe("BaseImageRegistry and BaseImageRegistryTLS", func() {
 		os.Setenv("CI_ENV", "1")
 		defer os.Unsetenv("CI_ENV")
 
-		km, _, err := getKM(nwConfig, node, "", false)
+		km, _, err := getKM(nwConfig, node, "", false, defaultOSProfiles())
 
 		Expect(err).To(BeNil())
 		Expect(km.Build).NotTo(BeNil())
@@ -213,7 +219,7 @@ var _ = Describe("BaseImageRegistry and BaseImageRegistryTLS", func() {
 			},
 		}
 
-		km, _, err := getKM(nwConfig, node, "ionic", true) // isOpenShift = true
+		km, _, err := getKM(nwConfig, node, "ionic", true, defaultOSProfiles()) // isOpenShift = true
 
 		Expect(err).To(BeNil())
 		Expect(km.Build).NotTo(BeNil())
@@ -247,7 +253,7 @@ var _ = Describe("getKernelMappings", func() {
 	}
 
 	It("should set InTreeModulesToRemove to ionic on OpenShift", func() {
-		kms, _, err := getKernelMappings(newNetworkConfig(), true, testNodeList)
+		kms, _, err := getKernelMappings(newNetworkConfig(), true, testNodeList, defaultOSProfiles())
 		Expect(err).To(BeNil())
 		Expect(kms).NotTo(BeEmpty())
 		for _, km := range kms {
@@ -256,13 +262,82 @@ var _ = Describe("getKernelMappings", func() {
 	})
 
 	It("should not set InTreeModulesToRemove on Kubernetes", func() {
-		kms, _, err := getKernelMappings(newNetworkConfig(), false, testNodeList)
+		kms, _, err := getKernelMappings(newNetworkConfig(), false, testNodeList, defaultOSProfiles())
 		Expect(err).To(BeNil())
 		Expect(kms).NotTo(BeEmpty())
 		for _, km := range kms {
 			Expect(km.InTreeModulesToRemove).To(BeNil())
 		}
 	})
+
+	unsupportedNode := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "unsupported-node",
+		},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{
+				Architecture:  "amd64",
+				OSImage:       "Unknown Linux 1.0",
+				KernelVersion: "5.0.0-1-unknown",
+			},
+		},
+	}
+	mixedNodeList := &v1.NodeList{
+		Items: append([]v1.Node{unsupportedNode}, testNodeList.Items...),
+	}
+
+	It("should fail on unsupported nodes by default", func() {
+		_, _, err := getKernelMappings(newNetworkConfig(), false, mixedNodeList, defaultOSProfiles())
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should skip unsupported nodes with Skip policy", func() {
+		nwConfig := newNetworkConfig()
+		nwConfig.Spec.Driver.UnsupportedNodePolicy = amdv1alpha1.UnsupportedNodePolicySkip
+		kms, _, err := getKernelMappings(nwConfig, false, mixedNodeList, defaultOSProfiles())
+		Expect(err).To(BeNil())
+		Expect(kms).To(HaveLen(1))
+		Expect(kms[0].Literal).To(Equal(testNodeList.Items[0].Status.NodeInfo.KernelVersion))
+
+		unsupported := getUnsupportedNodes(nwConfig, false, mixedNodeList, defaultOSProfiles())
+		Expect(unsupported).To(HaveLen(1))
+		Expect(unsupported).To(HaveKey("unsupported-node"))
+	})
+
+	It("should use a regexp mapping for matching kernels", func() {
+		nwConfig := newNetworkConfig()
+		nwConfig.Spec.Driver.KernelMappings = []amdv1alpha1.KernelMappingSpec{
+			{Regexp: `^6\.8\.0-[0-9]+-generic$`},
+		}
+		kms, _, err := getKernelMappings(nwConfig, false, testNodeList, defaultOSProfiles())
+		Expect(err).To(BeNil())
+		Expect(kms).To(HaveLen(1))
+		Expect(kms[0].Literal).To(BeEmpty())
+		Expect(kms[0].Regexp).To(Equal(`^6\.8\.0-[0-9]+-generic$`))
+	})
+
+	It("should reject a regexp mapping matching the kernels of several OSes", func() {
+		nwConfig := newNetworkConfig()
+		nwConfig.Spec.Driver.KernelMappings = []amdv1alpha1.KernelMappingSpec{
+			{Regexp: `^6\.8\.0-[0-9]+-generic$`},
+		}
+		noble := testNodeList.Items[0].DeepCopy()
+		noble.Name = "noble-node"
+		noble.Status.NodeInfo.OSImage = "Ubuntu 24.04.1 LTS"
+		noble.Status.NodeInfo.KernelVersion = "6.8.0-51-generic"
+		nodes := &v1.NodeList{Items: append([]v1.Node{*noble}, testNodeList.Items...)}
+
+		_, _, err := getKernelMappings(nwConfig, false, nodes, defaultOSProfiles())
+		Expect(err).To(MatchError(ContainSubstring("use a kernel mapping regexp per OS")))
+
+		nwConfig.Spec.Driver.KernelMappings = []amdv1alpha1.KernelMappingSpec{
+			{Regexp: `^6\.8\.0-51-generic$`},
+			{Regexp: `^6\.8\.0-40-generic$`},
+		}
+		kms, _, err := getKernelMappings(nwConfig, false, nodes, defaultOSProfiles())
+		Expect(err).To(BeNil())
+		Expect(kms).To(HaveLen(2))
+	})
 })
 
 var _ = PDescribe("setKMMModuleLoader", func() {
@@ -417,3 +492,162 @@ var _ = PDescribe("setKMMModuleLoader", func() {
 		Expect(mod).To(Equal(expectedMod))
 	})
 })
+
+var _ = Describe("OSProfiles", func() {
+	newNode := func(osImage string) v1.Node {
+		return v1.Node{
+			Status: v1.NodeStatus{
+				NodeInfo: v1.NodeSystemInfo{
+					OSImage: osImage,
+				},
+			},
+		}
+	}
+
+	It("should match the built-in profiles", func() {
+		profiles := defaultOSProfiles()
+		for osImage, expected := range map[string]string{
+			"Ubuntu 20.04.6 LTS": "ubuntu-20.04",
+			"Ubuntu 22.04.3 LTS": "ubuntu-22.04",
+			"Ubuntu 24.04.1 LTS": "ubuntu-24.04",
+			"Red Hat Enterprise Linux CoreOS 416.94.202410090804-0 (Plow)": "coreos-416.94",
+			"Red Hat Enterprise Linux 9.4 (Plow)":                          "rhel-9.4",
+		} {
+			osName, err := profiles.GetOSName(newNode(osImage))
+			Expect(err).To(BeNil())
+			Expect(osName).To(Equal(expected))
+		}
+		_, err := profiles.GetOSName(newNode("Ubuntu 18.04.6 LTS"))
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should load user defined profiles from ConfigMap", func() {
+		cm := &v1.ConfigMap{
+			Data: map[string]string{
+				"sles": `osImageRegex: 'sles (15\.\d+)'
+dockerfile: 'FROM registry.suse.com/bci/bci-base:$$VERSION'
+defaultDriverVersion: 1.117.1-a-42
+baseImageRegistry: registry.suse.com
+kmodsToSign:
+- /opt/lib/modules/${KERNEL_FULL_VERSION}/updates/ionic.ko
+`,
+			},
+		}
+		profiles, err := NewOSProfiles(cm)
+		Expect(err).To(BeNil())
+		node := newNode("SLES 15.5")
+		profile, version, err := profiles.Match(node)
+		Expect(err).To(BeNil())
+		Expect(profile.OSName(version)).To(Equal("sles-15.5"))
+		Expect(profile.ResolveDockerfile(version, false)).To(Equal("FROM registry.suse.com/bci/bci-base:15.5"))
+		Expect(profile.GetKmodsToSign("5.14.21")).To(Equal([]string{"/opt/lib/modules/5.14.21/updates/ionic.ko"}))
+		defaultVersion, err := profiles.GetDefaultDriversVersion(node)
+		Expect(err).To(BeNil())
+		Expect(defaultVersion).To(Equal("1.117.1-a-42"))
+
+		// built-in profiles are still available
+		osName, err := profiles.GetOSName(newNode("Ubuntu 22.04.3 LTS"))
+		Expect(err).To(BeNil())
+		Expect(osName).To(Equal("ubuntu-22.04"))
+	})
+
+	It("should sign the out-of-tree and in-tree kernel modules on CoreOS", func() {
+		profile, _, err := defaultOSProfiles().Match(newNode("Red Hat Enterprise Linux CoreOS 416.94.202410090804-0 (Plow)"))
+		Expect(err).To(BeNil())
+		Expect(profile.GetKmodsToSign("5.14.0-427.37.1.el9_4.x86_64")).To(ConsistOf(
+			"/opt/lib/modules/5.14.0-427.37.1.el9_4.x86_64/extra/ionic.ko",
+			"/opt/lib/modules/5.14.0-427.37.1.el9_4.x86_64/extra/ionic_rdma.ko",
+			"/opt/lib/modules/5.14.0-427.37.1.el9_4.x86_64/extra/pds_core.ko",
+			"/opt/lib/modules/5.14.0-427.37.1.el9_4.x86_64/extra/tawk_ipc.ko",
+			"/opt/lib/modules/5.14.0-427.37.1.el9_4.x86_64/kernel/drivers/infiniband/core/ib_core.ko",
+			"/opt/lib/modules/5.14.0-427.37.1.el9_4.x86_64/kernel/drivers/infiniband/core/ib_uverbs.ko",
+		))
+	})
+
+	It("should reject invalid user defined profiles", func() {
+		_, err := NewOSProfiles(&v1.ConfigMap{Data: map[string]string{"rocky": "osImageRegex: '('\ndockerfile: FROM rockylinux"}})
+		Expect(err).NotTo(BeNil())
+		// no kernel modules to sign
+		_, err = NewOSProfiles(&v1.ConfigMap{Data: map[string]string{"rocky": "osImageRegex: 'rocky (9\\.\\d+)'\ndockerfile: FROM rockylinux"}})
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("ImageBuild customization", func() {
+	It("should pass user Dockerfile, build args and secrets to KMM build", func() {
+		node := testNodeList.Items[0]
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-config",
+				Namespace: "test-ns",
+			},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				Driver: amdv1alpha1.DriverSpec{
+					Version: "6.2.2",
+					ImageBuild: amdv1alpha1.ImageBuildSpec{
+						Dockerfiles: []amdv1alpha1.DockerfileSpec{
+							{OS: "ubuntu", ConfigMap: v1.LocalObjectReference{Name: "ubuntu-dockerfile"}},
+							{OS: "ubuntu-22.04", ConfigMap: v1.LocalObjectReference{Name: "jammy-dockerfile"}},
+						},
+						BuildArgs: []amdv1alpha1.BuildArg{
+							{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:3128"},
+						},
+						BuildSecrets:    []v1.LocalObjectReference{{Name: "mirror-credentials"}},
+						CABundleSecrets: []v1.LocalObjectReference{{Name: "mirror-ca"}},
+					},
+				},
+			},
+		}
+
+		km, _, err := getKM(nwConfig, node, "", false, defaultOSProfiles())
+		Expect(err).To(BeNil())
+		Expect(km.Build.DockerfileConfigMap.Name).To(Equal("jammy-dockerfile"))
+		Expect(km.Build.BuildArgs).To(ContainElements(
+			kmmv1beta1.BuildArg{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:3128"},
+			kmmv1beta1.BuildArg{Name: "CA_BUNDLE_PATHS", Value: "/run/secrets/mirror-ca"},
+		))
+		Expect(km.Build.Secrets).To(ConsistOf(
+			v1.LocalObjectReference{Name: "mirror-ca"},
+			v1.LocalObjectReference{Name: "mirror-credentials"},
+		))
+	})
+})
+
+var _ = Describe("DevBuild", func() {
+	It("should render the dev Dockerfile without mutating the spec", func() {
+		nwConfig := &amdv1alpha1.NetworkConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-config",
+				Namespace: "test-ns",
+			},
+			Spec: amdv1alpha1.NetworkConfigSpec{
+				Driver: amdv1alpha1.DriverSpec{
+					Version:                    "6.2.2",
+					AMDNetworkInstallerRepoURL: "https://repo.example.com",
+					DevBuild: &amdv1alpha1.DevBuildSpec{
+						InstallerPackageURL: "https://artifactory.example.com/amdnetwork-install_1.0_all.deb",
+						IonicBuild:          "123",
+						ROCmBuild:           "compute-rocm-rel-6.4/43",
+					},
+				},
+			},
+		}
+		nwConfigCopy := nwConfig.DeepCopy()
+		profile, osVersion, err := defaultOSProfiles().Match(testNodeList.Items[0])
+		Expect(err).To(BeNil())
+
+		dockerfile, err := resolveDockerfile(profile, osVersion, nwConfig)
+		Expect(err).To(BeNil())
+		Expect(dockerfile).To(ContainSubstring("FROM ubuntu:22.04 as builder"))
+		Expect(dockerfile).To(ContainSubstring("${INSTALLER_PACKAGE_URL}"))
+
+		km, _, err := getKM(nwConfig, testNodeList.Items[0], "", false, defaultOSProfiles())
+		Expect(err).To(BeNil())
+		Expect(km.Build.BuildArgs).To(ContainElements(
+			kmmv1beta1.BuildArg{Name: "INSTALLER_PACKAGE_URL", Value: "https://artifactory.example.com/amdnetwork-install_1.0_all.deb"},
+			kmmv1beta1.BuildArg{Name: "IONIC_BUILD", Value: "123"},
+			kmmv1beta1.BuildArg{Name: "ROCM_BUILD", Value: "compute-rocm-rel-6.4/43"},
+		))
+		Expect(nwConfig).To(Equal(nwConfigCopy))
+	})
+})
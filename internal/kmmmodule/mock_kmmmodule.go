@@ -57,18 +57,47 @@ func (m *MockKMMModuleAPI) EXPECT() *MockKMMModuleAPIMockRecorder {
 	return m.recorder
 }
 
+// GetOSProfiles mocks base method.
+func (m *MockKMMModuleAPI) GetOSProfiles(ctx context.Context, nwConfig *v1alpha1.NetworkConfig) (*OSProfiles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOSProfiles", ctx, nwConfig)
+	ret0, _ := ret[0].(*OSProfiles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOSProfiles indicates an expected call of GetOSProfiles.
+func (mr *MockKMMModuleAPIMockRecorder) GetOSProfiles(ctx, nwConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOSProfiles", reflect.TypeOf((*MockKMMModuleAPI)(nil).GetOSProfiles), ctx, nwConfig)
+}
+
+// GetUnsupportedNodes mocks base method.
+func (m *MockKMMModuleAPI) GetUnsupportedNodes(ctx context.Context, nwConfig *v1alpha1.NetworkConfig, nodes *v1.NodeList) map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnsupportedNodes", ctx, nwConfig, nodes)
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// GetUnsupportedNodes indicates an expected call of GetUnsupportedNodes.
+func (mr *MockKMMModuleAPIMockRecorder) GetUnsupportedNodes(ctx, nwConfig, nodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnsupportedNodes", reflect.TypeOf((*MockKMMModuleAPI)(nil).GetUnsupportedNodes), ctx, nwConfig, nodes)
+}
+
 // SetBuildConfigMapAsDesired mocks base method.
-func (m *MockKMMModuleAPI) SetBuildConfigMapAsDesired(buildCM *v1.ConfigMap, nwConfig *v1alpha1.NetworkConfig) error {
+func (m *MockKMMModuleAPI) SetBuildConfigMapAsDesired(buildCM *v1.ConfigMap, nwConfig *v1alpha1.NetworkConfig, profile *OSProfile, osVersion string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetBuildConfigMapAsDesired", buildCM, nwConfig)
+	ret := m.ctrl.Call(m, "SetBuildConfigMapAsDesired", buildCM, nwConfig, profile, osVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetBuildConfigMapAsDesired indicates an expected call of SetBuildConfigMapAsDesired.
-func (mr *MockKMMModuleAPIMockRecorder) SetBuildConfigMapAsDesired(buildCM, nwConfig any) *gomock.Call {
+func (mr *MockKMMModuleAPIMockRecorder) SetBuildConfigMapAsDesired(buildCM, nwConfig, profile, osVersion any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBuildConfigMapAsDesired", reflect.TypeOf((*MockKMMModuleAPI)(nil).SetBuildConfigMapAsDesired), buildCM, nwConfig)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBuildConfigMapAsDesired", reflect.TypeOf((*MockKMMModuleAPI)(nil).SetBuildConfigMapAsDesired), buildCM, nwConfig, profile, osVersion)
 }
 
 // SetKMMModuleAsDesired mocks base method.
@@ -0,0 +1,292 @@
+/*
+Copyright (c) Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kmmmodule
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultUbuntuDriversVersion = "1.117.1-a-42"
+	defaultRHELDriversVersion   = "1.117.1-a-42"
+	defaultDockerIORegistry     = "docker.io"
+	defaultRedHatRegistry       = "registry.access.redhat.com"
+	// kernelVersionPlaceholder is replaced by the node kernel version in the kmods to sign
+	kernelVersionPlaceholder = "${KERNEL_FULL_VERSION}"
+)
+
+var (
+	// out-of-tree kernel modules built into the driver image
+	outOfTreeKmods = []string{ionicModuleName, networkDriverModuleName, pdsCoreModuleName, tawkIPCModuleName}
+	// in-tree kernel modules ionic_rdma depends on, copied into the driver image
+	inTreeKmods = []string{"ib_core", "ib_uverbs"}
+
+	ubuntuKmodsToSign = append(
+		getKmodPaths("updates/dkms", append(outOfTreeKmods, "ib_peer_mem")),
+		getKmodPaths("kernel/drivers/infiniband/core", inTreeKmods)...)
+	rhelKmodsToSign = append(
+		getKmodPaths("extra", outOfTreeKmods),
+		getKmodPaths("kernel/drivers/infiniband/core", inTreeKmods)...)
+)
+
+// getKmodPaths returns the paths of the given kernel modules under the given directory of the driver image
+func getKmodPaths(dir string, kmods []string) []string {
+	paths := make([]string, 0, len(kmods))
+	for _, kmod := range kmods {
+		paths = append(paths, "/opt/lib/modules/"+kernelVersionPlaceholder+"/"+dir+"/"+kmod+".ko")
+	}
+	return paths
+}
+
+// GetOutOfTreeKmods returns the names of the out-of-tree kernel modules managed by the operator
+func GetOutOfTreeKmods() []string {
+	return append([]string{}, outOfTreeKmods...)
+}
+
+// OSProfile describes everything needed to build the driver image for one OS
+type OSProfile struct {
+	// Name is the OS distro name, used as the prefix of the build ConfigMap name and driver image tag
+	Name string `json:"name,omitempty"`
+	// OSImageRegex is matched against the lower case node OS image
+	// the first capture group, if any, is used as the OS version
+	OSImageRegex string `json:"osImageRegex"`
+	// Dockerfile is the template to build the driver image
+	// $$VERSION and $$MAJOR_VERSION are replaced by the OS version captured from the node OS image
+	Dockerfile string `json:"dockerfile"`
+	// SourceImageDockerfile is the template used instead of Dockerfile when spec.driver.useSourceImage is true
+	SourceImageDockerfile string `json:"sourceImageDockerfile,omitempty"`
+	// DefaultDriverVersion is used when spec.driver.version is not specified
+	DefaultDriverVersion string `json:"defaultDriverVersion,omitempty"`
+	// BaseImageRegistry is used when spec.driver.imageBuild.baseImageRegistry is left to default
+	BaseImageRegistry string `json:"baseImageRegistry,omitempty"`
+	// KmodsToSign are the kernel module paths in the driver image to be signed, required for user defined profiles
+	// ${KERNEL_FULL_VERSION} is replaced by the node kernel version
+	KmodsToSign []string `json:"kmodsToSign,omitempty"`
+
+	osImageRe *regexp.Regexp
+}
+
+// OSProfiles is an ordered list of OS profiles, the first matching profile wins
+type OSProfiles struct {
+	profiles []*OSProfile
+}
+
+func builtinOSProfiles() []*OSProfile {
+	return []*OSProfile{
+		{
+			Name:                 "ubuntu",
+			OSImageRegex:         `^ubuntu (20\.04)`,
+			Dockerfile:           strings.ReplaceAll(dockerfileTemplateUbuntu, "$$DRIVER_LABEL", "focal"),
+			DefaultDriverVersion: defaultUbuntuDriversVersion,
+			BaseImageRegistry:    defaultDockerIORegistry,
+			KmodsToSign:          ubuntuKmodsToSign,
+		},
+		{
+			Name:                 "ubuntu",
+			OSImageRegex:         `^ubuntu (22\.04)`,
+			Dockerfile:           strings.ReplaceAll(dockerfileTemplateUbuntu, "$$DRIVER_LABEL", "jammy"),
+			DefaultDriverVersion: defaultUbuntuDriversVersion,
+			BaseImageRegistry:    defaultDockerIORegistry,
+			KmodsToSign:          ubuntuKmodsToSign,
+		},
+		{
+			Name:                 "ubuntu",
+			OSImageRegex:         `^ubuntu (24\.04)`,
+			Dockerfile:           strings.ReplaceAll(dockerfileTemplateUbuntu, "$$DRIVER_LABEL", "noble"),
+			DefaultDriverVersion: defaultUbuntuDriversVersion,
+			BaseImageRegistry:    defaultDockerIORegistry,
+			KmodsToSign:          ubuntuKmodsToSign,
+		},
+		{
+			// coreos must be checked before RHEL as its OS image also contains "red hat enterprise linux"
+			Name:                  "coreos",
+			OSImageRegex:          `coreos[^0-9]*(\d+\.\d+)`,
+			Dockerfile:            dockerfileTemplateCoreOSFromRPM,
+			SourceImageDockerfile: dockerfileTemplateCoreOSFromSrcImage,
+			DefaultDriverVersion:  defaultOcDriversVersion,
+			BaseImageRegistry:     defaultRedHatRegistry,
+			// both RPM and source image Dockerfiles put the built modules under extra
+			KmodsToSign: rhelKmodsToSign,
+		},
+		{
+			Name:                 "rhel",
+			OSImageRegex:         `(?:red ?hat enterprise linux|rhel)[^0-9]*(9\.\d+)`,
+			Dockerfile:           dockerfileTemplateRHEL,
+			DefaultDriverVersion: defaultRHELDriversVersion,
+			BaseImageRegistry:    defaultRedHatRegistry,
+			KmodsToSign:          rhelKmodsToSign,
+		},
+	}
+}
+
+// DefaultOSProfiles returns the OS profiles built into the operator
+func DefaultOSProfiles() (*OSProfiles, error) {
+	return newOSProfiles(nil)
+}
+
+// NewOSProfiles returns the user defined OS profiles from the given ConfigMap followed by the built-in profiles
+// each ConfigMap data key holds one OS profile in YAML format, the key is used as the profile name if not specified
+func NewOSProfiles(cm *v1.ConfigMap) (*OSProfiles, error) {
+	userProfiles := []*OSProfile{}
+	if cm != nil {
+		keys := make([]string, 0, len(cm.Data))
+		for key := range cm.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			profile := &OSProfile{}
+			if err := yaml.Unmarshal([]byte(cm.Data[key]), profile); err != nil {
+				return nil, fmt.Errorf("failed to parse OS profile %s: %v", key, err)
+			}
+			if profile.Name == "" {
+				profile.Name = key
+			}
+			// the driver image can't be signed without the kernel module paths
+			if len(profile.KmodsToSign) == 0 {
+				return nil, fmt.Errorf("OS profile %s: kmodsToSign is required", profile.Name)
+			}
+			userProfiles = append(userProfiles, profile)
+		}
+	}
+	return newOSProfiles(userProfiles)
+}
+
+func newOSProfiles(userProfiles []*OSProfile) (*OSProfiles, error) {
+	profiles := &OSProfiles{}
+	for _, profile := range append(userProfiles, builtinOSProfiles()...) {
+		if errs := validation.IsDNS1123Label(profile.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid OS profile name %s: %v", profile.Name, strings.Join(errs, ", "))
+		}
+		if profile.Dockerfile == "" {
+			return nil, fmt.Errorf("OS profile %s: dockerfile is required", profile.Name)
+		}
+		re, err := regexp.Compile(profile.OSImageRegex)
+		if err != nil || profile.OSImageRegex == "" {
+			return nil, fmt.Errorf("OS profile %s: invalid osImageRegex %s: %v", profile.Name, profile.OSImageRegex, err)
+		}
+		profile.osImageRe = re
+		profiles.profiles = append(profiles.profiles, profile)
+	}
+	return profiles, nil
+}
+
+// LoadOSProfiles returns the OS profiles for the given NetworkConfig
+// including the user defined profiles from the ConfigMap referenced by spec.driver.osProfiles
+func LoadOSProfiles(ctx context.Context, c client.Reader, nwConfig *amdv1alpha1.NetworkConfig) (*OSProfiles, error) {
+	if nwConfig.Spec.Driver.OSProfiles == nil || nwConfig.Spec.Driver.OSProfiles.Name == "" {
+		return DefaultOSProfiles()
+	}
+	cm := &v1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: nwConfig.Namespace, Name: nwConfig.Spec.Driver.OSProfiles.Name}, cm); err != nil {
+		return nil, fmt.Errorf("failed to get OS profiles ConfigMap %s: %v", nwConfig.Spec.Driver.OSProfiles.Name, err)
+	}
+	return NewOSProfiles(cm)
+}
+
+// Match returns the first OS profile matching the node OS image and the captured OS version
+func (p *OSProfiles) Match(node v1.Node) (*OSProfile, string, error) {
+	osImageStr := strings.ToLower(node.Status.NodeInfo.OSImage)
+	for _, profile := range p.profiles {
+		matches := profile.osImageRe.FindStringSubmatch(osImageStr)
+		if matches == nil {
+			continue
+		}
+		version := ""
+		if len(matches) > 1 {
+			version = matches[1]
+		}
+		return profile, version, nil
+	}
+	return nil, "", fmt.Errorf("OS: %s not supported. Should be one of %v", osImageStr, p.names())
+}
+
+// GetOSName returns the <distro>-<version> name of the node OS, e.g. ubuntu-22.04
+func (p *OSProfiles) GetOSName(node v1.Node) (string, error) {
+	profile, version, err := p.Match(node)
+	if err != nil {
+		return "", err
+	}
+	return profile.OSName(version), nil
+}
+
+// GetDefaultDriversVersion returns the default driver version for the node OS
+func (p *OSProfiles) GetDefaultDriversVersion(node v1.Node) (string, error) {
+	profile, _, err := p.Match(node)
+	if err != nil {
+		return "", err
+	}
+	if profile.DefaultDriverVersion == "" {
+		return "", fmt.Errorf("OS profile %s has no default driver version, please specify spec.driver.version", profile.Name)
+	}
+	return profile.DefaultDriverVersion, nil
+}
+
+// GetDriverVersion returns the driver version from NetworkConfig or the default driver version for the node OS
+func (p *OSProfiles) GetDriverVersion(node v1.Node, nwConfig amdv1alpha1.NetworkConfig) (string, error) {
+	if nwConfig.Spec.Driver.Version != "" {
+		return nwConfig.Spec.Driver.Version, nil
+	}
+	return p.GetDefaultDriversVersion(node)
+}
+
+func (p *OSProfiles) names() []string {
+	names := []string{}
+	for _, profile := range p.profiles {
+		names = append(names, fmt.Sprintf("%s(%s)", profile.Name, profile.OSImageRegex))
+	}
+	return names
+}
+
+// OSName returns the <distro>-<version> name for the given OS version
+func (o *OSProfile) OSName(version string) string {
+	if version == "" {
+		return o.Name
+	}
+	return o.Name + "-" + version
+}
+
+// ResolveDockerfile returns the Dockerfile for the given OS version
+func (o *OSProfile) ResolveDockerfile(version string, useSourceImage bool) string {
+	dockerfile := o.Dockerfile
+	if useSourceImage && o.SourceImageDockerfile != "" {
+		dockerfile = o.SourceImageDockerfile
+	}
+	majorVersion := strings.Split(version, ".")[0]
+	dockerfile = strings.ReplaceAll(dockerfile, "$$MAJOR_VERSION", majorVersion)
+	return strings.ReplaceAll(dockerfile, "$$VERSION", version)
+}
+
+// GetKmodsToSign returns the kernel module paths to sign for the given kernel version
+func (o *OSProfile) GetKmodsToSign(kernelVersion string) []string {
+	kmods := make([]string, 0, len(o.KmodsToSign))
+	for _, kmod := range o.KmodsToSign {
+		kmods = append(kmods, strings.ReplaceAll(kmod, kernelVersionPlaceholder, kernelVersion))
+	}
+	return kmods
+}
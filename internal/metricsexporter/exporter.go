@@ -19,7 +19,9 @@ package exporterinternal
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 
@@ -37,8 +39,80 @@ const (
 	defaultMetricsExporterImage       = "docker.io/rocm/device-metrics-exporter:nic-v1.2.0"
 	defaultInitContainerImage         = "busybox:1.36"
 	svcLabel                          = "app.kubernetes.io/service"
+	metricsConfigMountPath            = "/etc/metrics/"
 )
 
+// managedExporterFlags are rendered from the typed exporter options or by the common exporter
+var managedExporterFlags = []string{"monitor-nic", "monitor-gpu", "amd-metrics-config", "bind"}
+
+// GetExporterPort returns the port the metrics are served on by the pods, the service and the ServiceMonitor
+func GetExporterPort(nwConfig *amdv1alpha1.NetworkConfig) int32 {
+	if nwConfig.Spec.MetricsExporter.Port > 0 {
+		return nwConfig.Spec.MetricsExporter.Port
+	}
+	return exporterServicePort
+}
+
+// GetExporterListenPort returns the port the exporter container listens on,
+// which differs from the exporter port when kube-rbac-proxy fronts it
+func GetExporterListenPort(nwConfig *amdv1alpha1.NetworkConfig) int32 {
+	specIn := &nwConfig.Spec.MetricsExporter
+	port := GetExporterPort(nwConfig)
+	if specIn.RbacConfig.Enable == nil || !*specIn.RbacConfig.Enable {
+		return port
+	}
+	if specIn.Options != nil && specIn.Options.InternalPort > 0 {
+		return specIn.Options.InternalPort
+	}
+	if port != exporterServicePort {
+		return exporterServicePort
+	}
+	return port - 1
+}
+
+// ValidateExporterOptions validates the metrics exporter options against the rest of the exporter spec
+func ValidateExporterOptions(nwConfig *amdv1alpha1.NetworkConfig) error {
+	specIn := &nwConfig.Spec.MetricsExporter
+	opts := specIn.Options
+	if opts == nil {
+		return nil
+	}
+	if opts.MonitorNIC != nil && !*opts.MonitorNIC && (opts.MonitorGPU == nil || !*opts.MonitorGPU) {
+		return fmt.Errorf("Options: at least one of monitorNIC and monitorGPU must be enabled")
+	}
+	if opts.ConfigKey != "" && specIn.Config.Name == "" {
+		return fmt.Errorf("Options: configKey requires config.name")
+	}
+	if opts.InternalPort > 0 && opts.InternalPort == GetExporterPort(nwConfig) {
+		return fmt.Errorf("Options: internalPort %d must differ from port", opts.InternalPort)
+	}
+	for _, arg := range opts.ExtraArgs {
+		name := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)[0]
+		if slices.Contains(managedExporterFlags, name) {
+			return fmt.Errorf("Options: extraArgs must not set %s, use the typed options", name)
+		}
+	}
+	return nil
+}
+
+func getExporterArguments(nwConfig *amdv1alpha1.NetworkConfig) []string {
+	specIn := &nwConfig.Spec.MetricsExporter
+	opts := specIn.Options
+	if opts == nil {
+		opts = &amdv1alpha1.MetricsExporterOptions{}
+	}
+	monitorNIC := opts.MonitorNIC == nil || *opts.MonitorNIC
+	monitorGPU := opts.MonitorGPU != nil && *opts.MonitorGPU
+	args := []string{
+		fmt.Sprintf("-monitor-nic=%t", monitorNIC),
+		fmt.Sprintf("-monitor-gpu=%t", monitorGPU),
+	}
+	if specIn.Config.Name != "" && opts.ConfigKey != "" {
+		args = append(args, "-amd-metrics-config="+metricsConfigMountPath+opts.ConfigKey)
+	}
+	return append(args, opts.ExtraArgs...)
+}
+
 func GenerateCommonExporterSpec(nwConfig *amdv1alpha1.NetworkConfig) *protos.MetricsExporterSpec {
 	var specOut protos.MetricsExporterSpec
 	specIn := &nwConfig.Spec.MetricsExporter
@@ -48,13 +122,17 @@ func GenerateCommonExporterSpec(nwConfig *amdv1alpha1.NetworkConfig) *protos.Met
 	specOut.DsSpec.Namespace = nwConfig.Namespace
 	specOut.DsSpec.Enable = specIn.Enable
 	specOut.DsSpec.ServiceAccountName = exporterSAName
-	specOut.DsSpec.Tolerations = specIn.Tolerations
+	specOut.DsSpec.Tolerations = utils.WithNICUnhealthyToleration(specIn.Tolerations)
 	specOut.DsSpec.UpgradePolicy = (*protos.DaemonSetUpgradeSpec)(specIn.UpgradePolicy.DeepCopy())
 
-	specOut.SvcSpec.Port = specIn.Port
+	specOut.SvcSpec.Port = GetExporterPort(nwConfig)
 	specOut.SvcSpec.SvcType = protos.ServiceType(specIn.SvcType)
 	specOut.SvcSpec.NodePort = specIn.NodePort
+	// the exporter listens on the service port behind kube-rbac-proxy, or on port - 1 when both are equal
 	specOut.SvcSpec.ServicePort = exporterServicePort
+	if specIn.Options != nil && specIn.Options.InternalPort > 0 {
+		specOut.SvcSpec.ServicePort = specIn.Options.InternalPort
+	}
 	specOut.Config = protos.MetricsConfig(specIn.Config)
 	specOut.RbacConfig = protos.KubeRbacConfig{
 		Enable:            specIn.RbacConfig.Enable,
@@ -70,6 +148,11 @@ func GenerateCommonExporterSpec(nwConfig *amdv1alpha1.NetworkConfig) *protos.Met
 			SecretName: nwConfig.Name + "-" + StaticAuthSecretName,
 		}
 	}
+	if utils.IsKubeRbacAutoTLSEnable(nwConfig) {
+		// serve and verify the clients with the certificates issued by the operator
+		specOut.RbacConfig.Secret = &v1.LocalObjectReference{Name: GetServingTLSSecretName(nwConfig)}
+		specOut.RbacConfig.ClientCAConfigMap = &v1.LocalObjectReference{Name: GetClientCAConfigMapName(nwConfig)}
+	}
 
 	serviceMonitorLabelPair := []string{"app", "amd-device-metrics-exporter"}
 	// Copy Prometheus configuration if present
@@ -95,6 +178,10 @@ func GenerateCommonExporterSpec(nwConfig *amdv1alpha1.NetworkConfig) *protos.Met
 			for k, v := range smIn.Labels {
 				smOut.Labels[k] = v
 			}
+			if utils.IsKubeRbacAutoTLSEnable(nwConfig) {
+				smOut.TLSConfig = GetServiceMonitorTLSConfig(smIn.TLSConfig, nwConfig)
+			}
+
 			// Add default "app" label if user did not override
 			if _, exists := smOut.Labels["app"]; !exists {
 				smOut.Labels[serviceMonitorLabelPair[0]] = serviceMonitorLabelPair[1]
@@ -160,7 +247,7 @@ func GenerateCommonExporterSpec(nwConfig *amdv1alpha1.NetworkConfig) *protos.Met
 	specOut.DsSpec.MainContainer.ImageRegistrySecret = specIn.ImageRegistrySecret
 	specOut.DsSpec.MainContainer.IsPrivileged = true
 	specOut.DsSpec.MainContainer.IsHostNetwork = *specIn.HostNetwork
-	specOut.DsSpec.MainContainer.Arguments = []string{"-monitor-nic=true", "-monitor-gpu=false"}
+	specOut.DsSpec.MainContainer.Arguments = getExporterArguments(nwConfig)
 
 	// Exporter Specifc Values
 	specOut.DsSpec.MainContainer.Envs = []v1.EnvVar{
@@ -173,9 +260,16 @@ func GenerateCommonExporterSpec(nwConfig *amdv1alpha1.NetworkConfig) *protos.Met
 			},
 		},
 		{
-			Name: "METRICS_EXPORTER_PORT",
+			Name:  "METRICS_EXPORTER_PORT",
+			Value: strconv.Itoa(int(GetExporterListenPort(nwConfig))),
 		},
 	}
+	if specIn.Options != nil && specIn.Options.LogLevel != "" {
+		specOut.DsSpec.MainContainer.Envs = append(specOut.DsSpec.MainContainer.Envs, v1.EnvVar{
+			Name:  "LOG_LEVEL",
+			Value: specIn.Options.LogLevel,
+		})
+	}
 
 	hostPathDirectory := v1.HostPathDirectory
 	hostPathDirectoryOrCreate := v1.HostPathDirectoryOrCreate
@@ -223,7 +317,7 @@ func GenerateCommonExporterSpec(nwConfig *amdv1alpha1.NetworkConfig) *protos.Met
 	if specIn.Config.Name != "" {
 		specOut.DsSpec.MainContainer.VolumeMounts = append(specOut.DsSpec.MainContainer.VolumeMounts, v1.VolumeMount{
 			Name:      "metrics-config-volume",
-			MountPath: "/etc/metrics/",
+			MountPath: metricsConfigMountPath,
 		})
 	}
 	specOut.DsSpec.Volumes = []v1.Volume{
@@ -0,0 +1,232 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporterinternal
+
+import (
+	"fmt"
+	"regexp"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+	dpinternal "github.com/ROCm/network-operator/internal/deviceplugin"
+)
+
+const (
+	// the Grafana sidecar downloads the content of the keys with the .url suffix from the URL they hold
+	GrafanaDashboardKey     = "amd-ainic-dashboard.json.url"
+	grafanaDashboardSuffix  = "-dashboard"
+	grafanaFolderAnnotation = "grafana_folder"
+	ruleGroupName           = "amd-nic.rules"
+)
+
+var (
+	// default labels of the ConfigMap watched by the Grafana dashboard sidecar
+	defaultGrafanaDashboardLabels = map[string]string{"grafana_dashboard": "1"}
+
+	// kube-state-metrics replaces the characters of the resource names not allowed in label values
+	resourceLabelRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// alertDefaults are the settings of an alert when not set in the NetworkConfig
+type alertDefaults struct {
+	name      string
+	forPeriod string
+	severity  string
+	threshold int32
+}
+
+// GetPrometheusRuleName returns the name of the PrometheusRule of the NetworkConfig
+func GetPrometheusRuleName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return nwConfig.Name + "-" + ExporterName
+}
+
+// GetGrafanaDashboardName returns the name of the Grafana dashboard ConfigMap of the NetworkConfig
+func GetGrafanaDashboardName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return nwConfig.Name + "-" + ExporterName + grafanaDashboardSuffix
+}
+
+// getNICResourceRegex returns the resource label values of the AMD NIC resources in kube-state-metrics
+func getNICResourceRegex(nwConfig *amdv1alpha1.NetworkConfig) string {
+	prefix := resourceLabelRegex.ReplaceAllString(dpinternal.GetResourcePrefix(nwConfig), "_")
+	return prefix + "_v?nic.*"
+}
+
+func newAlertRule(cfg *amdv1alpha1.AlertRuleConfig, def alertDefaults, expr func(threshold int32) string, summary, description string) (monitoringv1.Rule, bool) {
+	if cfg == nil {
+		cfg = &amdv1alpha1.AlertRuleConfig{}
+	}
+	if cfg.Enable != nil && !*cfg.Enable {
+		return monitoringv1.Rule{}, false
+	}
+	forPeriod := def.forPeriod
+	if cfg.For != "" {
+		forPeriod = cfg.For
+	}
+	severity := def.severity
+	if cfg.Severity != "" {
+		severity = cfg.Severity
+	}
+	threshold := def.threshold
+	if cfg.Threshold != nil {
+		threshold = *cfg.Threshold
+	}
+	duration := monitoringv1.Duration(forPeriod)
+	return monitoringv1.Rule{
+		Alert: def.name,
+		Expr:  intstr.FromString(expr(threshold)),
+		For:   &duration,
+		Labels: map[string]string{
+			"severity": severity,
+		},
+		Annotations: map[string]string{
+			"summary":     summary,
+			"description": description,
+		},
+	}, true
+}
+
+// SetPrometheusRuleAsDesired renders the alert rules of the AMD NICs with the thresholds of the NetworkConfig
+func SetPrometheusRuleAsDesired(rule *monitoringv1.PrometheusRule, nwConfig *amdv1alpha1.NetworkConfig) error {
+	if rule == nil {
+		return fmt.Errorf("PrometheusRule is not initialized, zero pointer")
+	}
+	ruleCfg := nwConfig.Spec.MetricsExporter.Prometheus.Rules
+	ns := nwConfig.Namespace
+	exporterName := nwConfig.Name + "-" + ExporterName
+	devicePluginName := nwConfig.Name + "-" + dpinternal.DevicePluginName
+	nicResources := getNICResourceRegex(nwConfig)
+
+	linkDown := ruleCfg.LinkDown
+	if linkDown == nil {
+		linkDown = &amdv1alpha1.LinkDownAlertConfig{}
+	}
+	ports := ".*"
+	if linkDown.Ports != "" {
+		ports = linkDown.Ports
+	}
+
+	rules := []monitoringv1.Rule{}
+	candidates := []func() (monitoringv1.Rule, bool){
+		func() (monitoringv1.Rule, bool) {
+			return newAlertRule(&linkDown.AlertRuleConfig,
+				alertDefaults{name: "AMDNICLinkDown", forPeriod: "1m", severity: "critical"},
+				func(int32) string {
+					// a port without link doesn't receive any frame, not even the link level control frames
+					return fmt.Sprintf(`sum by (hostname, port_name) (rate(nic_port_stats_frames_rx_all{port_name=~%q}[5m])) == 0`, ports)
+				},
+				"AMD NIC port link down",
+				"Port {{ $labels.port_name }} on {{ $labels.hostname }} did not receive any frame in 5 minutes.")
+		},
+		func() (monitoringv1.Rule, bool) {
+			return newAlertRule(ruleCfg.RDMAErrors,
+				alertDefaults{name: "AMDNICRDMAErrors", forPeriod: "5m", severity: "warning", threshold: 10},
+				func(threshold int32) string {
+					return fmt.Sprintf(`sum by (hostname, rdma_dev_name) (increase(rdma_req_rx_cqe_err[5m]) + increase(rdma_req_tx_loc_err[5m])) > %d`, threshold)
+				},
+				"AMD NIC RDMA errors",
+				"RDMA device {{ $labels.rdma_dev_name }} on {{ $labels.hostname }} reported {{ $value }} completion and local errors in 5 minutes.")
+		},
+		func() (monitoringv1.Rule, bool) {
+			return newAlertRule(ruleCfg.ExporterDown,
+				alertDefaults{name: "AMDNICMetricsExporterDown", forPeriod: "5m", severity: "critical"},
+				func(int32) string {
+					return fmt.Sprintf(`up{namespace=%q,job=%q} == 0 or kube_daemonset_status_number_unavailable{namespace=%q,daemonset=%q} > 0`,
+						ns, exporterName, ns, exporterName)
+				},
+				"AMD NIC metrics exporter down",
+				fmt.Sprintf("The metrics exporter %s/%s is not scraped or has unavailable pods.", ns, exporterName))
+		},
+		func() (monitoringv1.Rule, bool) {
+			return newAlertRule(ruleCfg.DriverUpgradeStuck,
+				alertDefaults{name: "AMDNICDriverUpgradeStuck", forPeriod: "1h", severity: "warning"},
+				func(int32) string {
+					return fmt.Sprintf(`max by (namespace, pod) (kube_pod_status_phase{namespace=%q,pod=~%q,phase=~"Pending|Running|Failed"}) == 1`,
+						ns, "worker-"+regexp.QuoteMeta(nwConfig.Name)+"-.*|amd-network-operator-.*-reboot-worker")
+				},
+				"AMD NIC driver upgrade stuck",
+				"The driver upgrade pod {{ $labels.pod }} did not complete.")
+		},
+		func() (monitoringv1.Rule, bool) {
+			return newAlertRule(ruleCfg.DevicePluginUnhealthy,
+				alertDefaults{name: "AMDNICDevicePluginUnhealthy", forPeriod: "10m", severity: "warning"},
+				func(threshold int32) string {
+					return fmt.Sprintf(`sum by (node) (kube_node_status_capacity{resource=~%q}) - sum by (node) (kube_node_status_allocatable{resource=~%q}) > %d`+
+						` or kube_daemonset_status_number_unavailable{namespace=%q,daemonset=%q} > 0`,
+						nicResources, nicResources, threshold, ns, devicePluginName)
+				},
+				"AMD NIC device plugin unhealthy",
+				"NIC resources are unhealthy on {{ $labels.node }} or device plugin pods are unavailable.")
+		},
+	}
+	for _, candidate := range candidates {
+		if r, ok := candidate(); ok {
+			rules = append(rules, r)
+		}
+	}
+
+	rule.Labels = map[string]string{
+		utils.CRNameLabel: nwConfig.Name,
+	}
+	for k, v := range ruleCfg.Labels {
+		rule.Labels[k] = v
+	}
+	rule.Spec = monitoringv1.PrometheusRuleSpec{
+		Groups: []monitoringv1.RuleGroup{
+			{
+				Name:  ruleGroupName,
+				Rules: rules,
+			},
+		},
+	}
+	return nil
+}
+
+// SetGrafanaDashboardAsDesired points the Grafana dashboard sidecar to the AINIC dashboard of the device-metrics-exporter
+func SetGrafanaDashboardAsDesired(cm *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig) error {
+	if cm == nil {
+		return fmt.Errorf("ConfigMap is not initialized, zero pointer")
+	}
+	dashboardCfg := nwConfig.Spec.MetricsExporter.Prometheus.GrafanaDashboard
+
+	labels := defaultGrafanaDashboardLabels
+	if len(dashboardCfg.Labels) > 0 {
+		labels = dashboardCfg.Labels
+	}
+	cm.Labels = map[string]string{
+		utils.CRNameLabel: nwConfig.Name,
+	}
+	for k, v := range labels {
+		cm.Labels[k] = v
+	}
+	if dashboardCfg.Folder != "" {
+		if cm.Annotations == nil {
+			cm.Annotations = map[string]string{}
+		}
+		cm.Annotations[grafanaFolderAnnotation] = dashboardCfg.Folder
+	} else {
+		delete(cm.Annotations, grafanaFolderAnnotation)
+	}
+
+	cm.Data = map[string]string{
+		GrafanaDashboardKey: dashboardCfg.URL,
+	}
+	return nil
+}
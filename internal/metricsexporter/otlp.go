@@ -0,0 +1,298 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporterinternal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+)
+
+const (
+	// OTLPCollectorName is the name of the OpenTelemetry Collector sidecar pushing the metrics
+	OTLPCollectorName = "otel-collector"
+	// OTLPConfigKey is the key of the collector config within the ConfigMap
+	OTLPConfigKey = "config.yaml"
+	// OTLPConfigHashAnnotation is set on the metrics exporter pod template to roll the pods when the collector config changes
+	OTLPConfigHashAnnotation = "network.operator.amd.com/otlp-config-hash"
+
+	defaultOTLPCollectorImage = "docker.io/otel/opentelemetry-collector-contrib:0.120.0"
+	defaultOTLPInterval       = "60s"
+	otlpConfigVolume          = "otlp-config"
+	otlpConfigMountPath       = "/etc/otelcol/"
+	otlpCAVolume              = "otlp-ca"
+	otlpCAMountPath           = "/etc/otelcol/tls/ca/"
+	otlpCertVolume            = "otlp-cert"
+	otlpCertMountPath         = "/etc/otelcol/tls/client/"
+	otlpHeaderEnvPrefix       = "OTLP_HEADER_"
+	otlpNodeNameEnv           = "K8S_NODE_NAME"
+)
+
+// GetOTLPConfigMapName returns the name of the collector ConfigMap rendered for the NetworkConfig
+func GetOTLPConfigMapName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s-otlp", nwConfig.Name, ExporterName)
+}
+
+// ValidateOTLPConfig validates the OTLP push configuration of the metrics exporter
+func ValidateOTLPConfig(nwConfig *amdv1alpha1.NetworkConfig) error {
+	if !utils.IsOTLPEnable(nwConfig) {
+		return nil
+	}
+	otlp := nwConfig.Spec.MetricsExporter.OTLP
+	if otlp.Endpoint == "" {
+		return fmt.Errorf("OTLP: endpoint is required")
+	}
+	if otlp.Protocol == "http" {
+		u, err := url.Parse(otlp.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("OTLP: endpoint %s must be an http or https URL for the http protocol", otlp.Endpoint)
+		}
+		if otlp.TLS != nil && otlp.TLS.Insecure {
+			return fmt.Errorf("OTLP: tls.insecure is only supported for the grpc protocol, use an http:// endpoint")
+		}
+	}
+	if otlp.HeadersSecret != nil && otlp.HeadersSecret.Name == "" {
+		return fmt.Errorf("OTLP: headersSecret.name is required")
+	}
+	if otlp.TLS != nil {
+		if otlp.TLS.CASecret != nil && otlp.TLS.CASecret.Name == "" {
+			return fmt.Errorf("OTLP: tls.caSecret.name is required")
+		}
+		if otlp.TLS.CertSecret != nil && otlp.TLS.CertSecret.Name == "" {
+			return fmt.Errorf("OTLP: tls.certSecret.name is required")
+		}
+	}
+	if utils.IsPrometheusServiceMonitorEnable(nwConfig) && !utils.IsMetricsServiceEnable(nwConfig) {
+		return fmt.Errorf("OTLP: the ServiceMonitor requires keepService")
+	}
+	return nil
+}
+
+// GenerateOTLPCollectorConfig renders the collector config scraping the exporter of the pod
+// and pushing to the OTLP endpoint, the header values are read from the environment
+func GenerateOTLPCollectorConfig(nwConfig *amdv1alpha1.NetworkConfig, headerKeys []string) (string, error) {
+	otlp := nwConfig.Spec.MetricsExporter.OTLP
+	interval := otlp.Interval
+	if interval == "" {
+		interval = defaultOTLPInterval
+	}
+
+	attributes := []map[string]interface{}{
+		{"key": "k8s.node.name", "value": "${env:" + otlpNodeNameEnv + "}", "action": "upsert"},
+	}
+	attrKeys := make([]string, 0, len(otlp.ResourceAttributes))
+	for k := range otlp.ResourceAttributes {
+		attrKeys = append(attrKeys, k)
+	}
+	sort.Strings(attrKeys)
+	for _, k := range attrKeys {
+		attributes = append(attributes, map[string]interface{}{"key": k, "value": otlp.ResourceAttributes[k], "action": "upsert"})
+	}
+
+	exporterName := "otlp"
+	if otlp.Protocol == "http" {
+		exporterName = "otlphttp"
+	}
+	exporter := map[string]interface{}{"endpoint": otlp.Endpoint}
+	if len(headerKeys) > 0 {
+		headers := map[string]string{}
+		for i, k := range headerKeys {
+			headers[k] = "${env:" + otlpHeaderEnvPrefix + strconv.Itoa(i) + "}"
+		}
+		exporter["headers"] = headers
+	}
+	if otlp.TLS != nil {
+		tls := map[string]interface{}{}
+		if otlp.TLS.Insecure {
+			tls["insecure"] = true
+		}
+		if otlp.TLS.InsecureSkipVerify {
+			tls["insecure_skip_verify"] = true
+		}
+		if otlp.TLS.CASecret != nil {
+			tls["ca_file"] = otlpCAMountPath + "ca.crt"
+		}
+		if otlp.TLS.CertSecret != nil {
+			tls["cert_file"] = otlpCertMountPath + v1.TLSCertKey
+			tls["key_file"] = otlpCertMountPath + v1.TLSPrivateKeyKey
+		}
+		if otlp.TLS.ServerName != "" {
+			tls["server_name_override"] = otlp.TLS.ServerName
+		}
+		if len(tls) > 0 {
+			exporter["tls"] = tls
+		}
+	}
+
+	config := map[string]interface{}{
+		"receivers": map[string]interface{}{
+			"prometheus": map[string]interface{}{
+				"config": map[string]interface{}{
+					"scrape_configs": []map[string]interface{}{
+						{
+							"job_name":        fmt.Sprintf("%s-%s", nwConfig.Name, ExporterName),
+							"scrape_interval": interval,
+							"static_configs": []map[string]interface{}{
+								{"targets": []string{fmt.Sprintf("127.0.0.1:%d", GetExporterListenPort(nwConfig))}},
+							},
+						},
+					},
+				},
+			},
+		},
+		"processors": map[string]interface{}{
+			"resource": map[string]interface{}{"attributes": attributes},
+			"batch":    map[string]interface{}{},
+		},
+		"exporters": map[string]interface{}{
+			exporterName: exporter,
+		},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"receivers":  []string{"prometheus"},
+					"processors": []string{"resource", "batch"},
+					"exporters":  []string{exporterName},
+				},
+			},
+		},
+	}
+	configBytes, err := yaml.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal OTLP collector config: %v", err)
+	}
+	return string(configBytes), nil
+}
+
+// SetOTLPConfigMapAsDesired renders the collector config into the ConfigMap
+// and returns the config hash to be set on the metrics exporter pod template
+func SetOTLPConfigMapAsDesired(cm *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig, headerKeys []string) (string, error) {
+	config, err := GenerateOTLPCollectorConfig(nwConfig, headerKeys)
+	if err != nil {
+		return "", err
+	}
+	cm.Data = map[string]string{
+		OTLPConfigKey: config,
+	}
+	hash := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// SetOTLPCollectorAsDesired adds the OpenTelemetry Collector sidecar to the metrics exporter DaemonSet
+func SetOTLPCollectorAsDesired(ds *appsv1.DaemonSet, nwConfig *amdv1alpha1.NetworkConfig, headerKeys []string, configHash string) {
+	specIn := &nwConfig.Spec.MetricsExporter
+	otlp := specIn.OTLP
+	image := otlp.Image
+	if image == "" {
+		image = defaultOTLPCollectorImage
+	}
+
+	env := []v1.EnvVar{
+		{
+			Name: otlpNodeNameEnv,
+			ValueFrom: &v1.EnvVarSource{
+				FieldRef: &v1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+			},
+		},
+	}
+	for i, k := range headerKeys {
+		env = append(env, v1.EnvVar{
+			Name: otlpHeaderEnvPrefix + strconv.Itoa(i),
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: *otlp.HeadersSecret,
+					Key:                  k,
+				},
+			},
+		})
+	}
+
+	mounts := []v1.VolumeMount{{Name: otlpConfigVolume, MountPath: otlpConfigMountPath, ReadOnly: true}}
+	volumes := []v1.Volume{
+		{
+			Name: otlpConfigVolume,
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{Name: GetOTLPConfigMapName(nwConfig)},
+				},
+			},
+		},
+	}
+	if otlp.TLS != nil && otlp.TLS.CASecret != nil {
+		mounts = append(mounts, v1.VolumeMount{Name: otlpCAVolume, MountPath: otlpCAMountPath, ReadOnly: true})
+		volumes = append(volumes, v1.Volume{
+			Name:         otlpCAVolume,
+			VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: otlp.TLS.CASecret.Name}},
+		})
+	}
+	if otlp.TLS != nil && otlp.TLS.CertSecret != nil {
+		mounts = append(mounts, v1.VolumeMount{Name: otlpCertVolume, MountPath: otlpCertMountPath, ReadOnly: true})
+		volumes = append(volumes, v1.Volume{
+			Name:         otlpCertVolume,
+			VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: otlp.TLS.CertSecret.Name}},
+		})
+	}
+
+	collector := v1.Container{
+		Name:            OTLPCollectorName,
+		Image:           image,
+		ImagePullPolicy: v1.PullPolicy(specIn.ImagePullPolicy),
+		Args:            []string{"--config=" + otlpConfigMountPath + OTLPConfigKey},
+		Env:             env,
+		VolumeMounts:    mounts,
+	}
+
+	podSpec := &ds.Spec.Template.Spec
+	replaced := false
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == OTLPCollectorName {
+			podSpec.Containers[i] = collector
+			replaced = true
+		}
+	}
+	if !replaced {
+		podSpec.Containers = append(podSpec.Containers, collector)
+	}
+	for _, vol := range volumes {
+		replaced = false
+		for i := range podSpec.Volumes {
+			if podSpec.Volumes[i].Name == vol.Name {
+				podSpec.Volumes[i] = vol
+				replaced = true
+			}
+		}
+		if !replaced {
+			podSpec.Volumes = append(podSpec.Volumes, vol)
+		}
+	}
+
+	// roll the exporter pods when the collector config changes, the collector only reads it on start
+	if ds.Spec.Template.Annotations == nil {
+		ds.Spec.Template.Annotations = map[string]string{}
+	}
+	ds.Spec.Template.Annotations[OTLPConfigHashAnnotation] = configHash
+}
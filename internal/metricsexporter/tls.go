@@ -0,0 +1,455 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exporterinternal
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+)
+
+const (
+	// KubeRbacCAHashAnnotation is set on the metrics exporter pod template to roll the pods when the client CA changes
+	KubeRbacCAHashAnnotation = "network.operator.amd.com/rbac-proxy-ca-hash"
+	// CACertKey is the key of the CA certificate in the issued secrets and the client CA ConfigMap
+	CACertKey = "ca.crt"
+
+	autoTLSProviderCertManager  = "cert-manager"
+	defaultAutoTLSDurationHours = 2160
+	caDuration                  = 10 * 365 * 24 * time.Hour
+	certManagerGroup            = "cert-manager.io"
+)
+
+var (
+	// CertificateGVK is the cert-manager Certificate kind, used as unstructured as the cert-manager API isn't vendored
+	CertificateGVK = schema.GroupVersionKind{Group: certManagerGroup, Version: "v1", Kind: "Certificate"}
+	// IssuerGVK is the cert-manager Issuer kind
+	IssuerGVK = schema.GroupVersionKind{Group: certManagerGroup, Version: "v1", Kind: "Issuer"}
+)
+
+// GetCASecretName returns the name of the secret holding the CA issuing the kube-rbac-proxy certificates
+func GetCASecretName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s-ca", nwConfig.Name, ExporterName)
+}
+
+// GetServingTLSSecretName returns the name of the secret holding the kube-rbac-proxy serving certificate
+func GetServingTLSSecretName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s-tls", nwConfig.Name, ExporterName)
+}
+
+// GetClientTLSSecretName returns the name of the secret holding the client certificate used by Prometheus
+func GetClientTLSSecretName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s-client-tls", nwConfig.Name, ExporterName)
+}
+
+// GetClientCAConfigMapName returns the name of the ConfigMap holding the CA kube-rbac-proxy verifies the clients with
+func GetClientCAConfigMapName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s-client-ca", nwConfig.Name, ExporterName)
+}
+
+// GetSelfSignedIssuerName returns the name of the cert-manager issuer self signing the CA
+func GetSelfSignedIssuerName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s-selfsigned", nwConfig.Name, ExporterName)
+}
+
+// GetCAIssuerName returns the name of the cert-manager CA issuer and of the CA Certificate
+func GetCAIssuerName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return GetCASecretName(nwConfig)
+}
+
+// IsCertManagerProvider returns true if the kube-rbac-proxy certificates are issued by cert-manager
+func IsCertManagerProvider(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	return nwConfig.Spec.MetricsExporter.RbacConfig.AutoTLS.Provider == autoTLSProviderCertManager
+}
+
+// IsOwnCAIssuer returns true if the cert-manager CA issuer is owned by the operator
+func IsOwnCAIssuer(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	return nwConfig.Spec.MetricsExporter.RbacConfig.AutoTLS.IssuerRef == nil
+}
+
+// GetServingDNSNames returns the DNS names of the metrics service the serving certificate is issued for
+func GetServingDNSNames(nwConfig *amdv1alpha1.NetworkConfig) []string {
+	svc := fmt.Sprintf("%s-%s", nwConfig.Name, ExporterName)
+	return []string{
+		svc,
+		fmt.Sprintf("%s.%s", svc, nwConfig.Namespace),
+		fmt.Sprintf("%s.%s.svc", svc, nwConfig.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", svc, nwConfig.Namespace),
+	}
+}
+
+// GetClientCommonName returns the common name of the client certificate, the static authorization client name when set
+func GetClientCommonName(nwConfig *amdv1alpha1.NetworkConfig) string {
+	static := nwConfig.Spec.MetricsExporter.RbacConfig.StaticAuthorization
+	if static != nil && static.Enable && static.ClientName != "" {
+		return static.ClientName
+	}
+	return fmt.Sprintf("%s-%s-client", nwConfig.Name, ExporterName)
+}
+
+// GetAutoTLSDuration returns the validity of the kube-rbac-proxy certificates
+func GetAutoTLSDuration(nwConfig *amdv1alpha1.NetworkConfig) time.Duration {
+	hours := nwConfig.Spec.MetricsExporter.RbacConfig.AutoTLS.DurationHours
+	if hours <= 0 {
+		hours = defaultAutoTLSDurationHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// ValidateAutoTLSConfig validates the certificates issued by the operator against the rest of the kube-rbac-proxy config
+func ValidateAutoTLSConfig(nwConfig *amdv1alpha1.NetworkConfig) error {
+	if !utils.IsKubeRbacAutoTLSEnable(nwConfig) {
+		return nil
+	}
+	rbac := &nwConfig.Spec.MetricsExporter.RbacConfig
+	if rbac.Enable == nil || !*rbac.Enable {
+		return fmt.Errorf("RbacConfig: autoTLS requires rbacConfig.enable")
+	}
+	if rbac.DisableHttps != nil && *rbac.DisableHttps {
+		return fmt.Errorf("RbacConfig: autoTLS can't be used with disableHttps")
+	}
+	if rbac.Secret != nil || rbac.ClientCAConfigMap != nil {
+		return fmt.Errorf("RbacConfig: autoTLS can't be used with secret or clientCAConfigMap")
+	}
+	if rbac.AutoTLS.IssuerRef != nil {
+		if !IsCertManagerProvider(nwConfig) {
+			return fmt.Errorf("RbacConfig: autoTLS issuerRef requires the cert-manager provider")
+		}
+		if rbac.AutoTLS.IssuerRef.Name == "" {
+			return fmt.Errorf("RbacConfig: autoTLS issuerRef.name is required")
+		}
+	}
+	return nil
+}
+
+// GetServiceMonitorTLSConfig returns the ServiceMonitor TLS config with the CA, the client certificate and the server name
+// of the certificates issued by the operator, the fields set by the user are kept
+func GetServiceMonitorTLSConfig(tlsConfig *monitoringv1.TLSConfig, nwConfig *amdv1alpha1.NetworkConfig) *monitoringv1.TLSConfig {
+	if tlsConfig == nil {
+		tlsConfig = &monitoringv1.TLSConfig{}
+	} else {
+		tlsConfig = tlsConfig.DeepCopy()
+	}
+	if tlsConfig.CA == (monitoringv1.SecretOrConfigMap{}) && tlsConfig.CAFile == "" {
+		tlsConfig.CA.Secret = &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: GetServingTLSSecretName(nwConfig)},
+			Key:                  CACertKey,
+		}
+	}
+	if tlsConfig.Cert == (monitoringv1.SecretOrConfigMap{}) && tlsConfig.CertFile == "" && tlsConfig.KeySecret == nil && tlsConfig.KeyFile == "" {
+		tlsConfig.Cert.Secret = &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: GetClientTLSSecretName(nwConfig)},
+			Key:                  v1.TLSCertKey,
+		}
+		tlsConfig.KeySecret = &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: GetClientTLSSecretName(nwConfig)},
+			Key:                  v1.TLSPrivateKeyKey,
+		}
+	}
+	if tlsConfig.ServerName == nil {
+		// the targets are scraped by IP, verify the service name of the serving certificate
+		tlsConfig.ServerName = ptr.To(GetServingDNSNames(nwConfig)[2])
+	}
+	return tlsConfig
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM encoded certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseKey(keyPEM []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded key")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// needsRenewal returns true if the certificate can't be parsed, has a third of its validity left,
+// doesn't match the expected names or isn't signed by the CA
+func needsRenewal(certPEM []byte, ca *x509.Certificate, commonName string, dnsNames []string, now time.Time) bool {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return true
+	}
+	if now.After(cert.NotAfter.Add(-cert.NotAfter.Sub(cert.NotBefore) / 3)) {
+		return true
+	}
+	if cert.Subject.CommonName != commonName || !slices.Equal(cert.DNSNames, dnsNames) {
+		return true
+	}
+	return ca != nil && cert.CheckSignatureFrom(ca) != nil
+}
+
+// GenerateCA returns a self signed CA certificate and its key, PEM encoded
+func GenerateCA(commonName string, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate CA key: %v", err)
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate CA serial number: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caDuration),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CA certificate: %v", err)
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode CA key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// IssueCertificate returns a certificate signed by the CA and its key, PEM encoded
+func IssueCertificate(caCertPEM, caKeyPEM []byte, commonName string, dnsNames []string, usage x509.ExtKeyUsage, duration time.Duration, now time.Time) ([]byte, []byte, error) {
+	ca, err := parseCertificate(caCertPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CA certificate: %v", err)
+	}
+	caKey, err := parseKey(caKeyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CA key: %v", err)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate serial number: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(duration),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create certificate %s: %v", commonName, err)
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// SetCASecretAsDesired keeps the internal CA of the secret, or generates it when missing or expiring
+func SetCASecretAsDesired(secret *v1.Secret, nwConfig *amdv1alpha1.NetworkConfig, now time.Time) error {
+	commonName := fmt.Sprintf("%s-%s-ca", nwConfig.Name, ExporterName)
+	_, keyErr := parseKey(secret.Data[v1.TLSPrivateKeyKey])
+	if keyErr != nil || needsRenewal(secret.Data[v1.TLSCertKey], nil, commonName, nil, now) {
+		certPEM, keyPEM, err := GenerateCA(commonName, now)
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			v1.TLSCertKey:       certPEM,
+			v1.TLSPrivateKeyKey: keyPEM,
+			CACertKey:           certPEM,
+		}
+	}
+	if secret.CreationTimestamp.IsZero() {
+		secret.Type = v1.SecretTypeTLS
+	}
+	setAutoTLSLabels(&secret.ObjectMeta.Labels, nwConfig)
+	return nil
+}
+
+// SetServingTLSSecretAsDesired issues the kube-rbac-proxy serving certificate from the internal CA when missing or expiring
+func SetServingTLSSecretAsDesired(secret *v1.Secret, caSecret *v1.Secret, nwConfig *amdv1alpha1.NetworkConfig, now time.Time) error {
+	return setIssuedSecretAsDesired(secret, caSecret, nwConfig, GetServingDNSNames(nwConfig)[2], GetServingDNSNames(nwConfig), x509.ExtKeyUsageServerAuth, now)
+}
+
+// SetClientTLSSecretAsDesired issues the Prometheus client certificate from the internal CA when missing or expiring
+func SetClientTLSSecretAsDesired(secret *v1.Secret, caSecret *v1.Secret, nwConfig *amdv1alpha1.NetworkConfig, now time.Time) error {
+	return setIssuedSecretAsDesired(secret, caSecret, nwConfig, GetClientCommonName(nwConfig), nil, x509.ExtKeyUsageClientAuth, now)
+}
+
+func setIssuedSecretAsDesired(secret *v1.Secret, caSecret *v1.Secret, nwConfig *amdv1alpha1.NetworkConfig, commonName string,
+	dnsNames []string, usage x509.ExtKeyUsage, now time.Time) error {
+	caCertPEM := caSecret.Data[v1.TLSCertKey]
+	ca, err := parseCertificate(caCertPEM)
+	if err != nil {
+		return fmt.Errorf("failed to parse CA secret %s: %v", caSecret.Name, err)
+	}
+	if needsRenewal(secret.Data[v1.TLSCertKey], ca, commonName, dnsNames, now) || !bytes.Equal(secret.Data[CACertKey], caCertPEM) {
+		certPEM, keyPEM, err := IssueCertificate(caCertPEM, caSecret.Data[v1.TLSPrivateKeyKey], commonName, dnsNames, usage, GetAutoTLSDuration(nwConfig), now)
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			v1.TLSCertKey:       certPEM,
+			v1.TLSPrivateKeyKey: keyPEM,
+			CACertKey:           caCertPEM,
+		}
+	}
+	if secret.CreationTimestamp.IsZero() {
+		secret.Type = v1.SecretTypeTLS
+	}
+	setAutoTLSLabels(&secret.ObjectMeta.Labels, nwConfig)
+	return nil
+}
+
+// SetClientCAConfigMapAsDesired sets the CA kube-rbac-proxy verifies the client certificates with
+// and returns its hash to be set on the metrics exporter pod template
+func SetClientCAConfigMapAsDesired(cm *v1.ConfigMap, nwConfig *amdv1alpha1.NetworkConfig, caCertPEM []byte) string {
+	cm.Data = map[string]string{
+		CACertKey: string(caCertPEM),
+	}
+	setAutoTLSLabels(&cm.ObjectMeta.Labels, nwConfig)
+	hash := sha256.Sum256(caCertPEM)
+	return hex.EncodeToString(hash[:])
+}
+
+func setAutoTLSLabels(labels *map[string]string, nwConfig *amdv1alpha1.NetworkConfig) {
+	if *labels == nil {
+		*labels = map[string]string{}
+	}
+	(*labels)[utils.CRNameLabel] = nwConfig.Name
+}
+
+// getCertManagerIssuerRef returns the issuer of the kube-rbac-proxy certificates
+func getCertManagerIssuerRef(nwConfig *amdv1alpha1.NetworkConfig) map[string]interface{} {
+	ref := nwConfig.Spec.MetricsExporter.RbacConfig.AutoTLS.IssuerRef
+	if ref == nil {
+		return map[string]interface{}{"name": GetCAIssuerName(nwConfig), "kind": IssuerGVK.Kind, "group": certManagerGroup}
+	}
+	issuerRef := map[string]interface{}{"name": ref.Name, "kind": IssuerGVK.Kind, "group": certManagerGroup}
+	if ref.Kind != "" {
+		issuerRef["kind"] = ref.Kind
+	}
+	if ref.Group != "" {
+		issuerRef["group"] = ref.Group
+	}
+	return issuerRef
+}
+
+func setCertManagerSpec(obj *unstructured.Unstructured, nwConfig *amdv1alpha1.NetworkConfig, spec map[string]interface{}) error {
+	labels := obj.GetLabels()
+	setAutoTLSLabels(&labels, nwConfig)
+	obj.SetLabels(labels)
+	return unstructured.SetNestedMap(obj.Object, spec, "spec")
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	out := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		out = append(out, v)
+	}
+	return out
+}
+
+// SetSelfSignedIssuerAsDesired sets the cert-manager issuer self signing the CA
+func SetSelfSignedIssuerAsDesired(issuer *unstructured.Unstructured, nwConfig *amdv1alpha1.NetworkConfig) error {
+	return setCertManagerSpec(issuer, nwConfig, map[string]interface{}{"selfSigned": map[string]interface{}{}})
+}
+
+// SetCACertificateAsDesired sets the cert-manager Certificate of the CA issuing the kube-rbac-proxy certificates
+func SetCACertificateAsDesired(cert *unstructured.Unstructured, nwConfig *amdv1alpha1.NetworkConfig) error {
+	return setCertManagerSpec(cert, nwConfig, map[string]interface{}{
+		"isCA":        true,
+		"commonName":  fmt.Sprintf("%s-%s-ca", nwConfig.Name, ExporterName),
+		"secretName":  GetCASecretName(nwConfig),
+		"duration":    caDuration.String(),
+		"privateKey":  map[string]interface{}{"algorithm": "ECDSA", "size": int64(256)},
+		"issuerRef":   map[string]interface{}{"name": GetSelfSignedIssuerName(nwConfig), "kind": IssuerGVK.Kind, "group": certManagerGroup},
+		"renewBefore": (caDuration / 3).String(),
+	})
+}
+
+// SetCAIssuerAsDesired sets the cert-manager issuer signing with the CA of the operator
+func SetCAIssuerAsDesired(issuer *unstructured.Unstructured, nwConfig *amdv1alpha1.NetworkConfig) error {
+	return setCertManagerSpec(issuer, nwConfig, map[string]interface{}{"ca": map[string]interface{}{"secretName": GetCASecretName(nwConfig)}})
+}
+
+// SetServingCertificateAsDesired sets the cert-manager Certificate of the kube-rbac-proxy serving certificate
+func SetServingCertificateAsDesired(cert *unstructured.Unstructured, nwConfig *amdv1alpha1.NetworkConfig) error {
+	return setIssuedCertificateAsDesired(cert, nwConfig, GetServingTLSSecretName(nwConfig), GetServingDNSNames(nwConfig)[2], GetServingDNSNames(nwConfig), "server auth")
+}
+
+// SetClientCertificateAsDesired sets the cert-manager Certificate of the Prometheus client certificate
+func SetClientCertificateAsDesired(cert *unstructured.Unstructured, nwConfig *amdv1alpha1.NetworkConfig) error {
+	return setIssuedCertificateAsDesired(cert, nwConfig, GetClientTLSSecretName(nwConfig), GetClientCommonName(nwConfig), nil, "client auth")
+}
+
+func setIssuedCertificateAsDesired(cert *unstructured.Unstructured, nwConfig *amdv1alpha1.NetworkConfig, secretName, commonName string, dnsNames []string, usage string) error {
+	duration := GetAutoTLSDuration(nwConfig)
+	spec := map[string]interface{}{
+		"secretName":  secretName,
+		"commonName":  commonName,
+		"duration":    duration.String(),
+		"renewBefore": (duration / 3).String(),
+		"privateKey":  map[string]interface{}{"algorithm": "ECDSA", "size": int64(256), "rotationPolicy": "Always"},
+		"usages":      []interface{}{"digital signature", "key encipherment", usage},
+		"issuerRef":   getCertManagerIssuerRef(nwConfig),
+	}
+	if len(dnsNames) > 0 {
+		spec["dnsNames"] = toInterfaceSlice(dnsNames)
+	}
+	return setCertManagerSpec(cert, nwConfig, spec)
+}
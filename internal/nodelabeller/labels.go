@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelabellerinternal
+
+import (
+	"fmt"
+	"strings"
+
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+)
+
+const (
+	labelDomain     = "amd.com/"
+	betaLabelDomain = "beta.amd.com/"
+	// nicLabelPrefix prefixes every label published by the node labeller
+	nicLabelPrefix = "nic"
+	// labelledNodeLabelTemplate marks the nodes labelled by the node labeller of a NetworkConfig,
+	// so that the labels can be cleaned up once the node is no longer selected
+	labelledNodeLabelTemplate = "network.operator.amd.com/%v.%v.node-labeller"
+)
+
+// labelFamily is a set of node labels the node labeller publishes when its flag is set
+type labelFamily struct {
+	flag string
+	// keys are the label names without domain, the labeller publishes them under both amd.com and beta.amd.com
+	keys []string
+}
+
+// LabelFamilies lists the label families of the node labeller in the order their flags are passed
+var LabelFamilies = []string{
+	"nicModel",
+	"firmwareVersion",
+	"driverVersion",
+	"ports",
+	"pcieGeneration",
+	"numaNode",
+	"rail",
+	"vfCount",
+}
+
+var labelFamilies = map[string]labelFamily{
+	"nicModel":        {flag: "nic-model", keys: []string{"nic.model"}},
+	"firmwareVersion": {flag: "firmware-version", keys: []string{"nic.firmware-version"}},
+	"driverVersion":   {flag: "driver-version", keys: []string{"nic.driver-version"}},
+	"ports":           {flag: "ports", keys: []string{"nic.port-count", "nic.port-speed"}},
+	"pcieGeneration":  {flag: "pcie-generation", keys: []string{"nic.pcie-generation"}},
+	"numaNode":        {flag: "numa-node", keys: []string{"nic.numa-nodes"}},
+	"rail":            {flag: "rail", keys: []string{"nic.rails"}},
+	"vfCount":         {flag: "vf-count", keys: []string{"nic.vf-count"}},
+}
+
+// GetLabelledNodeLabel returns the label key marking the nodes labelled for the NetworkConfig
+func GetLabelledNodeLabel(nwConfig *amdv1alpha1.NetworkConfig) string {
+	return fmt.Sprintf(labelledNodeLabelTemplate, nwConfig.Namespace, nwConfig.Name)
+}
+
+// GetLabelFamilies returns the label families enabled on the NetworkConfig
+func GetLabelFamilies(nwConfig *amdv1alpha1.NetworkConfig) []string {
+	labels := GetNodeLabellerSpec(nwConfig).Labels
+	if len(labels) == 0 {
+		return LabelFamilies
+	}
+	enabled := map[string]bool{}
+	for _, family := range labels {
+		enabled[family] = true
+	}
+	families := []string{}
+	for _, family := range LabelFamilies {
+		if enabled[family] {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+// getNodeLabellerArgs returns one flag per enabled label family, or no flag when the labels are not selected
+// so that the node labeller images predating the flags keep publishing their default labels
+func getNodeLabellerArgs(nwConfig *amdv1alpha1.NetworkConfig) []string {
+	args := []string{}
+	if len(GetNodeLabellerSpec(nwConfig).Labels) == 0 {
+		return args
+	}
+	for _, family := range GetLabelFamilies(nwConfig) {
+		args = append(args, fmt.Sprintf("-%s", labelFamilies[family].flag))
+	}
+	return args
+}
+
+// getLabelName strips the amd.com or beta.amd.com domain of a node labeller label, ok is false for other labels
+func getLabelName(labelKey string) (string, bool) {
+	for _, domain := range []string{betaLabelDomain, labelDomain} {
+		if name, found := strings.CutPrefix(labelKey, domain); found {
+			return name, strings.HasPrefix(name, nicLabelPrefix)
+		}
+	}
+	return "", false
+}
+
+// IsNodeLabellerLabel returns true for the labels published by the node labeller
+func IsNodeLabellerLabel(labelKey string) bool {
+	_, ok := getLabelName(labelKey)
+	return ok
+}
+
+// getLabelFamily returns the family of a node labeller label, or an empty string for a label without family
+func getLabelFamily(labelKey string) string {
+	name, ok := getLabelName(labelKey)
+	if !ok {
+		return ""
+	}
+	for family, lf := range labelFamilies {
+		for _, key := range lf.keys {
+			// per device labels are suffixed with the device index, e.g. amd.com/nic.model.0
+			if name == key || strings.HasPrefix(name, key+".") {
+				return family
+			}
+		}
+	}
+	return ""
+}
+
+// GetStaleLabels returns the node labels of the label families disabled on the NetworkConfig
+func GetStaleLabels(nwConfig *amdv1alpha1.NetworkConfig, nodeLabels map[string]string) []string {
+	enabled := map[string]bool{}
+	for _, family := range GetLabelFamilies(nwConfig) {
+		enabled[family] = true
+	}
+	stale := []string{}
+	for key := range nodeLabels {
+		if family := getLabelFamily(key); family != "" && !enabled[family] {
+			stale = append(stale, key)
+		}
+	}
+	return stale
+}
@@ -25,6 +25,7 @@ import (
 
 	protos "github.com/ROCm/common-infra-operator/pkg/protos"
 	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
+	hostconfiginternal "github.com/ROCm/network-operator/internal/hostconfig"
 )
 
 const (
@@ -35,24 +36,66 @@ const (
 	NodeLabellerNameSuffix      = "node-labeller"
 )
 
+// GetNodeLabellerSpec returns the nodeLabeller spec, its unset fields fall back to the deprecated fields of devicePlugin
+func GetNodeLabellerSpec(nwConfig *amdv1alpha1.NetworkConfig) amdv1alpha1.NodeLabellerSpec {
+	nlSpec := *nwConfig.Spec.NodeLabeller.DeepCopy()
+	legacy := &nwConfig.Spec.DevicePlugin
+	if nlSpec.Enable == nil {
+		nlSpec.Enable = legacy.EnableNodeLabeller
+	}
+	if nlSpec.Image == "" {
+		nlSpec.Image = legacy.NodeLabellerImage
+	}
+	if nlSpec.ImagePullPolicy == "" {
+		nlSpec.ImagePullPolicy = legacy.NodeLabellerImagePullPolicy
+	}
+	if nlSpec.ImageRegistrySecret == nil {
+		nlSpec.ImageRegistrySecret = legacy.ImageRegistrySecret
+	}
+	if len(nlSpec.Selector) == 0 {
+		nlSpec.Selector = nwConfig.Spec.Selector
+	}
+	if len(nlSpec.Tolerations) == 0 {
+		nlSpec.Tolerations = legacy.NodeLabellerTolerations
+	}
+	if nlSpec.UpgradePolicy == nil {
+		nlSpec.UpgradePolicy = legacy.UpgradePolicy
+	}
+	if len(nlSpec.Labels) == 0 {
+		nlSpec.Labels = legacy.NodeLabellerLabels
+	}
+	return nlSpec
+}
+
+// IsNodeLabellerEnabled returns true if the node labeller is enabled by nodeLabeller.enable or the deprecated devicePlugin.enableNodeLabeller
+func IsNodeLabellerEnabled(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	enable := GetNodeLabellerSpec(nwConfig).Enable
+	return enable != nil && *enable
+}
+
 func GenerateCommonNodeLabellerSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenShift bool) *protos.NodeLabellerSpec {
 	var nlOut protos.NodeLabellerSpec
-	specIn := &nwConfig.Spec.DevicePlugin
+	specIn := GetNodeLabellerSpec(nwConfig)
 	simEnabled, _ := strconv.ParseBool(os.Getenv("SIM_ENABLE"))
 
 	nlOut.Name = nwConfig.Name
 	nlOut.Namespace = nwConfig.Namespace
-	nlOut.Enable = specIn.EnableNodeLabeller
+	nlOut.Enable = specIn.Enable
 	nlOut.ServiceAccountName = nodeLabellerSAName
-	nlOut.Tolerations = specIn.NodeLabellerTolerations
+	nlOut.Tolerations = specIn.Tolerations
 	nlOut.UpgradePolicy = (*protos.DaemonSetUpgradeSpec)(specIn.UpgradePolicy.DeepCopy())
-	nlOut.Selector = nwConfig.Spec.Selector
+	nlOut.Selector = specIn.Selector
 
 	nlOut.InitContainers = make([]protos.InitContainerSpec, 1)
 	nlOut.InitContainers[0].IsPrivileged = true
 	nlOut.InitContainers[0].DefaultImage = defaultInitContainerImage
 	nlOut.InitContainers[0].Image = nwConfig.Spec.CommonConfig.InitContainerImage
-	nlOut.InitContainers[0].Command = getInitContainerCommand(simEnabled, isOpenShift, nwConfig.Spec.Driver.Blacklist)
+	blacklist := nwConfig.Spec.Driver.Blacklist
+	if hostconfiginternal.IsHostConfigEnabled(nwConfig) {
+		// the host config owns the blacklist, the init container only removes its legacy blacklist file
+		blacklist = nil
+	}
+	nlOut.InitContainers[0].Command = getInitContainerCommand(simEnabled, isOpenShift, blacklist)
 
 	nlOut.InitContainers[0].VolumeMounts = []v1.VolumeMount{
 		{
@@ -69,10 +112,11 @@ func GenerateCommonNodeLabellerSpec(nwConfig *amdv1alpha1.NetworkConfig, isOpenS
 	nlOut.MainContainer.DefaultImage = defaultNodeLabellerUbiImage
 	nlOut.MainContainer.DefaultUbiImage = defaultNodeLabellerUbiImage
 
-	nlOut.MainContainer.Image = specIn.NodeLabellerImage
-	nlOut.MainContainer.ImagePullPolicy = specIn.NodeLabellerImagePullPolicy
+	nlOut.MainContainer.Image = specIn.Image
+	nlOut.MainContainer.ImagePullPolicy = specIn.ImagePullPolicy
 	nlOut.MainContainer.ImageRegistrySecret = specIn.ImageRegistrySecret
 	nlOut.MainContainer.IsPrivileged = true
+	nlOut.MainContainer.Arguments = getNodeLabellerArgs(nwConfig)
 
 	hostPathDirectory := v1.HostPathDirectory
 	hostPathDirectoryOrCreate := v1.HostPathDirectoryOrCreate
@@ -17,6 +17,10 @@ limitations under the License.
 package secondarynetwork
 
 import (
+	_ "embed"
+	"strconv"
+	"strings"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,13 +35,45 @@ const (
 	defaultCNIPluginsImage = "docker.io/rocm/k8s-cni-plugins:v1.2.0"
 	CNIPluginsName         = "cni-plugins"
 	cniPluginsSAName       = "amd-network-operator-cni-plugins"
+	// CNIPluginsInstallContainer is the init container installing the CNI plugins, its termination message reports the install status
+	CNIPluginsInstallContainer = CNIPluginsName + "-install"
+)
+
+var (
+	cniPluginsLabelPair = []string{"app.kubernetes.io/name", CNIPluginsName}
+
+	// defaultCNIPlugins are installed when no plugin is listed
+	defaultCNIPlugins = []string{"amd-host-device", "host-device", "sbr", "tuning"}
+
+	//go:embed scripts/installCNIPluginsScript.sh
+	installCNIPluginsScript string
+
+	//go:embed scripts/clusterIPAMKubeconfigScript.sh
+	clusterIPAMKubeconfigScript string
 )
 
-var cniPluginsLabelPair = []string{"app.kubernetes.io/name", CNIPluginsName}
+// GetCNIPlugins returns the CNI plugins installed on the nodes
+func GetCNIPlugins(cniPluginsSpec *v1alpha1.CniPluginsSpec) []string {
+	if cniPluginsSpec == nil || len(cniPluginsSpec.Plugins) == 0 {
+		return defaultCNIPlugins
+	}
+	return cniPluginsSpec.Plugins
+}
+
+// ParseCNIPluginsStatus parses the install status of the CNI plugins from the termination message of the install container
+func ParseCNIPluginsStatus(message string) map[string]string {
+	plugins := map[string]string{}
+	for _, field := range strings.Fields(message) {
+		if plugin, status, ok := strings.Cut(field, "="); ok {
+			plugins[plugin] = status
+		}
+	}
+	return plugins
+}
 
 //go:generate mockgen -source=cniplugins.go -destination=mock_cniplugins.go -package=secondarynetwork SecondaryNetwork
 type SecondaryNetworkAPI interface {
-	SetCNIPluginsAsDesired(nwConfigName string, ds *appsv1.DaemonSet, cniPluginsSpec *v1alpha1.CniPluginsSpec, nodeSelector map[string]string) (*runtime.Scheme, error)
+	SetCNIPluginsAsDesired(nwConfigName string, ds *appsv1.DaemonSet, cniPluginsSpec *v1alpha1.CniPluginsSpec, nodeSelector map[string]string, clusterIPAM bool) (*runtime.Scheme, error)
 }
 
 type secondaryNetwork struct {
@@ -52,7 +88,7 @@ func NewSecondaryNetwork(scheme *runtime.Scheme, isOpenshift bool) SecondaryNetw
 	}
 }
 
-func (s *secondaryNetwork) SetCNIPluginsAsDesired(nwConfigName string, ds *appsv1.DaemonSet, cniPluginsSpec *v1alpha1.CniPluginsSpec, nodeSelector map[string]string) (*runtime.Scheme, error) {
+func (s *secondaryNetwork) SetCNIPluginsAsDesired(nwConfigName string, ds *appsv1.DaemonSet, cniPluginsSpec *v1alpha1.CniPluginsSpec, nodeSelector map[string]string, clusterIPAM bool) (*runtime.Scheme, error) {
 	cniPluginsImage := defaultCNIPluginsImage
 	if cniPluginsSpec.Image != "" {
 		cniPluginsImage = cniPluginsSpec.Image
@@ -91,20 +127,65 @@ func (s *secondaryNetwork) SetCNIPluginsAsDesired(nwConfigName string, ds *appsv
 		utils.CRNameLabel:      nwConfigName,
 	}
 
+	plugins := strings.Join(GetCNIPlugins(cniPluginsSpec), " ")
+	overwriteForeign := cniPluginsSpec.OverwriteForeignBinaries != nil && *cniPluginsSpec.OverwriteForeignBinaries
+
 	// Container needs privileged mode to write to /var/lib/cni/bin on OpenShift
 	privileged := s.isOpenshift
-	containers := []corev1.Container{
+	// the install container verifies and installs the listed plugins, the pod turns ready once they are installed
+	// its env changes with the plugin list, so the plugins are reinstalled on every change
+	initContainers := []corev1.Container{
 		{
-			Name:         CNIPluginsName + "-container",
-			WorkingDir:   "/root",
+			Name:         CNIPluginsInstallContainer,
 			Image:        cniPluginsImage,
+			Command:      []string{"sh", "-c", installCNIPluginsScript},
 			VolumeMounts: volumeMounts,
+			Env: []corev1.EnvVar{
+				{Name: "CNI_PLUGINS", Value: plugins},
+				{Name: "OVERWRITE_FOREIGN_BINARIES", Value: strconv.FormatBool(overwriteForeign)},
+			},
 			SecurityContext: &corev1.SecurityContext{
 				Privileged: &privileged,
 			},
 		},
 	}
+	containers := []corev1.Container{
+		{
+			Name:       CNIPluginsName + "-container",
+			WorkingDir: "/root",
+			Image:      cniPluginsImage,
+			Command:    []string{"sh", "-c", "sleep 2147483647"},
+		},
+	}
+	if clusterIPAM {
+		// keep the kubeconfig of the amd-host-device cluster IPAM up to date on the node
+		containers[0].Command = []string{"sh", "-c", clusterIPAMKubeconfigScript}
+		containers[0].Env = []corev1.EnvVar{
+			{
+				Name: "NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+				},
+			},
+		}
+		containers[0].VolumeMounts = []corev1.VolumeMount{
+			{
+				Name:      "cni-conf",
+				MountPath: "/host/etc/cni/net.d",
+			},
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: "cni-conf",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/etc/cni/net.d",
+					Type: &hostPathDirectoryOrCreate,
+				},
+			},
+		})
+	}
 	if cniPluginsSpec.ImagePullPolicy != "" {
+		initContainers[0].ImagePullPolicy = corev1.PullPolicy(cniPluginsSpec.ImagePullPolicy)
 		containers[0].ImagePullPolicy = corev1.PullPolicy(cniPluginsSpec.ImagePullPolicy)
 	}
 
@@ -115,6 +196,7 @@ func (s *secondaryNetwork) SetCNIPluginsAsDesired(nwConfigName string, ds *appsv
 
 	gracePeriod := int64(1)
 	podSpec := corev1.PodSpec{
+		InitContainers:                initContainers,
 		Containers:                    containers,
 		Volumes:                       volumes,
 		ImagePullSecrets:              imagePullSecrets,
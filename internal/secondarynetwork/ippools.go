@@ -0,0 +1,151 @@
+/*
+Copyright (c) Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secondarynetwork
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+)
+
+const (
+	// ClusterIPAMKubeconfigPath is the kubeconfig written on the nodes by the CNI plugins pods,
+	// the amd-host-device CNI plugin leases the addresses of the NICIPPools with it
+	ClusterIPAMKubeconfigPath = "/etc/cni/net.d/amd-host-device.d/amd-host-device.kubeconfig"
+	// defaultRailPool is the key of the pool of the NICs not listed in the rails
+	defaultRailPool = "*"
+)
+
+var invalidPoolNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// UsesClusterIPAM returns true if a network attachment of the NetworkConfig leases its addresses from the NICIPPools
+func UsesClusterIPAM(nwConfig *v1alpha1.NetworkConfig) bool {
+	for _, attachment := range nwConfig.Spec.SecondaryNetwork.NetworkAttachments {
+		if attachment.IPAM.Mode == "cluster" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetIPPoolName returns the name of the NICIPPool of the rail of the network attachment,
+// an empty interface name returns the pool of the NICs not listed in the rails
+func GetIPPoolName(nwConfig *v1alpha1.NetworkConfig, attachmentName, iface string) string {
+	name := nwConfig.Name + "-" + attachmentName
+	if iface != "" {
+		name += "-" + iface
+	}
+	return strings.Trim(invalidPoolNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// GetDesiredIPPools returns the ranges of the NICIPPools of the network attachments using the cluster IPAM mode keyed by pool name
+func GetDesiredIPPools(nwConfig *v1alpha1.NetworkConfig) map[string]v1alpha1.NICIPPoolSpec {
+	pools := map[string]v1alpha1.NICIPPoolSpec{}
+	for _, attachment := range nwConfig.Spec.SecondaryNetwork.NetworkAttachments {
+		ipam := attachment.IPAM
+		if ipam.Mode != "cluster" {
+			continue
+		}
+		if ipam.Range != "" {
+			pools[GetIPPoolName(nwConfig, attachment.Name, "")] = v1alpha1.NICIPPoolSpec{
+				Range:      ipam.Range,
+				RangeStart: ipam.RangeStart,
+				RangeEnd:   ipam.RangeEnd,
+				Gateway:    ipam.Gateway,
+			}
+		}
+		for _, rail := range ipam.Rails {
+			pools[GetIPPoolName(nwConfig, attachment.Name, rail.Interface)] = v1alpha1.NICIPPoolSpec{
+				Range:      rail.Range,
+				RangeStart: rail.RangeStart,
+				RangeEnd:   rail.RangeEnd,
+				Gateway:    rail.Gateway,
+			}
+		}
+	}
+	return pools
+}
+
+// getClusterIPAMConfig renders the cluster IPAM config of the amd-host-device CNI plugin, the pools are keyed by host interface name
+func getClusterIPAMConfig(nwConfig *v1alpha1.NetworkConfig, spec v1alpha1.NetworkAttachmentSpec) map[string]interface{} {
+	pools := map[string]string{}
+	if spec.IPAM.Range != "" {
+		pools[defaultRailPool] = GetIPPoolName(nwConfig, spec.Name, "")
+	}
+	for _, rail := range spec.IPAM.Rails {
+		pools[rail.Interface] = GetIPPoolName(nwConfig, spec.Name, rail.Interface)
+	}
+	return map[string]interface{}{
+		"namespace":  nwConfig.Namespace,
+		"kubeconfig": ClusterIPAMKubeconfigPath,
+		"pools":      pools,
+	}
+}
+
+// SetIPPoolAsDesired sets the range of the NICIPPool, the leases outside of the new range are released
+// the leases within the range are owned by the CNI plugin and kept
+func SetIPPoolAsDesired(pool *v1alpha1.NICIPPool, spec v1alpha1.NICIPPoolSpec, nwConfig *v1alpha1.NetworkConfig) error {
+	if pool.Labels == nil {
+		pool.Labels = map[string]string{}
+	}
+	pool.Labels[utils.CRNameLabel] = nwConfig.Name
+	pool.Spec.Range = spec.Range
+	pool.Spec.RangeStart = spec.RangeStart
+	pool.Spec.RangeEnd = spec.RangeEnd
+	pool.Spec.Gateway = spec.Gateway
+	for ip := range pool.Spec.Allocations {
+		inRange, err := IsInIPPoolRange(pool.Spec, ip)
+		if err != nil {
+			return err
+		}
+		if !inRange {
+			delete(pool.Spec.Allocations, ip)
+		}
+	}
+	return nil
+}
+
+// IsInIPPoolRange returns true if the address can be leased from the pool
+func IsInIPPoolRange(spec v1alpha1.NICIPPoolSpec, address string) (bool, error) {
+	_, subnet, err := net.ParseCIDR(spec.Range)
+	if err != nil {
+		return false, fmt.Errorf("invalid range %s: %v", spec.Range, err)
+	}
+	ip := net.ParseIP(address)
+	if ip == nil || !subnet.Contains(ip) {
+		return false, nil
+	}
+	if start := net.ParseIP(spec.RangeStart); start != nil && ipToInt(ip).Cmp(ipToInt(start)) < 0 {
+		return false, nil
+	}
+	if end := net.ParseIP(spec.RangeEnd); end != nil && ipToInt(ip).Cmp(ipToInt(end)) > 0 {
+		return false, nil
+	}
+	return true, nil
+}
+
+func ipToInt(ip net.IP) *big.Int {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return new(big.Int).SetBytes(ip)
+}
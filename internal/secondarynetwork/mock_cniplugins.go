@@ -57,16 +57,16 @@ func (m *MockSecondaryNetworkAPI) EXPECT() *MockSecondaryNetworkAPIMockRecorder
 }
 
 // SetCNIPluginsAsDesired mocks base method.
-func (m *MockSecondaryNetworkAPI) SetCNIPluginsAsDesired(nwConfigName string, ds *v1.DaemonSet, cniPluginsSpec *v1alpha1.CniPluginsSpec, nodeSelector map[string]string) (*runtime.Scheme, error) {
+func (m *MockSecondaryNetworkAPI) SetCNIPluginsAsDesired(nwConfigName string, ds *v1.DaemonSet, cniPluginsSpec *v1alpha1.CniPluginsSpec, nodeSelector map[string]string, clusterIPAM bool) (*runtime.Scheme, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetCNIPluginsAsDesired", nwConfigName, ds, cniPluginsSpec, nodeSelector)
+	ret := m.ctrl.Call(m, "SetCNIPluginsAsDesired", nwConfigName, ds, cniPluginsSpec, nodeSelector, clusterIPAM)
 	ret0, _ := ret[0].(*runtime.Scheme)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SetCNIPluginsAsDesired indicates an expected call of SetCNIPluginsAsDesired.
-func (mr *MockSecondaryNetworkAPIMockRecorder) SetCNIPluginsAsDesired(nwConfigName, ds, cniPluginsSpec, nodeSelector any) *gomock.Call {
+func (mr *MockSecondaryNetworkAPIMockRecorder) SetCNIPluginsAsDesired(nwConfigName, ds, cniPluginsSpec, nodeSelector, clusterIPAM any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCNIPluginsAsDesired", reflect.TypeOf((*MockSecondaryNetworkAPI)(nil).SetCNIPluginsAsDesired), nwConfigName, ds, cniPluginsSpec, nodeSelector)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCNIPluginsAsDesired", reflect.TypeOf((*MockSecondaryNetworkAPI)(nil).SetCNIPluginsAsDesired), nwConfigName, ds, cniPluginsSpec, nodeSelector, clusterIPAM)
 }
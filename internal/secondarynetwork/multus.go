@@ -0,0 +1,251 @@
+/*
+Copyright (c) Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secondarynetwork
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+)
+
+const (
+	defaultMultusImage = "ghcr.io/k8snetworkplumbingwg/multus-cni:v4.2.2-thick"
+	// MultusName is the suffix of the Multus DaemonSet and daemon config names,
+	// it differs from the Multus DaemonSet of the helm chart so that both can't collide
+	MultusName = "multus-daemon"
+	// MultusConfigHashAnnotation is set on the Multus pod template to roll the pods when the daemon config changes
+	MultusConfigHashAnnotation = "network.operator.amd.com/multus-config-hash"
+
+	multusSAName             = "amd-network-operator-multus-daemon"
+	multusDaemonConfigKey    = "daemon-config.json"
+	multusDaemonConfigDir    = "/etc/cni/net.d/multus.d"
+	multusKubeconfigDir      = "/etc/multus/kubeconfig"
+	multusKubeconfigKey      = "kubeconfig"
+	multusCNIConfDir         = "/etc/cni/net.d"
+	multusCNIBinDir          = "/opt/cni/bin"
+	multusDefaultPriorityCls = "system-node-critical"
+)
+
+// IsMultusEnabled returns true if the operator deploys Multus, it never does on OpenShift where Multus is part of the cluster network
+func IsMultusEnabled(nwConfig *v1alpha1.NetworkConfig, isOpenShift bool) bool {
+	multus := nwConfig.Spec.SecondaryNetwork.Multus
+	return !isOpenShift && multus != nil && multus.Enable != nil && *multus.Enable
+}
+
+// GetMultusName returns the name of the Multus DaemonSet and daemon config
+func GetMultusName(nwConfig *v1alpha1.NetworkConfig) string {
+	return fmt.Sprintf("%s-%s", nwConfig.Name, MultusName)
+}
+
+// SetMultusConfigMapAsDesired renders the Multus daemon config and returns its hash to be set on the Multus pod template
+func SetMultusConfigMapAsDesired(cm *corev1.ConfigMap, nwConfig *v1alpha1.NetworkConfig) (string, error) {
+	config := map[string]interface{}{
+		// the delegate CNI plugins are executed in the host root
+		"chrootDir":           "/hostroot",
+		"cniVersion":          "0.3.1",
+		"logLevel":            "verbose",
+		"logToStderr":         true,
+		"cniConfigDir":        "/host" + multusCNIConfDir,
+		"multusAutoconfigDir": "/host" + multusCNIConfDir,
+		// generate the Multus config from the primary CNI config of the node
+		"multusConfigFile": "auto",
+		"socketDir":        "/host/run/multus/",
+	}
+	if nwConfig.Spec.SecondaryNetwork.Multus.KubeconfigSecret != nil {
+		config["kubeconfig"] = multusKubeconfigDir + "/" + multusKubeconfigKey
+	}
+	configBytes, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render the Multus daemon config: %v", err)
+	}
+	cm.Data = map[string]string{
+		multusDaemonConfigKey: string(configBytes),
+	}
+	hash := sha256.Sum256(configBytes)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// SetMultusAsDesired renders the Multus thick plugin DaemonSet, the shim installed on the nodes calls the Multus daemon of the node
+func SetMultusAsDesired(ds *appsv1.DaemonSet, nwConfig *v1alpha1.NetworkConfig, configHash string) error {
+	if ds == nil {
+		return fmt.Errorf("daemon set is not initialized, zero pointer")
+	}
+	spec := nwConfig.Spec.SecondaryNetwork.Multus
+
+	image := defaultMultusImage
+	if spec.Image != "" {
+		image = spec.Image
+	}
+
+	matchLabels := map[string]string{
+		"daemonset-name":         ds.Name,
+		"app.kubernetes.io/name": "multus",
+		utils.CRNameLabel:        nwConfig.Name,
+	}
+
+	nodeSelector := map[string]string{}
+	for key, val := range nwConfig.Spec.Selector {
+		nodeSelector[key] = val
+	}
+
+	hostToContainer := corev1.MountPropagationHostToContainer
+	bidirectional := corev1.MountPropagationBidirectional
+	volumeMounts := []corev1.VolumeMount{
+		{Name: "cni", MountPath: "/host" + multusCNIConfDir},
+		{Name: "cnibin", MountPath: "/host" + multusCNIBinDir},
+		{Name: "host-run", MountPath: "/host/run"},
+		{Name: "host-var-lib-cni-multus", MountPath: "/var/lib/cni/multus"},
+		{Name: "host-var-lib-kubelet", MountPath: "/var/lib/kubelet", MountPropagation: &hostToContainer},
+		{Name: "host-run-k8s-cni-cncf-io", MountPath: "/run/k8s.cni.cncf.io"},
+		{Name: "host-run-netns", MountPath: "/run/netns", MountPropagation: &hostToContainer},
+		{Name: "multus-daemon-config", MountPath: multusDaemonConfigDir, ReadOnly: true},
+		{Name: "hostroot", MountPath: "/hostroot", MountPropagation: &hostToContainer},
+	}
+	volumes := []corev1.Volume{
+		hostPathVolume("cni", multusCNIConfDir),
+		hostPathVolume("cnibin", multusCNIBinDir),
+		hostPathVolume("host-run", "/run"),
+		hostPathVolume("host-var-lib-cni-multus", "/var/lib/cni/multus"),
+		hostPathVolume("host-var-lib-kubelet", "/var/lib/kubelet"),
+		hostPathVolume("host-run-k8s-cni-cncf-io", "/run/k8s.cni.cncf.io"),
+		hostPathVolume("host-run-netns", "/run/netns/"),
+		hostPathVolume("hostroot", "/"),
+		{
+			Name: "multus-daemon-config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: GetMultusName(nwConfig)},
+					Items:                []corev1.KeyToPath{{Key: multusDaemonConfigKey, Path: multusDaemonConfigKey}},
+				},
+			},
+		},
+	}
+	if spec.KubeconfigSecret != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: "kubeconfig", MountPath: multusKubeconfigDir, ReadOnly: true})
+		volumes = append(volumes, corev1.Volume{
+			Name: "kubeconfig",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: spec.KubeconfigSecret.Name,
+					Items:      []corev1.KeyToPath{{Key: multusKubeconfigKey, Path: multusKubeconfigKey}},
+				},
+			},
+		})
+	}
+
+	initContainer := corev1.Container{
+		Name:    "install-multus-shim",
+		Image:   image,
+		Command: []string{"/install_multus", "--type", "thick"},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: ptr.To(true),
+		},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "cnibin", MountPath: "/host" + multusCNIBinDir, MountPropagation: &bidirectional},
+		},
+	}
+	container := corev1.Container{
+		Name:    "kube-multus",
+		Image:   image,
+		Command: []string{"/usr/src/multus-cni/bin/multus-daemon"},
+		Env: []corev1.EnvVar{
+			{
+				Name: "MULTUS_NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+				},
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: ptr.To(true),
+		},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		VolumeMounts:             volumeMounts,
+	}
+	if spec.ImagePullPolicy != "" {
+		initContainer.ImagePullPolicy = corev1.PullPolicy(spec.ImagePullPolicy)
+		container.ImagePullPolicy = corev1.PullPolicy(spec.ImagePullPolicy)
+	}
+
+	imagePullSecrets := []corev1.LocalObjectReference{}
+	if spec.ImageRegistrySecret != nil {
+		imagePullSecrets = append(imagePullSecrets, *spec.ImageRegistrySecret)
+	}
+
+	ds.Spec = appsv1.DaemonSetSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: matchLabels},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: matchLabels,
+				Annotations: map[string]string{
+					MultusConfigHashAnnotation: configHash,
+				},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers:                []corev1.Container{initContainer},
+				Containers:                    []corev1.Container{container},
+				HostNetwork:                   true,
+				HostPID:                       true,
+				ImagePullSecrets:              imagePullSecrets,
+				NodeSelector:                  nodeSelector,
+				PriorityClassName:             multusDefaultPriorityCls,
+				ServiceAccountName:            multusSAName,
+				TerminationGracePeriodSeconds: ptr.To(int64(10)),
+				Volumes:                       volumes,
+			},
+		},
+	}
+	if len(spec.Tolerations) > 0 {
+		ds.Spec.Template.Spec.Tolerations = spec.Tolerations
+	}
+	if spec.UpgradePolicy != nil {
+		up := spec.UpgradePolicy
+		upgradeStrategy := appsv1.RollingUpdateDaemonSetStrategyType
+		if up.UpgradeStrategy == "OnDelete" {
+			upgradeStrategy = appsv1.OnDeleteDaemonSetStrategyType
+		}
+		ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+			Type: upgradeStrategy,
+		}
+		if upgradeStrategy == appsv1.RollingUpdateDaemonSetStrategyType {
+			ds.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{
+				MaxUnavailable: &intstr.IntOrString{IntVal: int32(up.MaxUnavailable)},
+			}
+		}
+	}
+	return nil
+}
+
+func hostPathVolume(name, path string) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{Path: path},
+		},
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright (c) Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secondarynetwork
+
+import (
+	"encoding/json"
+	"fmt"
+
+	netattachdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+
+	"github.com/ROCm/network-operator/api/v1alpha1"
+	utils "github.com/ROCm/network-operator/internal"
+)
+
+const (
+	// NetworkAttachmentResourceNameAnnotation lets Multus request the NIC resource of the network for the attached pods
+	NetworkAttachmentResourceNameAnnotation = "k8s.v1.cni.cncf.io/resourceName"
+	// NetworkSelectionAnnotation lists the secondary networks a pod is attached to
+	NetworkSelectionAnnotation = "k8s.v1.cni.cncf.io/networks"
+
+	defaultNetworkAttachmentResource = "amd.com/nic"
+	networkAttachmentCNIVersion      = "0.3.1"
+	hostDevicePluginType             = "amd-host-device"
+)
+
+// GetNetworkAttachmentResourceName returns the NIC resource allocated to the pods attached to the network
+func GetNetworkAttachmentResourceName(spec v1alpha1.NetworkAttachmentSpec) string {
+	if spec.ResourceName != "" {
+		return spec.ResourceName
+	}
+	return defaultNetworkAttachmentResource
+}
+
+// GenerateNetworkAttachmentConfig renders the CNI network config list, amd-host-device followed by the chained plugins
+func GenerateNetworkAttachmentConfig(nwConfig *v1alpha1.NetworkConfig, spec v1alpha1.NetworkAttachmentSpec) (string, error) {
+	hostDevice := map[string]interface{}{
+		"type": hostDevicePluginType,
+	}
+	if ipam := getIPAMConfig(spec.IPAM); ipam != nil {
+		hostDevice["ipam"] = ipam
+	}
+	if spec.IPAM.Mode == "cluster" {
+		// amd-host-device leases the address from the NICIPPool of the rail of the NIC and passes it as static IPAM
+		hostDevice["clusterIPAM"] = getClusterIPAMConfig(nwConfig, spec)
+	}
+	if spec.IPAM.Mode == "static" {
+		// the static IPAM takes the addresses from the ips runtime config of the pod network selection annotation
+		hostDevice["capabilities"] = map[string]bool{"ips": true}
+	}
+
+	plugins := []interface{}{hostDevice}
+	for _, plugin := range spec.Plugins {
+		conf := map[string]interface{}{"type": plugin}
+		if plugin == "tuning" && spec.Tuning != nil {
+			if spec.Tuning.MTU != nil {
+				conf["mtu"] = *spec.Tuning.MTU
+			}
+			if len(spec.Tuning.Sysctls) > 0 {
+				conf["sysctl"] = spec.Tuning.Sysctls
+			}
+		}
+		plugins = append(plugins, conf)
+	}
+
+	// the map keys are sorted by the JSON encoder, the config is stable across reconciles
+	config, err := json.Marshal(map[string]interface{}{
+		"cniVersion": networkAttachmentCNIVersion,
+		"name":       spec.Name,
+		"plugins":    plugins,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render the CNI config of %s: %v", spec.Name, err)
+	}
+	return string(config), nil
+}
+
+func getIPAMConfig(ipam v1alpha1.NetworkAttachmentIPAMSpec) map[string]interface{} {
+	switch ipam.Mode {
+	case "host-local":
+		ipRange := map[string]interface{}{"subnet": ipam.Range}
+		if ipam.RangeStart != "" {
+			ipRange["rangeStart"] = ipam.RangeStart
+		}
+		if ipam.RangeEnd != "" {
+			ipRange["rangeEnd"] = ipam.RangeEnd
+		}
+		if ipam.Gateway != "" {
+			ipRange["gateway"] = ipam.Gateway
+		}
+		return map[string]interface{}{
+			"type":   "host-local",
+			"ranges": [][]interface{}{{ipRange}},
+		}
+	case "whereabouts":
+		conf := map[string]interface{}{
+			"type":  "whereabouts",
+			"range": ipam.Range,
+		}
+		if ipam.RangeStart != "" {
+			conf["range_start"] = ipam.RangeStart
+		}
+		if ipam.RangeEnd != "" {
+			conf["range_end"] = ipam.RangeEnd
+		}
+		if ipam.Gateway != "" {
+			conf["gateway"] = ipam.Gateway
+		}
+		return conf
+	case "static", "dhcp":
+		return map[string]interface{}{"type": ipam.Mode}
+	}
+	// none, the NIC keeps its addresses
+	// cluster, the addresses are leased by amd-host-device itself
+	return nil
+}
+
+// SetNetworkAttachmentAsDesired renders the NetworkAttachmentDefinition of the network attachment
+func SetNetworkAttachmentAsDesired(nad *netattachdefv1.NetworkAttachmentDefinition, spec v1alpha1.NetworkAttachmentSpec, nwConfig *v1alpha1.NetworkConfig) error {
+	config, err := GenerateNetworkAttachmentConfig(nwConfig, spec)
+	if err != nil {
+		return err
+	}
+	if nad.Labels == nil {
+		nad.Labels = map[string]string{}
+	}
+	// the NetworkAttachmentDefinitions can live in other namespaces, so they can't be owned by owner references
+	for key, val := range utils.GetOwnerLabels(nwConfig) {
+		nad.Labels[key] = val
+	}
+	if nad.Annotations == nil {
+		nad.Annotations = map[string]string{}
+	}
+	nad.Annotations[NetworkAttachmentResourceNameAnnotation] = GetNetworkAttachmentResourceName(spec)
+	nad.Spec.Config = config
+	return nil
+}
@@ -0,0 +1,43 @@
+#!/bin/sh
+
+# Write the kubeconfig used by the amd-host-device CNI plugin to lease the addresses of the NICIPPools
+# the service account token of the pod is rotated by the kubelet, the kubeconfig is rewritten periodically
+
+dir=/host/etc/cni/net.d/amd-host-device.d
+kubeconfig=${dir}/amd-host-device.kubeconfig
+sa=/var/run/secrets/kubernetes.io/serviceaccount
+
+host=${KUBERNETES_SERVICE_HOST}
+case "${host}" in
+    *:*) host="[${host}]" ;;
+esac
+
+write_kubeconfig() {
+    token=$(cat "${sa}/token")
+    ca=$(base64 "${sa}/ca.crt" | tr -d '\n')
+    umask 077
+    # JSON is valid YAML, the CNI plugin parses the kubeconfig without a YAML library
+    cat > "${kubeconfig}.tmp" <<KUBECONFIG
+{
+  "apiVersion": "v1",
+  "kind": "Config",
+  "clusters": [{"name": "local", "cluster": {"server": "https://${host}:${KUBERNETES_SERVICE_PORT}", "certificate-authority-data": "${ca}"}}],
+  "users": [{"name": "amd-host-device", "user": {"token": "${token}"}}],
+  "contexts": [{"name": "amd-host-device", "context": {"cluster": "local", "user": "amd-host-device"}}],
+  "current-context": "amd-host-device"
+}
+KUBECONFIG
+    mv -f "${kubeconfig}.tmp" "${kubeconfig}"
+    # the leases record the node name, the hostname can differ from it
+    echo "${NODE_NAME}" > "${dir}/node-name"
+}
+
+mkdir -p "${dir}"
+# the token is bound to this pod and rejected once the pod is deleted, so the kubeconfig is removed on termination
+# the plugin then fails ADD and DEL explicitly until the next CNI plugins pod of the node writes a new kubeconfig
+trap 'rm -f "${kubeconfig}" "${kubeconfig}.tmp"; exit 0' TERM INT
+while true; do
+    write_kubeconfig || echo "failed to write ${kubeconfig}"
+    sleep 300 &
+    wait $!
+done
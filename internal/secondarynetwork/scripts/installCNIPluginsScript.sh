@@ -0,0 +1,62 @@
+#!/bin/sh
+
+# Install the CNI plugins listed in CNI_PLUGINS from the image into the host CNI bin directory
+# prints one <plugin>=<status> pair per plugin into the termination message:
+#   installed  the host binary matches the image binary
+#   foreign    the host binary was installed by another operator and is left untouched
+#   missing    neither the image nor the host provides the plugin
+#   failed     the binary couldn't be copied
+
+src=/usr/src/cni/bin
+dst=/host/opt/cni/bin
+# checksums of the binaries installed by the operator, the binaries with another checksum are foreign
+owned=${dst}/.amd-network-operator
+mkdir -p "${owned}"
+
+checksum() {
+    sha256sum "$1" | cut -d' ' -f1
+}
+
+install_plugin() {
+    # copy then rename, so that the kubelet never executes a partially written binary
+    cp "${src}/$1" "${dst}/.$1.tmp" && chmod 755 "${dst}/.$1.tmp" && mv -f "${dst}/.$1.tmp" "${dst}/$1"
+}
+
+result=""
+rc=0
+for plugin in ${CNI_PLUGINS}; do
+    if [ ! -f "${src}/${plugin}" ]; then
+        # the plugins not shipped by the image can be provided by another operator
+        if [ -f "${dst}/${plugin}" ]; then
+            status=foreign
+        else
+            status=missing
+            rc=1
+        fi
+    else
+        want=$(checksum "${src}/${plugin}")
+        status=installed
+        if [ -f "${dst}/${plugin}" ]; then
+            have=$(checksum "${dst}/${plugin}")
+            if [ "${have}" != "${want}" ]; then
+                if [ "${have}" != "$(cat "${owned}/${plugin}" 2>/dev/null)" ] && [ "${OVERWRITE_FOREIGN_BINARIES}" != "true" ]; then
+                    status=foreign
+                elif ! install_plugin "${plugin}"; then
+                    status=failed
+                fi
+            fi
+        elif ! install_plugin "${plugin}"; then
+            status=failed
+        fi
+        if [ "${status}" = "installed" ]; then
+            echo "${want}" > "${owned}/${plugin}"
+        elif [ "${status}" = "failed" ]; then
+            rc=1
+        fi
+    fi
+    echo "${plugin}: ${status}"
+    result="${result}${plugin}=${status} "
+done
+
+echo "${result}" > /dev/termination-log
+exit ${rc}
@@ -0,0 +1,191 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// affinity of a NIC and a GPU, from the closest to the farthest
+const (
+	// AffinityPCIeSwitch means the NIC and the GPU share an upstream PCIe bridge below the host bridge
+	AffinityPCIeSwitch = "pcie-switch"
+	// AffinityNUMA means the NIC and the GPU are attached to the same NUMA node
+	AffinityNUMA = "numa"
+	// AffinityNone means the NIC and the GPU are not aligned
+	AffinityNone = "none"
+
+	deviceKindNIC = "nic"
+	deviceKindGPU = "gpu"
+)
+
+var affinityRank = map[string]int{
+	AffinityPCIeSwitch: 2,
+	AffinityNUMA:       1,
+	AffinityNone:       0,
+}
+
+// Device is a PCI device discovered on the node
+type Device struct {
+	// PCIAddress of the device, e.g. 0000:05:00.0
+	PCIAddress string `json:"pciAddress"`
+	// NUMANode of the device, -1 if unknown
+	NUMANode int `json:"numaNode"`
+	// PCIePath from the host bridge to the device, e.g. pci0000:00/0000:00:01.1/0000:01:00.0/0000:05:00.0
+	PCIePath string `json:"pciePath"`
+}
+
+// Pair is the NIC assigned to a GPU
+type Pair struct {
+	GPU      string `json:"gpu"`
+	NIC      string `json:"nic"`
+	Affinity string `json:"affinity"`
+}
+
+// NodeTopology is the NIC to GPU topology published for a node
+type NodeTopology struct {
+	// BootID of the node when the topology was discovered
+	BootID string   `json:"bootId"`
+	NICs   []Device `json:"nics"`
+	GPUs   []Device `json:"gpus"`
+	Pairs  []Pair   `json:"pairs"`
+	// Affinity every GPU of the node gets with its NIC
+	Affinity string `json:"affinity"`
+	// Message contains the discovery failure details
+	Message string `json:"message,omitempty"`
+}
+
+// GetTopologyConfigMapName returns the name of the ConfigMap publishing the NIC to GPU topology of the NetworkConfig nodes
+func GetTopologyConfigMapName(nwConfigName string) string {
+	return fmt.Sprintf("%s-nic-gpu-topology", nwConfigName)
+}
+
+// GetNodeTopology returns the topology published for the node, nil if not discovered yet
+func GetNodeTopology(cm *v1.ConfigMap, nodeName string) *NodeTopology {
+	value, ok := cm.Data[nodeName]
+	if !ok {
+		return nil
+	}
+	topo := &NodeTopology{}
+	if err := json.Unmarshal([]byte(value), topo); err != nil {
+		return nil
+	}
+	return topo
+}
+
+// ParseDevices parses the output of the topology discovery script,
+// one line per device: <nic|gpu> <pci address> <numa node> <pcie path>
+func ParseDevices(output string) (nics, gpus []Device, err error) {
+	nics, gpus = []Device{}, []Device{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, nil, fmt.Errorf("invalid device line %q", line)
+		}
+		numaNode, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid NUMA node in device line %q: %v", line, err)
+		}
+		device := Device{PCIAddress: fields[1], NUMANode: numaNode, PCIePath: fields[3]}
+		switch fields[0] {
+		case deviceKindNIC:
+			nics = append(nics, device)
+		case deviceKindGPU:
+			gpus = append(gpus, device)
+		default:
+			return nil, nil, fmt.Errorf("unknown device kind in device line %q", line)
+		}
+	}
+	return nics, gpus, nil
+}
+
+// NewNodeTopology pairs each GPU with its closest NIC
+// the NICs are assigned one to one as long as possible, then shared by the remaining GPUs
+func NewNodeTopology(bootID string, nics, gpus []Device) *NodeTopology {
+	topo := &NodeTopology{
+		BootID: bootID,
+		NICs:   nics,
+		GPUs:   gpus,
+		Pairs:  []Pair{},
+	}
+	pairs := make([]*Pair, len(gpus))
+	usedNICs := make([]bool, len(nics))
+	for _, affinity := range []string{AffinityPCIeSwitch, AffinityNUMA, AffinityNone} {
+		for i, gpu := range gpus {
+			if pairs[i] != nil {
+				continue
+			}
+			for j, nic := range nics {
+				if !usedNICs[j] && getAffinity(nic, gpu) == affinity {
+					pairs[i] = &Pair{GPU: gpu.PCIAddress, NIC: nic.PCIAddress, Affinity: affinity}
+					usedNICs[j] = true
+					break
+				}
+			}
+		}
+	}
+	for i, gpu := range gpus {
+		if pairs[i] != nil {
+			continue
+		}
+		// more GPUs than NICs, share the closest NIC
+		for _, nic := range nics {
+			affinity := getAffinity(nic, gpu)
+			if pairs[i] == nil || affinityRank[affinity] > affinityRank[pairs[i].Affinity] {
+				pairs[i] = &Pair{GPU: gpu.PCIAddress, NIC: nic.PCIAddress, Affinity: affinity}
+			}
+		}
+	}
+
+	topo.Affinity = AffinityNone
+	if len(gpus) > 0 && len(nics) > 0 {
+		topo.Affinity = AffinityPCIeSwitch
+	}
+	for _, pair := range pairs {
+		if pair == nil {
+			continue
+		}
+		topo.Pairs = append(topo.Pairs, *pair)
+		if affinityRank[pair.Affinity] < affinityRank[topo.Affinity] {
+			topo.Affinity = pair.Affinity
+		}
+	}
+	return topo
+}
+
+// getAffinity returns the affinity of the NIC and the GPU
+func getAffinity(nic, gpu Device) string {
+	// the last path element is the device itself, the host bridge is shared by all the devices of the root complex
+	nicBridges := strings.Split(nic.PCIePath, "/")
+	gpuBridges := strings.Split(gpu.PCIePath, "/")
+	if len(nicBridges) > 2 && len(gpuBridges) > 2 &&
+		nicBridges[0] == gpuBridges[0] && nicBridges[1] == gpuBridges[1] {
+		return AffinityPCIeSwitch
+	}
+	if nic.NUMANode >= 0 && nic.NUMANode == gpu.NUMANode {
+		return AffinityNUMA
+	}
+	return AffinityNone
+}
@@ -0,0 +1,54 @@
+/*
+Copyright (c) 2025 Advanced Micro Devices, Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the \"License\");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an \"AS IS\" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewNodeTopology", func() {
+	It("should pair each GPU with the closest NIC", func() {
+		nics, gpus, err := ParseDevices(`
+gpu 0000:05:00.0 0 pci0000:00/0000:00:01.1/0000:01:00.0/0000:02:00.0/0000:05:00.0
+nic 0000:06:00.0 0 pci0000:00/0000:00:01.1/0000:01:00.0/0000:02:01.0/0000:06:00.0
+gpu 0000:85:00.0 1 pci0000:80/0000:80:01.1/0000:81:00.0/0000:85:00.0
+nic 0000:91:00.0 1 pci0000:80/0000:80:03.1/0000:91:00.0
+`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nics).To(HaveLen(2))
+		Expect(gpus).To(HaveLen(2))
+
+		topo := NewNodeTopology("boot-id", nics, gpus)
+		Expect(topo.Pairs).To(Equal([]Pair{
+			{GPU: "0000:05:00.0", NIC: "0000:06:00.0", Affinity: AffinityPCIeSwitch},
+			{GPU: "0000:85:00.0", NIC: "0000:91:00.0", Affinity: AffinityNUMA},
+		}))
+		Expect(topo.Affinity).To(Equal(AffinityNUMA))
+
+		// more GPUs than NICs, the remaining GPU shares the closest NIC
+		topo = NewNodeTopology("boot-id", nics[:1], gpus)
+		Expect(topo.Pairs).To(Equal([]Pair{
+			{GPU: "0000:05:00.0", NIC: "0000:06:00.0", Affinity: AffinityPCIeSwitch},
+			{GPU: "0000:85:00.0", NIC: "0000:06:00.0", Affinity: AffinityNone},
+		}))
+		Expect(topo.Affinity).To(Equal(AffinityNone))
+
+		_, _, err = ParseDevices("fpga 0000:05:00.0 0 pci0000:00/0000:05:00.0")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -19,28 +19,30 @@ package utils
 import (
 	"context"
 	"fmt"
-	"strings"
+	"slices"
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
 	"github.com/ROCm/network-operator/internal/cmd"
 )
 
 const (
-	defaultOcDriversVersion     = "1.117.1-a-42"
-	defaultUbuntuDriversVersion = "1.117.1-a-42"
-	openShiftNodeLabel          = "node.openshift.io/os_id"
-	NodeFeatureLabelAmdNic      = "feature.node.kubernetes.io/amd-nic"
-	NodeFeatureLabelAmdVNic     = "feature.node.kubernetes.io/amd-vnic"
-	ResourceNamingStrategyFlag  = "resource_naming_strategy"
-	SingleStrategy              = "single"
-	MixedStrategy               = "mixed"
-	DefaultUtilsImage           = "docker.io/rocm/network-operator-utils:v1.2.0"
+	openShiftNodeLabel         = "node.openshift.io/os_id"
+	NodeFeatureLabelAmdNic     = "feature.node.kubernetes.io/amd-nic"
+	NodeFeatureLabelAmdVNic    = "feature.node.kubernetes.io/amd-vnic"
+	ResourceNamingStrategyFlag = "resource_naming_strategy"
+	LogLevelFlag               = "log_level"
+	ResourcePrefixFlag         = "resource_prefix"
+	HealthCheckIntervalFlag    = "health_check_interval"
+	KubeletSocketFlag          = "kubelet_socket"
+	GPUAffinityFlag            = "gpu_affinity"
+	SingleStrategy             = "single"
+	MixedStrategy              = "mixed"
+	DefaultUtilsImage          = "docker.io/rocm/network-operator-utils:v1.2.0"
 
 	// worker pod related constants
 	KindNetworkConfig      = "NetworkConfig"
@@ -48,57 +50,22 @@ const (
 	UndoAction             = "undo"
 	WorkerActionLabelKey   = "network.operator.amd.com/worker-action"
 	WorkReadyLabelTemplate = "network.operator.amd.com/%v.%v.work.ready"
-)
 
-func GetDriverVersion(node v1.Node, networkConfig amdv1alpha1.NetworkConfig) (string, error) {
-	var driverVersion string
-	var err error
-	if networkConfig.Spec.Driver.Version != "" {
-		driverVersion = networkConfig.Spec.Driver.Version
-	} else {
-		defaultDriverVersion, err := GetDefaultDriversVersion(node)
-		if err == nil {
-			driverVersion = defaultDriverVersion
-		}
-	}
-	return driverVersion, err
-}
+	// NetworkReadyLabelKey mirrors the AMDNetworkReady node condition as a node label, so that it can be used in node selectors
+	NetworkReadyLabelKey = "network.operator.amd.com/network-ready"
 
-func GetDefaultDriversVersion(node v1.Node) (string, error) {
-	osImageStr := strings.ToLower(node.Status.NodeInfo.OSImage)
-	for os, mapper := range defaultDriverversionsMappers {
-		if strings.Contains(osImageStr, os) {
-			return mapper(osImageStr)
-		}
-	}
-	return "", fmt.Errorf("OS: %s not supported", osImageStr)
-}
+	// kmod signature verification related constants
+	VerifyKmodSignatureAction       = "verify-kmod-signature"
+	KmodSignatureAnnotationTemplate = "network.operator.amd.com/%v.%v.kmod-signature"
 
-var defaultDriverversionsMappers = map[string]func(fullImageStr string) (string, error){
-	"ubuntu": UbuntuDefaultDriverVersionsMapper,
-	"rhel": func(f string) (string, error) {
-		return defaultOcDriversVersion, nil
-	},
-	"redhat": func(f string) (string, error) {
-		return defaultOcDriversVersion, nil
-	},
-	"red hat": func(f string) (string, error) {
-		return defaultOcDriversVersion, nil
-	},
-}
+	// NIC to GPU topology discovery related constants
+	DiscoverTopologyAction = "discover-topology"
+	// NICGPUAffinityLabelKey is the affinity every GPU on the node can get with a NIC: pcie-switch, numa or none
+	NICGPUAffinityLabelKey = "network.operator.amd.com/nic-gpu-affinity"
 
-func UbuntuDefaultDriverVersionsMapper(fullImageStr string) (string, error) {
-	if strings.Contains(fullImageStr, "20.04") {
-		return defaultUbuntuDriversVersion, nil
-	}
-	if strings.Contains(fullImageStr, "22.04") {
-		return defaultUbuntuDriversVersion, nil
-	}
-	if strings.Contains(fullImageStr, "24.04") {
-		return defaultUbuntuDriversVersion, nil
-	}
-	return "", fmt.Errorf("invalid ubuntu version, should be one of [20.04, 22.04]")
-}
+	// NICUnhealthyTaintKey is the key of the taint applied on the nodes remediated for unhealthy NICs
+	NICUnhealthyTaintKey = "amd-network-nic-unhealthy"
+)
 
 func HasNodeLabelKey(node v1.Node, labelKey string) bool {
 	for k := range node.Labels {
@@ -109,23 +76,41 @@ func HasNodeLabelKey(node v1.Node, labelKey string) bool {
 	return false
 }
 
+// WithNICUnhealthyToleration returns the tolerations with the one of the NIC unhealthy taint appended,
+// the operands reporting the NIC health have to keep running on the remediated nodes to clear the taint
+func WithNICUnhealthyToleration(tolerations []v1.Toleration) []v1.Toleration {
+	return append(slices.Clone(tolerations), v1.Toleration{
+		Key:      NICUnhealthyTaintKey,
+		Operator: v1.TolerationOpExists,
+	})
+}
+
 func GetUpgradeWorkerPodName(networkConfig *amdv1alpha1.NetworkConfig, nodeName string) string {
 	return fmt.Sprintf("worker-%v-%v", networkConfig.Name, nodeName)
 }
 
-func IsOpenShift(logger logr.Logger) bool {
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		cmd.FatalError(logger, err, "unable to get cluster config")
-	}
-	// creates the clientset
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		cmd.FatalError(logger, err, "unable to create cluster clientset")
+func GetKmodSignatureVerifierPodName(networkConfig *amdv1alpha1.NetworkConfig, nodeName string) string {
+	return fmt.Sprintf("kmod-sig-%v-%v", networkConfig.Name, nodeName)
+}
+
+// GetOwnerLabels returns the labels tracking the objects of the NetworkConfig which can't be owned by owner references,
+// e.g. cluster scoped objects or objects in other namespaces
+func GetOwnerLabels(nwConfig *amdv1alpha1.NetworkConfig) map[string]string {
+	return map[string]string{
+		CRNameLabel:      nwConfig.Name,
+		CRNamespaceLabel: nwConfig.Namespace,
 	}
-	// Check for OpenShift-specific labels on nodes
-	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
+}
+
+func GetTopologyDiscoveryPodName(networkConfig *amdv1alpha1.NetworkConfig, nodeName string) string {
+	return fmt.Sprintf("topology-%v-%v", networkConfig.Name, nodeName)
+}
+
+// IsOpenShift checks if the operator is running on OpenShift cluster by looking for OpenShift-specific labels on nodes
+// the reader must be usable before the manager cache starts, e.g. the manager's API reader
+func IsOpenShift(logger logr.Logger, reader client.Reader) bool {
+	nodes := v1.NodeList{}
+	if err := reader.List(context.TODO(), &nodes); err != nil {
 		cmd.FatalError(logger, err, "unable to list nodes")
 	}
 
@@ -150,3 +135,151 @@ func IsPrometheusServiceMonitorEnable(nwConfig *amdv1alpha1.NetworkConfig) bool
 	}
 	return false
 }
+
+// GetPodCustomization merges the operand pod customization over the common pod customization of the NetworkConfig
+func GetPodCustomization(nwConfig *amdv1alpha1.NetworkConfig, operand *amdv1alpha1.PodCustomizationOverrideSpec) *amdv1alpha1.PodCustomizationSpec {
+	merged := &amdv1alpha1.PodCustomizationSpec{}
+	if common := nwConfig.Spec.CommonConfig.PodCustomization; common != nil {
+		merged = common.DeepCopy()
+	}
+	if operand == nil {
+		return merged
+	}
+	operand = operand.DeepCopy()
+	if operand.Resources != nil {
+		merged.Resources = operand.Resources
+	}
+	if operand.PriorityClassName != "" {
+		merged.PriorityClassName = operand.PriorityClassName
+	}
+	merged.Env = mergeEnv(merged.Env, operand.Env)
+	for k, v := range operand.Annotations {
+		if merged.Annotations == nil {
+			merged.Annotations = map[string]string{}
+		}
+		merged.Annotations[k] = v
+	}
+	return merged
+}
+
+// ApplyPodCustomization applies the pod customization on top of the pod rendered by the operator
+// the resources are set on the main container only, the node affinity is merged with the rendered one
+// and is not applied to the pods bound to a node
+func ApplyPodCustomization(meta *metav1.ObjectMeta, spec *v1.PodSpec, custom *amdv1alpha1.PodCustomizationSpec) {
+	if custom == nil {
+		return
+	}
+	for k, v := range custom.Annotations {
+		if meta.Annotations == nil {
+			meta.Annotations = map[string]string{}
+		}
+		meta.Annotations[k] = v
+	}
+	if custom.PriorityClassName != "" {
+		spec.PriorityClassName = custom.PriorityClassName
+	}
+	if custom.SecurityContext != nil {
+		spec.SecurityContext = custom.SecurityContext.DeepCopy()
+	}
+	if custom.NodeAffinity != nil && spec.NodeName == "" {
+		if spec.Affinity == nil {
+			spec.Affinity = &v1.Affinity{}
+		}
+		spec.Affinity.NodeAffinity = mergeNodeAffinity(spec.Affinity.NodeAffinity, custom.NodeAffinity)
+	}
+	if custom.Resources != nil && len(spec.Containers) > 0 {
+		spec.Containers[0].Resources = *custom.Resources.DeepCopy()
+	}
+	for _, containers := range [][]v1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			containers[i].Env = mergeEnv(containers[i].Env, custom.Env)
+		}
+	}
+}
+
+// mergeNodeAffinity returns the node affinity satisfying both the rendered and the custom node affinity
+// the required terms are combined so that a node has to match a term of each, the preferred terms are appended
+func mergeNodeAffinity(rendered, custom *v1.NodeAffinity) *v1.NodeAffinity {
+	if rendered == nil {
+		return custom.DeepCopy()
+	}
+	merged := rendered.DeepCopy()
+	if required := custom.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+		if merged.RequiredDuringSchedulingIgnoredDuringExecution == nil || len(merged.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+			merged.RequiredDuringSchedulingIgnoredDuringExecution = required.DeepCopy()
+		} else {
+			terms := []v1.NodeSelectorTerm{}
+			for _, r := range merged.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+				for _, c := range required.NodeSelectorTerms {
+					terms = append(terms, v1.NodeSelectorTerm{
+						MatchExpressions: slices.Concat(r.MatchExpressions, c.MatchExpressions),
+						MatchFields:      slices.Concat(r.MatchFields, c.MatchFields),
+					})
+				}
+			}
+			merged.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = terms
+		}
+	}
+	for _, preferred := range custom.PreferredDuringSchedulingIgnoredDuringExecution {
+		merged.PreferredDuringSchedulingIgnoredDuringExecution = append(merged.PreferredDuringSchedulingIgnoredDuringExecution, *preferred.DeepCopy())
+	}
+	return merged
+}
+
+// mergeEnv returns env with the variables of override replacing the ones of the same name
+func mergeEnv(env, override []v1.EnvVar) []v1.EnvVar {
+	if len(override) == 0 {
+		return env
+	}
+	env = slices.Clone(env)
+	for _, o := range override {
+		idx := slices.IndexFunc(env, func(e v1.EnvVar) bool { return e.Name == o.Name })
+		if idx < 0 {
+			env = append(env, *o.DeepCopy())
+		} else {
+			env[idx] = *o.DeepCopy()
+		}
+	}
+	return env
+}
+
+// IsPrometheusRuleEnable checks if the PrometheusRule is enabled in the NetworkConfig
+func IsPrometheusRuleEnable(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	prometheus := nwConfig.Spec.MetricsExporter.Prometheus
+	return prometheus != nil && prometheus.Rules != nil &&
+		prometheus.Rules.Enable != nil && *prometheus.Rules.Enable
+}
+
+// IsOTLPEnable checks if the metrics are pushed over OTLP in the NetworkConfig
+func IsOTLPEnable(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	otlp := nwConfig.Spec.MetricsExporter.OTLP
+	return otlp != nil && otlp.Enable != nil && *otlp.Enable
+}
+
+// IsMetricsServiceEnable checks if the metrics exporter Service is created, it is optional when the metrics are pushed over OTLP
+func IsMetricsServiceEnable(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	if !IsOTLPEnable(nwConfig) {
+		return true
+	}
+	keep := nwConfig.Spec.MetricsExporter.OTLP.KeepService
+	return keep != nil && *keep
+}
+
+// IsKubeRbacAutoTLSEnable checks if the kube-rbac-proxy certificates are issued by the operator in the NetworkConfig
+func IsKubeRbacAutoTLSEnable(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	autoTLS := nwConfig.Spec.MetricsExporter.RbacConfig.AutoTLS
+	return autoTLS != nil && autoTLS.Enable != nil && *autoTLS.Enable
+}
+
+// IsGrafanaDashboardEnable checks if the Grafana dashboard ConfigMap is enabled in the NetworkConfig
+func IsGrafanaDashboardEnable(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	prometheus := nwConfig.Spec.MetricsExporter.Prometheus
+	return prometheus != nil && prometheus.GrafanaDashboard != nil &&
+		prometheus.GrafanaDashboard.Enable != nil && *prometheus.GrafanaDashboard.Enable
+}
+
+// IsRemediationEnable checks if the remediation of the nodes with unhealthy NICs is enabled in the NetworkConfig
+func IsRemediationEnable(nwConfig *amdv1alpha1.NetworkConfig) bool {
+	enable := nwConfig.Spec.Remediation.Enable
+	return enable != nil && *enable
+}
@@ -19,9 +19,23 @@ package validator
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
 
 	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
 	utils "github.com/ROCm/network-operator/internal"
+	dpinternal "github.com/ROCm/network-operator/internal/deviceplugin"
+	drainternal "github.com/ROCm/network-operator/internal/dra"
+	hostconfiginternal "github.com/ROCm/network-operator/internal/hostconfig"
+	"github.com/ROCm/network-operator/internal/kmmmodule"
+	expinternal "github.com/ROCm/network-operator/internal/metricsexporter"
+	nlinternal "github.com/ROCm/network-operator/internal/nodelabeller"
+	netattachdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
+	resourcev1beta1 "k8s.io/api/resource/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -51,6 +65,46 @@ func ValidateDriverSpec(ctx context.Context, client client.Client, nwConfig *amd
 		}
 	}
 
+	if dSpec.OSProfiles != nil {
+		if err := validateConfigMap(ctx, client, dSpec.OSProfiles.Name, nwConfig.Namespace); err != nil {
+			return fmt.Errorf("OSProfiles: %v", err)
+		}
+		if _, err := kmmmodule.LoadOSProfiles(ctx, client, nwConfig); err != nil {
+			return fmt.Errorf("OSProfiles: %v", err)
+		}
+	}
+
+	for _, dockerfile := range dSpec.ImageBuild.Dockerfiles {
+		if err := validateConfigMap(ctx, client, dockerfile.ConfigMap.Name, nwConfig.Namespace); err != nil {
+			return fmt.Errorf("ImageBuild Dockerfiles %s: %v", dockerfile.OS, err)
+		}
+	}
+
+	for _, secret := range slices.Concat(dSpec.ImageBuild.BuildSecrets, dSpec.ImageBuild.CABundleSecrets) {
+		if err := validateSecret(ctx, client, &secret, nwConfig.Namespace); err != nil {
+			return fmt.Errorf("ImageBuild secret: %v", err)
+		}
+	}
+
+	reservedBuildArgs := kmmmodule.GetReservedBuildArgs()
+	for _, arg := range dSpec.ImageBuild.BuildArgs {
+		if slices.Contains(reservedBuildArgs, arg.Name) {
+			return fmt.Errorf("ImageBuild BuildArgs: %s is managed by the operator, build args %v cannot be overridden", arg.Name, reservedBuildArgs)
+		}
+	}
+
+	if dSpec.DevBuild != nil {
+		if err := validateDevBuild(dSpec.DevBuild); err != nil {
+			return fmt.Errorf("DevBuild: %v", err)
+		}
+	}
+
+	for _, mapping := range dSpec.KernelMappings {
+		if _, err := regexp.Compile(mapping.Regexp); err != nil {
+			return fmt.Errorf("KernelMappings: invalid regexp %s: %v", mapping.Regexp, err)
+		}
+	}
+
 	return nil
 }
 
@@ -74,6 +128,23 @@ func ValidateMetricsExporterSpec(ctx context.Context, client client.Client, nwCo
 		}
 	}
 
+	if err := expinternal.ValidateExporterOptions(nwConfig); err != nil {
+		return err
+	}
+
+	if err := expinternal.ValidateOTLPConfig(nwConfig); err != nil {
+		return err
+	}
+
+	if err := expinternal.ValidateAutoTLSConfig(nwConfig); err != nil {
+		return err
+	}
+	if utils.IsKubeRbacAutoTLSEnable(nwConfig) && expinternal.IsCertManagerProvider(nwConfig) {
+		if err := validateCertManagerCRD(ctx, client); err != nil {
+			return fmt.Errorf("RbacConfig: %v", err)
+		}
+	}
+
 	// Validate ServiceMonitor CRD availability if ServiceMonitor is enabled
 	if utils.IsPrometheusServiceMonitorEnable(nwConfig) {
 		if err := validateServiceMonitorCRD(ctx, client); err != nil {
@@ -81,6 +152,27 @@ func ValidateMetricsExporterSpec(ctx context.Context, client client.Client, nwCo
 		}
 	}
 
+	if utils.IsPrometheusRuleEnable(nwConfig) {
+		if err := validatePrometheusRuleCRD(ctx, client); err != nil {
+			return fmt.Errorf("PrometheusRule: %v", err)
+		}
+		if linkDown := mSpec.Prometheus.Rules.LinkDown; linkDown != nil && linkDown.Ports != "" {
+			if _, err := regexp.Compile(linkDown.Ports); err != nil {
+				return fmt.Errorf("PrometheusRule: invalid linkDown ports regexp %s: %v", linkDown.Ports, err)
+			}
+		}
+	}
+
+	if utils.IsGrafanaDashboardEnable(nwConfig) {
+		dashboardURL := mSpec.Prometheus.GrafanaDashboard.URL
+		if dashboardURL == "" {
+			return fmt.Errorf("GrafanaDashboard: url of the AINIC dashboard is required")
+		}
+		if u, err := url.Parse(dashboardURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("GrafanaDashboard: invalid url %s, an http or https URL is required", dashboardURL)
+		}
+	}
+
 	return nil
 }
 
@@ -94,29 +186,235 @@ func ValidateDevicePluginSpec(ctx context.Context, client client.Client, nwConfi
 		}
 	}
 
-	supportedFlagValues := map[string][]string{
-		utils.ResourceNamingStrategyFlag: {utils.SingleStrategy, utils.MixedStrategy},
+	if err := dpinternal.ValidateDevicePluginFlags(nwConfig); err != nil {
+		return err
+	}
+
+	for _, pool := range dSpec.ResourcePools {
+		prefix := pool.Prefix
+		if prefix == "" {
+			prefix = dpinternal.GetResourcePrefix(nwConfig)
+		}
+		resourceName := prefix + "/" + pool.Name
+		if errs := validation.IsQualifiedName(resourceName); len(errs) > 0 {
+			return fmt.Errorf("ResourcePools: invalid resource name %s: %v", resourceName, errs)
+		}
+	}
+
+	return nil
+}
+
+// NodeLabellerSpec validation
+func ValidateNodeLabellerSpec(ctx context.Context, client client.Client, nwConfig *amdv1alpha1.NetworkConfig) error {
+	nSpec := nwConfig.Spec.NodeLabeller
+
+	if !nlinternal.IsNodeLabellerEnabled(nwConfig) {
+		return nil
+	}
+
+	if nSpec.ImageRegistrySecret != nil {
+		if err := validateSecret(ctx, client, nSpec.ImageRegistrySecret, nwConfig.Namespace); err != nil {
+			return fmt.Errorf("ImageRegistrySecret: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// DRADriverSpec validation
+func ValidateDRADriverSpec(ctx context.Context, client client.Client, nwConfig *amdv1alpha1.NetworkConfig) error {
+	dSpec := nwConfig.Spec.DRADriver
+
+	if !drainternal.IsDRADriverEnabled(nwConfig) {
+		return nil
+	}
+
+	if dSpec.ImageRegistrySecret != nil {
+		if err := validateSecret(ctx, client, dSpec.ImageRegistrySecret, nwConfig.Namespace); err != nil {
+			return fmt.Errorf("ImageRegistrySecret: %v", err)
+		}
+	}
+
+	gvk := resourcev1beta1.SchemeGroupVersion.WithKind("DeviceClass")
+	if _, err := client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		return fmt.Errorf("DRA API %s is not available in the cluster, it requires Kubernetes v1.32 or later with the DynamicResourceAllocation feature enabled: %v", resourcev1beta1.SchemeGroupVersion, err)
 	}
 
-	devicePluginArguments := nwConfig.Spec.DevicePlugin.DevicePluginArguments
-	for key, val := range devicePluginArguments {
-		validValues, validKey := supportedFlagValues[key]
-		if !validKey {
-			return fmt.Errorf("Invalid flag: %s", key)
+	for _, class := range dSpec.DeviceClasses {
+		if errs := validation.IsDNS1123Subdomain(class.Name); len(errs) > 0 {
+			return fmt.Errorf("DeviceClasses: invalid name %s: %v", class.Name, errs)
 		}
-		validKeyValue := false
+	}
 
-		for _, validVal := range validValues {
-			if val == validVal {
-				validKeyValue = true
-				break
+	railPFs := map[string]string{}
+	for _, rail := range dSpec.Rails {
+		for _, pfName := range rail.PFNames {
+			if other, ok := railPFs[pfName]; ok {
+				return fmt.Errorf("Rails: %s is in both rails %s and %s", pfName, other, rail.Name)
 			}
+			railPFs[pfName] = rail.Name
 		}
+	}
 
-		if !validKeyValue {
-			return fmt.Errorf("Invalid flag value: %s=%s. Supported values: %v", key, val, supportedFlagValues[key])
+	return nil
+}
+
+// sysctlKeyRegex matches the dotted sysctl keys written to the sysctl.d config
+var sysctlKeyRegex = regexp.MustCompile(`^[a-z0-9_]+(\.[a-zA-Z0-9_-]+)+$`)
+
+// HostConfigSpec validation
+func ValidateHostConfigSpec(ctx context.Context, client client.Client, nwConfig *amdv1alpha1.NetworkConfig) error {
+	hSpec := nwConfig.Spec.HostConfig
+
+	if !hostconfiginternal.IsHostConfigEnabled(nwConfig) {
+		return nil
+	}
+
+	if hSpec.ImageRegistrySecret != nil {
+		if err := validateSecret(ctx, client, hSpec.ImageRegistrySecret, nwConfig.Namespace); err != nil {
+			return fmt.Errorf("ImageRegistrySecret: %v", err)
+		}
+	}
+
+	modules := map[string]bool{}
+	for _, opts := range hSpec.ModprobeOptions {
+		if modules[opts.Module] {
+			return fmt.Errorf("ModprobeOptions: module %s is configured more than once", opts.Module)
+		}
+		modules[opts.Module] = true
+	}
+
+	for key, val := range hSpec.Sysctls {
+		if !sysctlKeyRegex.MatchString(key) {
+			return fmt.Errorf("Sysctls: invalid key %s", key)
+		}
+		if val == "" || strings.ContainsAny(val, "\n\r") {
+			return fmt.Errorf("Sysctls: invalid value %q for %s", val, key)
+		}
+	}
+
+	return nil
+}
+
+// RemediationSpec validation
+func ValidateRemediationSpec(ctx context.Context, client client.Client, nwConfig *amdv1alpha1.NetworkConfig) error {
+	if !utils.IsRemediationEnable(nwConfig) {
+		return nil
+	}
+
+	// the unhealthy NICs are the ones the device plugin withdrew from the allocatable resources
+	if enable := nwConfig.Spec.DevicePlugin.EnableDevicePlugin; enable != nil && !*enable {
+		return fmt.Errorf("the remediation requires the device plugin to report the NIC health")
+	}
+
+	return nil
+}
+
+// SecondaryNetworkSpec validation
+func ValidateSecondaryNetworkSpec(ctx context.Context, client client.Client, nwConfig *amdv1alpha1.NetworkConfig) error {
+	sSpec := nwConfig.Spec.SecondaryNetwork
+	multusEnabled := sSpec.Multus != nil && sSpec.Multus.Enable != nil && *sSpec.Multus.Enable
+
+	if multusEnabled {
+		if sSpec.Multus.KubeconfigSecret != nil {
+			if err := validateSecret(ctx, client, sSpec.Multus.KubeconfigSecret, nwConfig.Namespace); err != nil {
+				return fmt.Errorf("Multus: KubeconfigSecret: %v", err)
+			}
+		}
+		if sSpec.Multus.ImageRegistrySecret != nil {
+			if err := validateSecret(ctx, client, sSpec.Multus.ImageRegistrySecret, nwConfig.Namespace); err != nil {
+				return fmt.Errorf("Multus: ImageRegistrySecret: %v", err)
+			}
 		}
 	}
 
+	if len(sSpec.NetworkAttachments) == 0 && !multusEnabled {
+		return nil
+	}
+
+	// Multus deployed by the operator still relies on the NetworkAttachmentDefinition CRD installed in the cluster
+	gvk := netattachdefv1.SchemeGroupVersion.WithKind("NetworkAttachmentDefinition")
+	if _, err := client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		return fmt.Errorf("NetworkAttachmentDefinition API %s is not available in the cluster, please install Multus or its NetworkAttachmentDefinition CRD: %v", netattachdefv1.SchemeGroupVersion, err)
+	}
+
+	nads := map[string]bool{}
+	for _, attachment := range sSpec.NetworkAttachments {
+		for _, namespace := range attachment.Namespaces {
+			if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+				return fmt.Errorf("NetworkAttachments: invalid namespace %s of %s: %v", namespace, attachment.Name, errs)
+			}
+			key := namespace + "/" + attachment.Name
+			if nads[key] {
+				return fmt.Errorf("NetworkAttachments: %s is defined more than once", key)
+			}
+			nads[key] = true
+		}
+		if err := validateNetworkAttachmentIPAM(attachment.IPAM); err != nil {
+			return fmt.Errorf("NetworkAttachments: %s: %v", attachment.Name, err)
+		}
+		if attachment.IPAM.Mode == "cluster" && (sSpec.CniPlugins == nil || sSpec.CniPlugins.Enable == nil || !*sSpec.CniPlugins.Enable) {
+			// the CNI plugins pods write the kubeconfig the amd-host-device CNI plugin leases the addresses with
+			return fmt.Errorf("NetworkAttachments: %s: the cluster IPAM requires the CNI plugins to be enabled", attachment.Name)
+		}
+		if attachment.Tuning != nil && !slices.Contains(attachment.Plugins, "tuning") {
+			return fmt.Errorf("NetworkAttachments: %s: tuning is set without the tuning plugin", attachment.Name)
+		}
+	}
+
+	return nil
+}
+
+func validateNetworkAttachmentIPAM(ipam amdv1alpha1.NetworkAttachmentIPAMSpec) error {
+	if len(ipam.Rails) > 0 && ipam.Mode != "cluster" {
+		return fmt.Errorf("IPAM rails are not used by %s", ipam.Mode)
+	}
+	switch ipam.Mode {
+	case "host-local", "whereabouts":
+		if ipam.Range == "" {
+			return fmt.Errorf("IPAM range is required by %s", ipam.Mode)
+		}
+	case "cluster":
+		if ipam.Range == "" && len(ipam.Rails) == 0 {
+			return fmt.Errorf("IPAM range or rails are required by %s", ipam.Mode)
+		}
+		interfaces := map[string]bool{}
+		for _, rail := range ipam.Rails {
+			if interfaces[rail.Interface] {
+				return fmt.Errorf("IPAM rail %s is defined more than once", rail.Interface)
+			}
+			interfaces[rail.Interface] = true
+			if err := validateIPAMRange(rail.Range, rail.RangeStart, rail.RangeEnd, rail.Gateway); err != nil {
+				return fmt.Errorf("IPAM rail %s: %v", rail.Interface, err)
+			}
+		}
+		if ipam.Range == "" {
+			if ipam.RangeStart != "" || ipam.RangeEnd != "" || ipam.Gateway != "" {
+				return fmt.Errorf("IPAM range is required by rangeStart, rangeEnd and gateway")
+			}
+			return nil
+		}
+	default:
+		if ipam.Range != "" || ipam.RangeStart != "" || ipam.RangeEnd != "" || ipam.Gateway != "" {
+			return fmt.Errorf("IPAM range is not used by %s", ipam.Mode)
+		}
+		return nil
+	}
+	return validateIPAMRange(ipam.Range, ipam.RangeStart, ipam.RangeEnd, ipam.Gateway)
+}
+
+func validateIPAMRange(ipRange, rangeStart, rangeEnd, gateway string) error {
+	_, subnet, err := net.ParseCIDR(ipRange)
+	if err != nil {
+		return fmt.Errorf("invalid IPAM range %s: %v", ipRange, err)
+	}
+	for name, addr := range map[string]string{"rangeStart": rangeStart, "rangeEnd": rangeEnd, "gateway": gateway} {
+		if addr == "" {
+			continue
+		}
+		if ip := net.ParseIP(addr); ip == nil || !subnet.Contains(ip) {
+			return fmt.Errorf("IPAM %s %s is not an address of %s", name, addr, ipRange)
+		}
+	}
 	return nil
 }
@@ -19,7 +19,10 @@ package validator
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
 
+	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,6 +34,8 @@ const (
 	ServiceMonitorCRDName    = "servicemonitors.monitoring.coreos.com"
 	ServiceMonitorCRDGroup   = "monitoring.coreos.com"
 	ServiceMonitorCRDVersion = "v1"
+	PrometheusRuleCRDName    = "prometheusrules.monitoring.coreos.com"
+	CertificateCRDName       = "certificates.cert-manager.io"
 )
 
 func validateSecret(ctx context.Context, client client.Client, secretRef *v1.LocalObjectReference, namespace string) error {
@@ -67,18 +72,47 @@ func validateConfigMap(ctx context.Context, client client.Client, mapRef string,
 	return nil
 }
 
+func validateDevBuild(devBuild *amdv1alpha1.DevBuildSpec) error {
+	installerURL, err := url.Parse(devBuild.InstallerPackageURL)
+	if err != nil {
+		return fmt.Errorf("invalid installer package URL %s: %v", devBuild.InstallerPackageURL, err)
+	}
+	if installerURL.Scheme != "http" && installerURL.Scheme != "https" {
+		return fmt.Errorf("installer package URL %s must use http or https", devBuild.InstallerPackageURL)
+	}
+	if !strings.HasSuffix(installerURL.Path, ".deb") {
+		return fmt.Errorf("installer package URL %s must point to a debian package", devBuild.InstallerPackageURL)
+	}
+	if devBuild.IonicBuild == "" {
+		return fmt.Errorf("ionic build number is required")
+	}
+	if devBuild.ROCmBuild == "" {
+		return fmt.Errorf("ROCm build tag is required")
+	}
+	return nil
+}
+
 // validateServiceMonitorCRD checks if the ServiceMonitor CRD is available in the cluster
 func validateServiceMonitorCRD(ctx context.Context, c client.Client) error {
-	// Define the ServiceMonitor CRD we want to check
+	return validatePrometheusOperatorCRD(ctx, c, ServiceMonitorCRDName, "ServiceMonitor")
+}
+
+// validatePrometheusRuleCRD checks if the PrometheusRule CRD is available in the cluster
+func validatePrometheusRuleCRD(ctx context.Context, c client.Client) error {
+	return validatePrometheusOperatorCRD(ctx, c, PrometheusRuleCRDName, "PrometheusRule")
+}
+
+// validatePrometheusOperatorCRD checks if a CRD of the Prometheus Operator is available in the cluster
+func validatePrometheusOperatorCRD(ctx context.Context, c client.Client, crdName, kind string) error {
 	crd := &apiextensionsv1.CustomResourceDefinition{}
-	err := c.Get(ctx, client.ObjectKey{Name: ServiceMonitorCRDName}, crd)
+	err := c.Get(ctx, client.ObjectKey{Name: crdName}, crd)
 	if err != nil {
-		return fmt.Errorf("ServiceMonitor CRD is not available in the cluster. Please ensure the Prometheus Operator is installed: %v", err)
+		return fmt.Errorf("%s CRD is not available in the cluster. Please ensure the Prometheus Operator is installed: %v", kind, err)
 	}
 
 	// Check if the CRD is in the correct group
 	if crd.Spec.Group != ServiceMonitorCRDGroup {
-		return fmt.Errorf("ServiceMonitor CRD group mismatch. Expected %s, got %s", ServiceMonitorCRDGroup, crd.Spec.Group)
+		return fmt.Errorf("%s CRD group mismatch. Expected %s, got %s", kind, ServiceMonitorCRDGroup, crd.Spec.Group)
 	}
 
 	found := false
@@ -91,7 +125,21 @@ func validateServiceMonitorCRD(ctx context.Context, c client.Client) error {
 	}
 
 	if !found {
-		return fmt.Errorf("ServiceMonitor CRD does not support version %s", ServiceMonitorCRDVersion)
+		return fmt.Errorf("%s CRD does not support version %s", kind, ServiceMonitorCRDVersion)
 	}
 	return nil
 }
+
+// validateCertManagerCRD checks if the cert-manager Certificate CRD is available in the cluster
+func validateCertManagerCRD(ctx context.Context, c client.Client) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := c.Get(ctx, client.ObjectKey{Name: CertificateCRDName}, crd); err != nil {
+		return fmt.Errorf("Certificate CRD is not available in the cluster. Please ensure cert-manager is installed: %v", err)
+	}
+	for _, version := range crd.Spec.Versions {
+		if version.Name == "v1" && version.Served {
+			return nil
+		}
+	}
+	return fmt.Errorf("Certificate CRD does not support version v1")
+}
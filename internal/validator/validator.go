@@ -37,9 +37,14 @@ type validator struct {
 func NewValidator() ValidatorAPI {
 	// Map of spec names to their respective validation functions
 	specValidationFuncs := map[string]func(context.Context, client.Client, *amdv1alpha1.NetworkConfig) error{
-		"driver":          ValidateDriverSpec,
-		"metricsExporter": ValidateMetricsExporterSpec,
-		"devicePlugin":    ValidateDevicePluginSpec,
+		"driver":           ValidateDriverSpec,
+		"metricsExporter":  ValidateMetricsExporterSpec,
+		"devicePlugin":     ValidateDevicePluginSpec,
+		"nodeLabeller":     ValidateNodeLabellerSpec,
+		"draDriver":        ValidateDRADriverSpec,
+		"hostConfig":       ValidateHostConfigSpec,
+		"secondaryNetwork": ValidateSecondaryNetworkSpec,
+		"remediation":      ValidateRemediationSpec,
 	}
 	vInst := &validator{
 		specValidationFuncs: specValidationFuncs,
@@ -70,6 +70,20 @@ func (mr *MockWorkerMgrAPIMockRecorder) AddWorkReadyLabel(ctx, logger, nsn, pod
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWorkReadyLabel", reflect.TypeOf((*MockWorkerMgrAPI)(nil).AddWorkReadyLabel), ctx, logger, nsn, pod)
 }
 
+// DiscoverTopology mocks base method.
+func (m *MockWorkerMgrAPI) DiscoverTopology(ctx context.Context, networkConfig *v1alpha1.NetworkConfig, node *v1.Node) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscoverTopology", ctx, networkConfig, node)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DiscoverTopology indicates an expected call of DiscoverTopology.
+func (mr *MockWorkerMgrAPIMockRecorder) DiscoverTopology(ctx, networkConfig, node any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscoverTopology", reflect.TypeOf((*MockWorkerMgrAPI)(nil).DiscoverTopology), ctx, networkConfig, node)
+}
+
 // GetWorkReadyLabel mocks base method.
 func (m *MockWorkerMgrAPI) GetWorkReadyLabel(nsn types.NamespacedName) string {
 	m.ctrl.T.Helper()
@@ -111,6 +125,30 @@ func (mr *MockWorkerMgrAPIMockRecorder) RemoveWorkReadyLabel(ctx, logger, nsn, p
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWorkReadyLabel", reflect.TypeOf((*MockWorkerMgrAPI)(nil).RemoveWorkReadyLabel), ctx, logger, nsn, pod)
 }
 
+// SetKmodSignatureResult mocks base method.
+func (m *MockWorkerMgrAPI) SetKmodSignatureResult(ctx context.Context, logger logr.Logger, nsn types.NamespacedName, pod *v1.Pod) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetKmodSignatureResult", ctx, logger, nsn, pod)
+}
+
+// SetKmodSignatureResult indicates an expected call of SetKmodSignatureResult.
+func (mr *MockWorkerMgrAPIMockRecorder) SetKmodSignatureResult(ctx, logger, nsn, pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKmodSignatureResult", reflect.TypeOf((*MockWorkerMgrAPI)(nil).SetKmodSignatureResult), ctx, logger, nsn, pod)
+}
+
+// SetTopologyResult mocks base method.
+func (m *MockWorkerMgrAPI) SetTopologyResult(ctx context.Context, logger logr.Logger, nsn types.NamespacedName, pod *v1.Pod) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTopologyResult", ctx, logger, nsn, pod)
+}
+
+// SetTopologyResult indicates an expected call of SetTopologyResult.
+func (mr *MockWorkerMgrAPIMockRecorder) SetTopologyResult(ctx, logger, nsn, pod any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTopologyResult", reflect.TypeOf((*MockWorkerMgrAPI)(nil).SetTopologyResult), ctx, logger, nsn, pod)
+}
+
 // Undo mocks base method.
 func (m *MockWorkerMgrAPI) Undo(ctx context.Context, networkConfig *v1alpha1.NetworkConfig, node *v1.Node) error {
 	m.ctrl.T.Helper()
@@ -125,6 +163,20 @@ func (mr *MockWorkerMgrAPIMockRecorder) Undo(ctx, networkConfig, node any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undo", reflect.TypeOf((*MockWorkerMgrAPI)(nil).Undo), ctx, networkConfig, node)
 }
 
+// VerifyKmodSignature mocks base method.
+func (m *MockWorkerMgrAPI) VerifyKmodSignature(ctx context.Context, networkConfig *v1alpha1.NetworkConfig, node *v1.Node, containerImage, certSKID string, kmods []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyKmodSignature", ctx, networkConfig, node, containerImage, certSKID, kmods)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyKmodSignature indicates an expected call of VerifyKmodSignature.
+func (mr *MockWorkerMgrAPIMockRecorder) VerifyKmodSignature(ctx, networkConfig, node, containerImage, certSKID, kmods any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyKmodSignature", reflect.TypeOf((*MockWorkerMgrAPI)(nil).VerifyKmodSignature), ctx, networkConfig, node, containerImage, certSKID, kmods)
+}
+
 // Work mocks base method.
 func (m *MockWorkerMgrAPI) Work(ctx context.Context, networkConfig *v1alpha1.NetworkConfig, node *v1.Node) error {
 	m.ctrl.T.Helper()
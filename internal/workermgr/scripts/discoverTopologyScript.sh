@@ -0,0 +1,30 @@
+#!/bin/bash
+
+# Discover the PCIe topology of the AMD NICs and GPUs
+# prints one line per physical function: <nic|gpu> <pci address> <numa node> <pcie path>
+
+devices=()
+for dev in /sys/bus/pci/devices/*; do
+    # skip the virtual functions, they share the topology of their physical function
+    if [ -e "${dev}/physfn" ]; then
+        continue
+    fi
+    vendor=$(cat "${dev}/vendor")
+    class=$(cat "${dev}/class")
+    kind=""
+    if [ "${vendor}" == "0x1dd8" ] && [[ "${class}" == 0x02* ]]; then
+        kind="nic"
+    # display controllers and processing accelerators
+    elif [ "${vendor}" == "0x1002" ] && { [[ "${class}" == 0x03* ]] || [[ "${class}" == 0x12* ]]; }; then
+        kind="gpu"
+    fi
+    if [ -z "${kind}" ]; then
+        continue
+    fi
+    numa=$(cat "${dev}/numa_node" 2>/dev/null || echo -1)
+    path=$(readlink -f "${dev}")
+    devices+=("${kind} $(basename "${dev}") ${numa} ${path#/sys/devices/}")
+done
+
+printf "%s\n" "${devices[@]}" | tee /dev/termination-log
+exit 0
@@ -0,0 +1,53 @@
+#!/bin/bash
+
+# Verify that the loaded kernel modules are signed by the configured signing key
+# KMODS: space separated names of the kernel modules to verify
+# CERT_SKID: subject key identifier of the signing certificate in lower case hex
+# KMODS_DIR: directory holding the kernel modules of the loaded driver image
+
+failures=()
+
+# normalize a key identifier to lower case hex without separators, modinfo prints it as upper case colon separated bytes
+normalize_key_id() {
+    echo "$1" | tr -d ':[:space:]' | tr '[:upper:]' '[:lower:]'
+}
+
+expected_key=$(normalize_key_id "${CERT_SKID}")
+
+# the signing key must be enrolled in one of the kernel keyrings, e.g. via MOK
+if ! grep -qi "${expected_key}" /proc/keys; then
+    failures+=("signing key ${CERT_SKID} is not enrolled in the kernel keyrings")
+fi
+
+for kmod in ${KMODS}; do
+    if [ ! -d "/sys/module/${kmod}" ]; then
+        failures+=("${kmod} is not loaded")
+        continue
+    fi
+    # the kernel taints itself with E when a module without a valid signature was loaded
+    if grep -q "E" "/sys/module/${kmod}/taint" 2>/dev/null; then
+        failures+=("${kmod} is loaded without a valid signature")
+        continue
+    fi
+    # the module must be signed by the configured key, not only by a key trusted by the kernel
+    kmod_file=$(find "${KMODS_DIR}/$(uname -r)" -name "${kmod}.ko" -o -name "${kmod}.ko.*" 2>/dev/null | head -n 1)
+    if [ -z "${kmod_file}" ]; then
+        failures+=("${kmod} is not found in the driver image")
+        continue
+    fi
+    sig_key=$(normalize_key_id "$(modinfo -F sig_key "${kmod_file}" 2>/dev/null)")
+    if [ -z "${sig_key}" ]; then
+        failures+=("${kmod} is not signed")
+    elif [ "${sig_key}" != "${expected_key}" ]; then
+        failures+=("${kmod} is signed by key ${sig_key} instead of ${expected_key}")
+    fi
+done
+
+if [ ${#failures[@]} -gt 0 ]; then
+    message=$(IFS=';'; echo "${failures[*]}")
+    echo "${message}" | tee /dev/termination-log
+    exit 1
+fi
+
+echo "kernel modules ${KMODS} are loaded and signed by key ${CERT_SKID}" | tee /dev/termination-log
+exit 0
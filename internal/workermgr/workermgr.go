@@ -21,9 +21,11 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -34,10 +36,15 @@ import (
 
 	amdv1alpha1 "github.com/ROCm/network-operator/api/v1alpha1"
 	utils "github.com/ROCm/network-operator/internal"
+	"github.com/ROCm/network-operator/internal/topology"
 )
 
 const (
-	workerContainerName = "worker"
+	workerContainerName                   = "worker"
+	workerBootIDAnnotation                = "network.operator.amd.com/boot-id"
+	kmodSignatureContainerImageAnnotation = "network.operator.amd.com/container-image"
+	kmodSignatureVolumeName               = "kmods"
+	kmodSignatureMountPath                = "/kmods"
 )
 
 var (
@@ -45,8 +52,24 @@ var (
 	workScript string
 	//go:embed scripts/undoScript.sh
 	undoScript string
+	//go:embed scripts/verifyKmodSignatureScript.sh
+	verifyKmodSignatureScript string
+	//go:embed scripts/discoverTopologyScript.sh
+	discoverTopologyScript string
 )
 
+// KmodSignatureResult is the result of the kmod signature verification on a node
+type KmodSignatureResult struct {
+	// BootID of the node when the verification was done
+	BootID string `json:"bootId"`
+	// ContainerImage of the driver image loaded when the verification was done
+	ContainerImage string `json:"containerImage"`
+	// Verified is true if all the kernel modules are loaded and signed by the configured key
+	Verified bool `json:"verified"`
+	// Message contains the verification details
+	Message string `json:"message,omitempty"`
+}
+
 //go:generate mockgen -source=workermgr.go -package=workermgr -destination=mock_workermgr.go WorkerMgrAPI
 type WorkerMgrAPI interface {
 	// Work executes the work on given node via worker pod
@@ -61,6 +84,14 @@ type WorkerMgrAPI interface {
 	GetWorkReadyLabel(nsn types.NamespacedName) string
 	// Remove the node label that indicates the work is completed
 	RemoveWorkReadyLabel(ctx context.Context, logger logr.Logger, nsn types.NamespacedName, pod *v1.Pod)
+	// VerifyKmodSignature verifies via verifier pod that the loaded kernel modules on given node are signed by the given key
+	VerifyKmodSignature(ctx context.Context, networkConfig *amdv1alpha1.NetworkConfig, node *v1.Node, containerImage, certSKID string, kmods []string) error
+	// SetKmodSignatureResult saves the result of a completed verifier pod on its node
+	SetKmodSignatureResult(ctx context.Context, logger logr.Logger, nsn types.NamespacedName, pod *v1.Pod)
+	// DiscoverTopology discovers via discovery pod the PCIe topology of the NICs and GPUs on given node
+	DiscoverTopology(ctx context.Context, networkConfig *amdv1alpha1.NetworkConfig, node *v1.Node) error
+	// SetTopologyResult publishes the NIC to GPU topology of a completed discovery pod and labels its node
+	SetTopologyResult(ctx context.Context, logger logr.Logger, nsn types.NamespacedName, pod *v1.Pod)
 }
 
 type workerMgr struct {
@@ -143,6 +174,194 @@ func (w *workerMgr) RemoveWorkReadyLabel(ctx context.Context, logger logr.Logger
 	w.patchNode(ctx, patch, &node, logger)
 }
 
+// VerifyKmodSignature verifies via verifier pod that the loaded kernel modules on given node are signed by the given key
+func (w *workerMgr) VerifyKmodSignature(ctx context.Context, networkConfig *amdv1alpha1.NetworkConfig, node *v1.Node, containerImage, certSKID string, kmods []string) error {
+	logger := log.FromContext(ctx)
+	verifier := w.getPodDef(networkConfig, node.Name, utils.VerifyKmodSignatureAction)
+	if err := w.client.Get(ctx, client.ObjectKeyFromObject(verifier), &v1.Pod{}); err == nil {
+		// verification is already in progress
+		return nil
+	} else if !k8serrors.IsNotFound(err) {
+		return err
+	}
+	verifier.Annotations = map[string]string{
+		workerBootIDAnnotation:                node.Status.NodeInfo.BootID,
+		kmodSignatureContainerImageAnnotation: containerImage,
+	}
+	// the loaded modules are read from the driver image, as KMM loads them from the image instead of the host
+	verifier.Spec.Volumes = append(verifier.Spec.Volumes, v1.Volume{
+		Name:         kmodSignatureVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+	kmodsMount := v1.VolumeMount{Name: kmodSignatureVolumeName, MountPath: kmodSignatureMountPath}
+	verifier.Spec.InitContainers = append(verifier.Spec.InitContainers, v1.Container{
+		Name:         kmodSignatureVolumeName,
+		Image:        containerImage,
+		Command:      []string{"/bin/sh", "-c", "cp -r /opt/lib/modules/. " + kmodSignatureMountPath},
+		VolumeMounts: []v1.VolumeMount{kmodsMount},
+	})
+	if networkConfig.Spec.Driver.ImageRegistrySecret != nil {
+		verifier.Spec.ImagePullSecrets = append(verifier.Spec.ImagePullSecrets, *networkConfig.Spec.Driver.ImageRegistrySecret)
+	}
+	verifier.Spec.Containers[0].VolumeMounts = append(verifier.Spec.Containers[0].VolumeMounts, kmodsMount)
+	verifier.Spec.Containers[0].Env = []v1.EnvVar{
+		{
+			Name:  "KMODS",
+			Value: strings.Join(kmods, " "),
+		},
+		{
+			Name:  "CERT_SKID",
+			Value: certSKID,
+		},
+		{
+			Name:  "KMODS_DIR",
+			Value: kmodSignatureMountPath,
+		},
+	}
+	if err := controllerutil.SetControllerReference(networkConfig, verifier, w.scheme); err != nil {
+		return err
+	}
+	if err := w.client.Create(ctx, verifier); err != nil {
+		return err
+	}
+	logger.Info("Created kmod signature verifier", "name", verifier.Name, "node", node.Name)
+	return nil
+}
+
+// SetKmodSignatureResult saves the result of a completed verifier pod on its node
+func (w *workerMgr) SetKmodSignatureResult(ctx context.Context, logger logr.Logger, nsn types.NamespacedName, pod *v1.Pod) {
+	node := v1.Node{}
+	err := w.client.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, &node)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("failed to get node resource %+v", pod.Spec.NodeName))
+		return
+	}
+	result := KmodSignatureResult{
+		BootID:         pod.Annotations[workerBootIDAnnotation],
+		ContainerImage: pod.Annotations[kmodSignatureContainerImageAnnotation],
+		Verified:       pod.Status.Phase == v1.PodSucceeded,
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.State.Terminated != nil {
+			result.Message = strings.TrimSpace(containerStatus.State.Terminated.Message)
+		}
+	}
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to marshal kmod signature result: %+v", err))
+		return
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				fmt.Sprintf(utils.KmodSignatureAnnotationTemplate, nsn.Namespace, nsn.Name): string(resultBytes),
+			},
+		},
+	}
+	w.patchNode(ctx, patch, &node, logger)
+}
+
+// GetKmodSignatureResult returns the kmod signature verification result saved on the node, nil if not verified yet
+func GetKmodSignatureResult(node *v1.Node, nsn types.NamespacedName) *KmodSignatureResult {
+	value, ok := node.Annotations[fmt.Sprintf(utils.KmodSignatureAnnotationTemplate, nsn.Namespace, nsn.Name)]
+	if !ok {
+		return nil
+	}
+	result := &KmodSignatureResult{}
+	if err := json.Unmarshal([]byte(value), result); err != nil {
+		return nil
+	}
+	return result
+}
+
+// DiscoverTopology discovers via discovery pod the PCIe topology of the NICs and GPUs on given node
+func (w *workerMgr) DiscoverTopology(ctx context.Context, networkConfig *amdv1alpha1.NetworkConfig, node *v1.Node) error {
+	logger := log.FromContext(ctx)
+	discoverer := w.getPodDef(networkConfig, node.Name, utils.DiscoverTopologyAction)
+	if err := w.client.Get(ctx, client.ObjectKeyFromObject(discoverer), &v1.Pod{}); err == nil {
+		// discovery is already in progress
+		return nil
+	} else if !k8serrors.IsNotFound(err) {
+		return err
+	}
+	discoverer.Annotations = map[string]string{
+		workerBootIDAnnotation: node.Status.NodeInfo.BootID,
+	}
+	if err := controllerutil.SetControllerReference(networkConfig, discoverer, w.scheme); err != nil {
+		return err
+	}
+	if err := w.client.Create(ctx, discoverer); err != nil {
+		return err
+	}
+	logger.Info("Created topology discoverer", "name", discoverer.Name, "node", node.Name)
+	return nil
+}
+
+// SetTopologyResult publishes the NIC to GPU topology of a completed discovery pod and labels its node
+func (w *workerMgr) SetTopologyResult(ctx context.Context, logger logr.Logger, nsn types.NamespacedName, pod *v1.Pod) {
+	node := v1.Node{}
+	err := w.client.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, &node)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("failed to get node resource %+v", pod.Spec.NodeName))
+		return
+	}
+	var output string
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.State.Terminated != nil {
+			output = containerStatus.State.Terminated.Message
+		}
+	}
+	bootID := pod.Annotations[workerBootIDAnnotation]
+	// the failed discovery is published as well, so that it is not retried until the node reboots
+	topo := topology.NewNodeTopology(bootID, []topology.Device{}, []topology.Device{})
+	if pod.Status.Phase != v1.PodSucceeded {
+		topo.Message = strings.TrimSpace(output)
+	} else if nics, gpus, err := topology.ParseDevices(output); err != nil {
+		topo.Message = err.Error()
+	} else {
+		topo = topology.NewNodeTopology(bootID, nics, gpus)
+	}
+	topoBytes, err := json.Marshal(topo)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to marshal node topology: %+v", err))
+		return
+	}
+
+	cm := v1.ConfigMap{}
+	cmName := types.NamespacedName{Namespace: nsn.Namespace, Name: topology.GetTopologyConfigMapName(nsn.Name)}
+	if err := w.client.Get(ctx, cmName, &cm); err != nil {
+		logger.Error(err, fmt.Sprintf("failed to get topology configmap %+v", cmName))
+		return
+	}
+	cmPatch, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{
+			node.Name: string(topoBytes),
+		},
+	})
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to marshal topology configmap patch: %+v", err))
+		return
+	}
+	if err := w.client.Patch(ctx, &cm, client.RawPatch(types.MergePatchType, cmPatch)); err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to patch topology configmap %+v", cmName))
+		return
+	}
+
+	// only label the nodes with GPUs, the affinity is meaningless otherwise
+	var label interface{}
+	if len(topo.GPUs) > 0 {
+		label = topo.Affinity
+	}
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				utils.NICGPUAffinityLabelKey: label,
+			},
+		},
+	}
+	w.patchNode(ctx, patch, &node, logger)
+}
+
 func (w *workerMgr) patchNode(ctx context.Context, patch map[string]interface{}, node *v1.Node, logger logr.Logger) {
 	patchBytes, err := json.Marshal(patch)
 	if err != nil {
@@ -181,11 +400,21 @@ func (w *workerMgr) getPodDef(networkConfig *amdv1alpha1.NetworkConfig, nodeName
 	}
 	// container command
 	var command []string
+	restartPolicy := v1.RestartPolicyOnFailure
 	switch action {
 	case utils.WorkAction:
 		command = []string{"/bin/bash", "-c", workScript}
 	case utils.UndoAction:
 		command = []string{"/bin/bash", "-c", undoScript}
+	case utils.VerifyKmodSignatureAction:
+		podName = utils.GetKmodSignatureVerifierPodName(networkConfig, nodeName)
+		command = []string{"/bin/bash", "-c", verifyKmodSignatureScript}
+		// the verification result is collected from the completed pod, no matter succeeded or failed
+		restartPolicy = v1.RestartPolicyNever
+	case utils.DiscoverTopologyAction:
+		podName = utils.GetTopologyDiscoveryPodName(networkConfig, nodeName)
+		command = []string{"/bin/bash", "-c", discoverTopologyScript}
+		restartPolicy = v1.RestartPolicyNever
 	}
 
 	// mount necessary folders
@@ -264,7 +493,7 @@ func (w *workerMgr) getPodDef(networkConfig *amdv1alpha1.NetworkConfig, nodeName
 			// the unload pod is always restarted until the amdgpu is unloaded
 			// even if the worker node somehow rebooted during the unload
 			// after reboot, the unload pod will be restarted again
-			RestartPolicy: v1.RestartPolicyOnFailure,
+			RestartPolicy: restartPolicy,
 			Volumes:       volumes,
 		},
 	}
@@ -279,6 +508,8 @@ func (w *workerMgr) getPodDef(networkConfig *amdv1alpha1.NetworkConfig, nodeName
 			*networkConfig.Spec.CommonConfig.UtilsContainer.ImageRegistrySecret,
 		}
 	}
+	utils.ApplyPodCustomization(&worker.ObjectMeta, &worker.Spec,
+		utils.GetPodCustomization(networkConfig, networkConfig.Spec.CommonConfig.UtilsContainer.PodCustomization))
 
 	return worker
 }
@@ -38,6 +38,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/ROCm/common-infra-operator/pkg/metricsexporter"
@@ -451,11 +452,11 @@ func (s *E2ESuite) verifyNICNLLablelsNotPresent(nc *v1alpha1.NetworkConfig, c *C
 // -----------------------------------------------------------------------------
 func (s *E2ESuite) verifySelectorFunctionalityForDaemonSet(appLabel map[string]string, nodeLabel map[string]string, c *C) {
 	pods, _ := s.k8sClientSet.CoreV1().Pods(s.ns).List(context.TODO(), metav1.ListOptions{
-		LabelSelector: kmmmodule.MapToLabelSelector(appLabel),
+		LabelSelector: labels.SelectorFromSet(appLabel).String(),
 	})
 
 	nodes, _ := s.k8sClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
-		LabelSelector: kmmmodule.MapToLabelSelector(nodeLabel),
+		LabelSelector: labels.SelectorFromSet(nodeLabel).String(),
 	})
 	assert.True(c, len(nodes.Items) > 0, "no nodes matching selector", len(nodes.Items))
 	assert.True(c, len(pods.Items) > 0, "no pods found for label", len(pods.Items))
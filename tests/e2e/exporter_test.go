@@ -25,13 +25,13 @@ import (
 	"github.com/ROCm/common-infra-operator/pkg/metricsexporter"
 	"github.com/ROCm/network-operator/api/v1alpha1"
 	"github.com/ROCm/network-operator/internal/conditions"
-	"github.com/ROCm/network-operator/internal/kmmmodule"
 	"github.com/ROCm/network-operator/tests/e2e/utils"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/stretchr/testify/assert"
 	. "gopkg.in/check.v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/ptr"
 )
 
@@ -81,7 +81,7 @@ func (s *E2ESuite) TestExporterBasic(c *C) {
 
 	// Explicitly specify node for exporter and verify scheduling
 	nodes, _ := s.k8sClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
-		LabelSelector: kmmmodule.MapToLabelSelector(netCfg.Spec.Selector),
+		LabelSelector: labels.SelectorFromSet(netCfg.Spec.Selector).String(),
 	})
 	assert.True(c, len(nodes.Items) > 0, "no nodes matching selector", len(nodes.Items))
 	logger.Infof("selecting selector to %s=%s", "kubernetes.io/hostname", nodes.Items[0].Name)
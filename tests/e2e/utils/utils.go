@@ -31,7 +31,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/ROCm/network-operator/internal/kmmmodule"
 	netattachdefv1 "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	netclientset "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/client/clientset/versioned"
 	appsv1 "k8s.io/api/apps/v1"
@@ -255,7 +254,7 @@ func DeployPodWithNICResource(ctx context.Context, cl *kubernetes.Clientset, res
 	}
 	if err := Retry(func() error {
 		pods, err := cl.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-			LabelSelector: kmmmodule.MapToLabelSelector(ainicLabel),
+			LabelSelector: labels.SelectorFromSet(ainicLabel).String(),
 		})
 		if err != nil {
 			return fmt.Errorf("list pods: %w", err)
@@ -281,7 +280,7 @@ func DeletePodWithNICResource(ctx context.Context, cl *kubernetes.Clientset) err
 	}
 	if err := Retry(func() error {
 		pods, err := cl.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-			LabelSelector: kmmmodule.MapToLabelSelector(ainicLabel),
+			LabelSelector: labels.SelectorFromSet(ainicLabel).String(),
 		})
 		if err != nil {
 			return fmt.Errorf("list pods: %w", err)
@@ -298,7 +297,7 @@ func DeletePodWithNICResource(ctx context.Context, cl *kubernetes.Clientset) err
 
 func ListAinicPods(ctx context.Context, cl *kubernetes.Clientset) ([]string, error) {
 	pods, err := cl.CoreV1().Pods("").List(ctx, metav1.ListOptions{
-		LabelSelector: kmmmodule.MapToLabelSelector(ainicLabel),
+		LabelSelector: labels.SelectorFromSet(ainicLabel).String(),
 	})
 	if err != nil {
 		return nil, err